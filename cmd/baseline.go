@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/baseline"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Freeze approved specs and report changes since",
+	Long: fmt.Sprintf(`Baselines freeze a set of requirements, e.g. the ones signed off for a
+release, in %s. Each baseline stores the normalized specs with a
+content hash per requirement and per example, so later changes can be listed
+with "baseline compare" and flagged in "trace --baseline".`, config.DefaultBaselineDir),
+}
+
+var baselineCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Freeze the current specs as a baseline",
+	Long: `Freeze the current specs as baseline <name> (e.g. v1.0). Use --status to
+only include approved requirements and --namespace to freeze one namespace.
+Excluded statuses (e.g. deprecated) are left out unless listed in --status.
+
+An existing baseline is only replaced with --force.`,
+	Args: cobra.ExactArgs(1),
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("baseline.create")
+
+		name := args[0]
+		if err := baseline.ValidateName(name); err != nil {
+			return err
+		}
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+		if err != nil {
+			return err
+		}
+		statuses, _ := cmd.Flags().GetStringSlice("status")
+		specs, _, err = filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+		if err != nil {
+			return err
+		}
+
+		b := baseline.New(name, specs)
+		b.Namespace = spec.CleanNamespace(namespaceFilter)
+		b.Statuses = statuses
+		force, _ := cmd.Flags().GetBool("force")
+		if err := baseline.Save(config.DefaultBaselineDir, b, force); err != nil {
+			log.Error("Failed to save baseline", "name", name, "error", err)
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "created baseline %s with %d requirement(s): %s\n", name, len(b.Requirements), baseline.Path(config.DefaultBaselineDir, name))
+		return nil
+	}),
+}
+
+var baselineCompareCmd = &cobra.Command{
+	Use:   "compare <name>",
+	Short: "List requirements and examples changed since a baseline",
+	Long: `List the requirements added, removed or changed since baseline <name>, with
+the examples that changed. A requirement counts as changed when its content
+differs; status changes alone do not count. New requirements are only
+reported if they are in the namespace and statuses the baseline was taken
+from.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q (allowed: text, json)", format)
+		}
+		cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		b, err := baseline.Load(config.DefaultBaselineDir, args[0])
+		if err != nil {
+			return err
+		}
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		report := compareBaseline(cfg, b, specs)
+
+		w := cmd.OutOrStdout()
+		if format == "json" {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, string(data))
+			return nil
+		}
+		created := report.CreatedAt.Local().Format(journalTimeFormat)
+		if report.Empty() {
+			fmt.Fprintf(w, "no changes since baseline %s (%s)\n", b.Name, created)
+			return nil
+		}
+		fmt.Fprintf(w, "baseline %s (%s): %d added, %d removed, %d changed\n",
+			b.Name, created, len(report.Added), len(report.Removed), len(report.Changed))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, it := range report.Changed {
+			fmt.Fprintf(tw, "changed\t%s\t%s%s\n", it.ID, it.Title, describeExampleChanges(it))
+		}
+		for _, it := range report.Added {
+			fmt.Fprintf(tw, "added\t%s\t%s\n", it.ID, it.Title)
+		}
+		for _, it := range report.Removed {
+			fmt.Fprintf(tw, "removed\t%s\t%s\n", it.ID, it.Title)
+		}
+		return tw.Flush()
+	},
+}
+
+var baselineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List baselines",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baselines, err := baseline.List(config.DefaultBaselineDir)
+		if err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		if len(baselines) == 0 {
+			fmt.Fprintln(w, "no baselines found")
+			return nil
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tCREATED\tREQUIREMENTS")
+		for _, b := range baselines {
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", b.Name, b.CreatedAt.Local().Format(journalTimeFormat), len(b.Requirements))
+		}
+		return tw.Flush()
+	},
+}
+
+// compareBaseline compares specs with b, counting new specs only when they
+// are in the namespace and statuses b was taken from.
+func compareBaseline(cfg config.SpecConfig, b *baseline.Baseline, specs []*spec.Spec) baseline.Report {
+	lc := cfg.EffectiveLifecycle()
+	return baseline.Compare(b, specs, func(s *spec.Spec) bool {
+		if !spec.InNamespace(s.Namespace, b.Namespace) {
+			return false
+		}
+		status := lc.Effective(s.Status)
+		if len(b.Statuses) > 0 {
+			return slices.Contains(b.Statuses, status)
+		}
+		return !lc.IsExcluded(status)
+	})
+}
+
+// describeExampleChanges formats the example changes of a requirement, e.g.
+// " (E2 changed, E3 added)".
+func describeExampleChanges(it baseline.Item) string {
+	var parts []string
+	for _, id := range it.ExamplesChanged {
+		parts = append(parts, id+" changed")
+	}
+	for _, id := range it.ExamplesAdded {
+		parts = append(parts, id+" added")
+	}
+	for _, id := range it.ExamplesRemoved {
+		parts = append(parts, id+" removed")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineCreateCmd)
+	baselineCmd.AddCommand(baselineCompareCmd)
+	baselineCmd.AddCommand(baselineListCmd)
+
+	baselineCreateCmd.Flags().StringSlice("status", nil, "Only include specs in these statuses (default: all but excluded, e.g. deprecated)")
+	baselineCreateCmd.Flags().Bool("force", false, "Replace an existing baseline of the same name")
+	baselineCompareCmd.Flags().String("format", "text", "Output format (text or json)")
+}
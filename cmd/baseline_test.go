@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/baseline"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestBaselineCommands(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	for _, c := range []*cobra.Command{baselineCreateCmd, baselineCompareCmd, baselineListCmd, traceCmd} {
+		resetCmdFlags(t, c)
+	}
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login", Status: "ready", Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t"}}},
+		&spec.Spec{ID: "REQ-002", Title: "Logout", Status: "ready"},
+		&spec.Spec{ID: "REQ-003", Title: "Draft idea"},
+	)
+
+	var buf bytes.Buffer
+	baselineCreateCmd.SetOut(&buf)
+	_ = baselineCreateCmd.Flags().Set("status", "ready")
+	if err := baselineCreateCmd.RunE(baselineCreateCmd, []string{"v1.0"}); err != nil {
+		t.Fatalf("baseline create error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "created baseline v1.0 with 2 requirement(s)") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	if err := baselineCreateCmd.RunE(baselineCreateCmd, []string{"v1.0"}); !apperrors.IsConflict(err) {
+		t.Errorf("second create error = %v, want conflict", err)
+	}
+
+	t.Run("compare without changes", func(t *testing.T) {
+		var buf bytes.Buffer
+		baselineCompareCmd.SetOut(&buf)
+		if err := baselineCompareCmd.RunE(baselineCompareCmd, []string{"v1.0"}); err != nil {
+			t.Fatalf("baseline compare error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "no changes since baseline v1.0") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	})
+
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login", Status: "implemented", Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t2"}}},
+		&spec.Spec{ID: "REQ-004", Title: "Signup", Status: "ready"},
+	)
+	if err := os.Remove(filepath.Join(specDir, "REQ-002.yml")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("compare lists changes", func(t *testing.T) {
+		var buf bytes.Buffer
+		baselineCompareCmd.SetOut(&buf)
+		if err := baselineCompareCmd.RunE(baselineCompareCmd, []string{"v1.0"}); err != nil {
+			t.Fatalf("baseline compare error: %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{"1 added, 1 removed, 1 changed", "changed  REQ-001  Login (E1 changed)", "added    REQ-004", "removed  REQ-002"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "REQ-003") {
+			t.Errorf("draft REQ-003 is outside the baseline's statuses:\n%s", out)
+		}
+
+		buf.Reset()
+		_ = baselineCompareCmd.Flags().Set("format", "json")
+		if err := baselineCompareCmd.RunE(baselineCompareCmd, []string{"v1.0"}); err != nil {
+			t.Fatalf("baseline compare json error: %v", err)
+		}
+		var r baseline.Report
+		if err := json.Unmarshal(buf.Bytes(), &r); err != nil || len(r.Changed) != 1 {
+			t.Errorf("unexpected JSON report: %+v, %v", r, err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var buf bytes.Buffer
+		baselineListCmd.SetOut(&buf)
+		if err := baselineListCmd.RunE(baselineListCmd, nil); err != nil {
+			t.Fatalf("baseline list error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "v1.0") || !strings.Contains(buf.String(), "  2\n") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("trace flags changed requirements", func(t *testing.T) {
+		testFile := filepath.Join(tmpDir, "tests", "login.test.ts")
+		if err := os.MkdirAll(filepath.Dir(testFile), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(testFile, []byte(`it("REQ-001 E1: login", () => {})`+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		traceCmd.SetOut(&bytes.Buffer{})
+		_ = traceCmd.Flags().Set("baseline", "v1.0")
+		if err := traceCmd.RunE(traceCmd, nil); err != nil {
+			t.Fatalf("trace error: %v", err)
+		}
+		md, err := os.ReadFile(filepath.Join(tmpDir, ".tdd", "trace.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(md), "| REQ-001 | Login | changed | E1 | 1 |") {
+			t.Errorf("trace.md does not flag REQ-001:\n%s", md)
+		}
+	})
+}
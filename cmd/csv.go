@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/csvtable"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export specs to external formats",
+}
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv <file>",
+	Short: "Import specs from a CSV/TSV requirements table",
+	Args:  cobra.ExactArgs(1),
+	RunE:  withWorkspaceLock(runImportCSV),
+}
+
+var exportCSVCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Export specs as a CSV/TSV requirements table",
+	RunE:  runExportCSV,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	importCmd.AddCommand(importCSVCmd)
+	exportCmd.AddCommand(exportCSVCmd)
+
+	for _, c := range []*cobra.Command{importCSVCmd, exportCSVCmd} {
+		c.Flags().String("delimiter", "", "Field delimiter: comma or tab (default: tab for .tsv, otherwise comma)")
+		c.Flags().StringToString("column", nil, "Column header override as field=Header (overrides csvColumns in config)")
+	}
+	importCSVCmd.Flags().Bool("force", false, "Overwrite existing spec files")
+	importCSVCmd.Flags().Bool("dry-run", false, "Preview without writing files")
+	exportCSVCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+}
+
+// csvTableOptions resolves the column mapping and delimiter for path.
+func csvTableOptions(cmd *cobra.Command, cfg config.SpecConfig, path string) (csvtable.Columns, rune, error) {
+	overrides := make(map[string]string, len(cfg.CSVColumns))
+	for k, v := range cfg.CSVColumns {
+		overrides[k] = v
+	}
+	flagCols, _ := cmd.Flags().GetStringToString("column")
+	for k, v := range flagCols {
+		overrides[k] = v
+	}
+	cols, err := csvtable.NewColumns(overrides)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	delimFlag, _ := cmd.Flags().GetString("delimiter")
+	switch strings.ToLower(delimFlag) {
+	case "":
+		return cols, csvtable.DelimiterFor(path), nil
+	case "comma", ",":
+		return cols, ',', nil
+	case "tab", `\t`, "\t":
+		return cols, '\t', nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported delimiter: %s (use comma or tab)", delimFlag)
+	}
+}
+
+func runImportCSV(cmd *cobra.Command, args []string) error {
+	cfg, err := loadSpecConfig(cmd)
+	if err != nil {
+		return err
+	}
+	ns, err := importNamespace()
+	if err != nil {
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cols, delim, err := csvTableOptions(cmd, cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	records, err := csvtable.Read(f, cols, delim)
+	if err != nil {
+		return err
+	}
+
+	existing, err := spec.LoadAll(cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+
+	assignCSVReqIDs(records, existing)
+
+	if err := validateCSVDepends(records, existing); err != nil {
+		return err
+	}
+
+	if !dryRun {
+		if err := os.MkdirAll(cfg.SpecDir, 0755); err != nil {
+			return err
+		}
+	}
+
+	entries := make([]importEntry, 0, len(records))
+	for _, rec := range records {
+		entries = append(entries, importEntry{spec: rec.Spec})
+	}
+	return saveEntries(cmd, entries, cfg.SpecDir, ns, force, dryRun)
+}
+
+// assignCSVReqIDs gives requirements without an ID the next free REQ number
+// after both the existing specs and the IDs used in the table.
+func assignCSVReqIDs(records []csvtable.Record, existing []*spec.Spec) {
+	maxN := 0
+	trackMax := func(id string) {
+		if n, ok := autoIDNumber(id); ok && n > maxN {
+			maxN = n
+		}
+	}
+	for _, s := range existing {
+		trackMax(s.ID)
+	}
+	for _, rec := range records {
+		trackMax(rec.Spec.ID)
+	}
+	for _, rec := range records {
+		if rec.Spec.ID == "" {
+			maxN++
+			rec.Spec.ID = autoID(maxN)
+		}
+	}
+}
+
+// validateCSVDepends checks every requirement's depends against the IDs in
+// the table and in the spec directory, reporting failures by row.
+func validateCSVDepends(records []csvtable.Record, existing []*spec.Spec) error {
+	known := make(map[string]bool, len(existing)+len(records))
+	for _, s := range existing {
+		known[s.ID] = true
+	}
+	for _, rec := range records {
+		known[rec.Spec.ID] = true
+	}
+
+	var rowErrs csvtable.RowErrors
+	for _, rec := range records {
+		set := []*spec.Spec{rec.Spec}
+		for _, dep := range rec.Spec.Depends {
+			if known[dep] {
+				set = append(set, &spec.Spec{ID: dep})
+			}
+		}
+		if err := spec.ValidateDependsRefs(set); err != nil {
+			rowErrs = append(rowErrs, csvtable.RowError{Row: rec.Row, Message: apperrors.Message(err)})
+		}
+	}
+	if len(rowErrs) > 0 {
+		return fmt.Errorf("dependency validation failed: %w", rowErrs)
+	}
+	return nil
+}
+
+func runExportCSV(cmd *cobra.Command, args []string) error {
+	cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	cols, delim, err := csvTableOptions(cmd, cfg, output)
+	if err != nil {
+		return err
+	}
+
+	specs, err := spec.LoadAll(cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+	specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		return csvtable.Write(cmd.OutOrStdout(), specs, cols, delim)
+	}
+
+	var buf bytes.Buffer
+	if err := csvtable.Write(&buf, specs, cols, delim); err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s (%d specs)\n", output, len(specs))
+	return nil
+}
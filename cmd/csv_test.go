@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestImportCSVCommand(t *testing.T) {
+	t.Run("imports rows and auto-assigns missing IDs", func(t *testing.T) {
+		tmpDir := setupWorkspace(t)
+
+		table := "id,title,tags,depends,exampleId,given,when,then\n" +
+			"REQ-002,Login,auth,,E1,g1,w1,t1\n" +
+			",,,,E3,g2,w2,t2\n" +
+			",Logout,auth,REQ-002,,g3,w3,t3\n"
+		if err := os.WriteFile("reqs.csv", []byte(table), 0644); err != nil {
+			t.Fatalf("write csv error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importCSVCmd.SetOut(&buf)
+
+		if err := importCSVCmd.RunE(importCSVCmd, []string{"reqs.csv"}); err != nil {
+			t.Fatalf("importCSVCmd error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "2 created") {
+			t.Errorf("expected '2 created', got: %s", buf.String())
+		}
+
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+		s, err := spec.Load(filepath.Join(specDir, "REQ-002.yml"))
+		if err != nil {
+			t.Fatalf("Load REQ-002 error: %v", err)
+		}
+		if len(s.Examples) != 2 || s.Examples[1].ID != "E3" {
+			t.Errorf("expected example IDs preserved, got %+v", s.Examples)
+		}
+
+		logout, err := spec.Load(filepath.Join(specDir, "REQ-003.yml"))
+		if err != nil {
+			t.Fatalf("Load REQ-003 error: %v", err)
+		}
+		if logout.Title != "Logout" || logout.Examples[0].ID != "E1" {
+			t.Errorf("unexpected REQ-003: %+v", logout)
+		}
+	})
+
+	t.Run("unknown depends are reported with row numbers", func(t *testing.T) {
+		setupWorkspace(t)
+
+		table := "id,title,depends\nREQ-001,A,\nREQ-002,B,REQ-009\n"
+		if err := os.WriteFile("reqs.csv", []byte(table), 0644); err != nil {
+			t.Fatalf("write csv error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importCSVCmd.SetOut(&buf)
+
+		err := importCSVCmd.RunE(importCSVCmd, []string{"reqs.csv"})
+		if err == nil {
+			t.Fatal("expected dependency error")
+		}
+		if !strings.Contains(err.Error(), "row 3: REQ-002 depends on REQ-009") {
+			t.Errorf("expected row-numbered error, got: %v", err)
+		}
+	})
+
+	t.Run("depends may reference existing specs", func(t *testing.T) {
+		tmpDir := setupWorkspace(t)
+		if err := spec.Save(filepath.Join(tmpDir, ".tdd", "specs", "REQ-001.yml"), &spec.Spec{ID: "REQ-001", Title: "A"}); err != nil {
+			t.Fatalf("save spec error: %v", err)
+		}
+
+		table := "id,title,depends\nREQ-002,B,REQ-001\n"
+		if err := os.WriteFile("reqs.csv", []byte(table), 0644); err != nil {
+			t.Fatalf("write csv error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importCSVCmd.SetOut(&buf)
+
+		if err := importCSVCmd.RunE(importCSVCmd, []string{"reqs.csv"}); err != nil {
+			t.Fatalf("importCSVCmd error: %v", err)
+		}
+	})
+}
+
+func TestExportCSVCommand(t *testing.T) {
+	t.Run("round trip through export and import preserves IDs", func(t *testing.T) {
+		tmpDir := setupWorkspace(t)
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+
+		original := &spec.Spec{
+			ID:    "REQ-004",
+			Title: "Search",
+			Tags:  []string{"search"},
+			Examples: []spec.Example{
+				{ID: "E2", Given: "g", When: "w", Then: "t"},
+			},
+		}
+		if err := spec.Save(filepath.Join(specDir, "REQ-004.yml"), original); err != nil {
+			t.Fatalf("save spec error: %v", err)
+		}
+
+		var out bytes.Buffer
+		exportCSVCmd.SetOut(&out)
+		exportCSVCmd.SetErr(&bytes.Buffer{})
+		if err := exportCSVCmd.Flags().Set("output", "reqs.tsv"); err != nil {
+			t.Fatalf("set output flag: %v", err)
+		}
+		defer func() {
+			_ = exportCSVCmd.Flags().Set("output", "")
+		}()
+
+		if err := exportCSVCmd.RunE(exportCSVCmd, []string{}); err != nil {
+			t.Fatalf("exportCSVCmd error: %v", err)
+		}
+
+		data, err := os.ReadFile("reqs.tsv")
+		if err != nil {
+			t.Fatalf("read export error: %v", err)
+		}
+		if !strings.Contains(string(data), "REQ-004\tSearch") {
+			t.Errorf("expected TSV output, got:\n%s", data)
+		}
+
+		if err := os.Remove(filepath.Join(specDir, "REQ-004.yml")); err != nil {
+			t.Fatalf("remove spec error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importCSVCmd.SetOut(&buf)
+		if err := importCSVCmd.RunE(importCSVCmd, []string{"reqs.tsv"}); err != nil {
+			t.Fatalf("importCSVCmd error: %v", err)
+		}
+
+		s, err := spec.Load(filepath.Join(specDir, "REQ-004.yml"))
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		if s.Examples[0].ID != "E2" || s.Tags[0] != "search" {
+			t.Errorf("round trip lost data: %+v", s)
+		}
+	})
+
+	t.Run("writes to stdout with custom columns", func(t *testing.T) {
+		tmpDir := setupWorkspace(t)
+		if err := spec.Save(filepath.Join(tmpDir, ".tdd", "specs", "REQ-001.yml"), &spec.Spec{ID: "REQ-001", Title: "A"}); err != nil {
+			t.Fatalf("save spec error: %v", err)
+		}
+
+		var out bytes.Buffer
+		exportCSVCmd.SetOut(&out)
+		exportCSVCmd.SetErr(&bytes.Buffer{})
+		if err := exportCSVCmd.Flags().Set("column", "id=要件ID"); err != nil {
+			t.Fatalf("set column flag: %v", err)
+		}
+		defer func() {
+			// StringToString flags merge on Set, so restore the default header
+			_ = exportCSVCmd.Flags().Set("column", "id=id")
+		}()
+
+		if err := exportCSVCmd.RunE(exportCSVCmd, []string{}); err != nil {
+			t.Fatalf("exportCSVCmd error: %v", err)
+		}
+		if !strings.HasPrefix(out.String(), "要件ID,title,") {
+			t.Errorf("expected custom header on stdout, got:\n%s", out.String())
+		}
+	})
+}
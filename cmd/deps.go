@@ -1,13 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
 	"github.com/thirdlf03/spec-tdd/internal/deps"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
@@ -18,7 +19,7 @@ var testDepsDetector deps.Detector
 var depsCmd = &cobra.Command{
 	Use:   "deps",
 	Short: "Detect and update dependencies between specs",
-	RunE:  runDeps,
+	RunE:  withWorkspaceLock(runDeps),
 }
 
 func init() {
@@ -28,6 +29,7 @@ func init() {
 	depsCmd.Flags().String("model", "gemini-2.5-flash", "Gemini model name for dependency detection")
 	depsCmd.Flags().Duration("timeout", 60*time.Second, "Timeout for Gemini API call")
 	depsCmd.Flags().Bool("dry-run", false, "Preview dependencies without writing files")
+	addSelectFlag(depsCmd)
 }
 
 func runDeps(cmd *cobra.Command, args []string) error {
@@ -47,6 +49,20 @@ func runDeps(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(cmd.OutOrStdout(), "no specs found\n")
 		return nil
 	}
+	// Every spec is a dependency candidate; only those in --namespace and
+	// --select are updated
+	targets, err := filterSpecsByNamespace(cfg.SpecDir, specs)
+	if err != nil {
+		return err
+	}
+	targets, err = filterSpecsBySelect(cmd, targets, cfg.EffectiveLifecycle())
+	if err != nil {
+		return err
+	}
+	update := make(map[string]bool, len(targets))
+	for _, s := range targets {
+		update[s.ID] = true
+	}
 
 	enrichEnabled, _ := cmd.Flags().GetBool("enrich")
 	model, _ := cmd.Flags().GetString("model")
@@ -88,11 +104,13 @@ func runDeps(cmd *cobra.Command, args []string) error {
 		depsMap[r.ID] = r
 	}
 
-	// Update specs
+	// Update specs in one transaction
 	updatedCount := 0
+	var report bytes.Buffer
+	tx := atomicfile.NewTx()
 	for _, s := range specs {
 		r, ok := depsMap[s.ID]
-		if !ok {
+		if !ok || !update[s.ID] {
 			continue
 		}
 
@@ -102,14 +120,21 @@ func runDeps(cmd *cobra.Command, args []string) error {
 		}
 
 		s.Depends = r.Depends
-		specPath := filepath.Join(cfg.SpecDir, s.ID+".yml")
-		if err := spec.Save(specPath, s); err != nil {
-			log.Error("Failed to save spec", "id", s.ID, "error", err)
+		specPath, err := spec.PathFor(cfg.SpecDir, s.Namespace, s.ID)
+		if err != nil {
+			return err
+		}
+		if err := spec.Stage(tx, specPath, s); err != nil {
 			return err
 		}
-		fmt.Fprintf(cmd.OutOrStdout(), "updated: %s -> %v\n", s.ID, r.Depends)
+		fmt.Fprintf(&report, "updated: %s -> %v\n", s.ID, r.Depends)
 		updatedCount++
 	}
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to save specs", "error", err)
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), report.String())
 
 	// Validate graph (warning only)
 	if !dryRun && updatedCount > 0 {
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/gitrev"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"github.com/thirdlf03/spec-tdd/internal/specdiff"
+)
+
+// workingTree is how the diff report names the specs on disk.
+const workingTree = "working tree"
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <rev1> [<rev2>]",
+	Short: "Show requirement-level changes between git revisions",
+	Long: `Compare the specs at two revisions of the local git repository, or at rev1
+and the working tree, and report added, removed and changed requirements,
+examples, dependencies and questions. Requirements, rules, examples and
+questions are matched by ID.
+
+The specs are read from the git object store, so neither revision has to be
+checked out. The Markdown output is meant for pull request comments; use
+--format json for tools.`,
+	Example: `  spec-tdd diff main HEAD
+  spec-tdd diff HEAD~1 --format json
+  spec-tdd diff origin/main --output spec-diff.md`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("diff")
+
+		format, _ := cmd.Flags().GetString("format")
+		if format != "markdown" && format != "json" {
+			return fmt.Errorf("unknown format %q (allowed: markdown, json)", format)
+		}
+		cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+
+		before, err := specsAtRevision(args[0], cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		to := workingTree
+		var after []*spec.Spec
+		if len(args) == 2 {
+			to = args[1]
+			after, err = specsAtRevision(to, cfg.SpecDir)
+		} else {
+			after, err = spec.LoadAll(cfg.SpecDir)
+		}
+		if err != nil {
+			return err
+		}
+		// The namespace may exist at only one side, so it is not checked on disk
+		ns := spec.CleanNamespace(namespaceFilter)
+		if ns != "" {
+			if err := spec.ValidateNamespace(ns); err != nil {
+				return err
+			}
+		}
+
+		report := specdiff.Compare(spec.FilterNamespace(before, ns), spec.FilterNamespace(after, ns))
+		report.From, report.To = args[0], to
+
+		var data []byte
+		if format == "json" {
+			if data, err = report.ToJSON(); err != nil {
+				return err
+			}
+			data = append(data, '\n')
+		} else {
+			data = []byte(report.ToMarkdown())
+		}
+
+		if output, _ := cmd.Flags().GetString("output"); output != "" {
+			if err := atomicfile.WriteFile(output, data, 0644); err != nil {
+				log.Error("Failed to write diff", "path", output, "error", err)
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s\n", output)
+			return nil
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+// specsAtRevision loads the specs as they were at rev.
+func specsAtRevision(rev, specDir string) ([]*spec.Spec, error) {
+	files, err := gitrev.ReadDir(rev, specDir)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := spec.ParseAll(specDir, files)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rev, err)
+	}
+	return specs, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().String("format", "markdown", "Output format (markdown or json)")
+	diffCmd.Flags().StringP("output", "o", "", "Write the report to a file instead of stdout")
+}
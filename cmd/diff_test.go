@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"github.com/thirdlf03/spec-tdd/internal/specdiff"
+)
+
+func gitCommitAll(t *testing.T, message string) {
+	t.Helper()
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-q", "-m", message}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestDiffCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, diffCmd)
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login", Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t"}}},
+		&spec.Spec{ID: "REQ-002", Title: "Logout"},
+	)
+	gitCommitAll(t, "first")
+
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login", Depends: []string{"REQ-003"}, Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t2"}}},
+		&spec.Spec{ID: "REQ-003", Title: "Accounts"},
+	)
+	if err := os.Remove(filepath.Join(specDir, "REQ-002.yml")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("markdown against the working tree", func(t *testing.T) {
+		var buf bytes.Buffer
+		diffCmd.SetOut(&buf)
+		if err := diffCmd.RunE(diffCmd, []string{"HEAD"}); err != nil {
+			t.Fatalf("diff error: %v", err)
+		}
+		for _, want := range []string{"(HEAD → working tree)", "- **REQ-003** Accounts", "- **REQ-002** Logout", "- depends: +REQ-003", "  - then: `t` → `t2`"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("json between revisions", func(t *testing.T) {
+		gitCommitAll(t, "second")
+		var buf bytes.Buffer
+		diffCmd.SetOut(&buf)
+		_ = diffCmd.Flags().Set("format", "json")
+		if err := diffCmd.RunE(diffCmd, []string{"HEAD~1", "HEAD"}); err != nil {
+			t.Fatalf("diff error: %v", err)
+		}
+		var r specdiff.Report
+		if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if r.From != "HEAD~1" || r.To != "HEAD" || len(r.Added) != 1 || len(r.Removed) != 1 || len(r.Changed) != 1 {
+			t.Errorf("unexpected report: %+v", r)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		err := diffCmd.RunE(diffCmd, []string{"no-such-rev"})
+		if err == nil || !strings.Contains(err.Error(), `unknown revision "no-such-rev"`) {
+			t.Errorf("expected unknown revision error, got %v", err)
+		}
+	})
+}
@@ -2,11 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
@@ -15,6 +14,9 @@ var (
 	exampleGiven string
 	exampleWhen  string
 	exampleThen  string
+	exampleRule  string
+	exampleID    string
+	exampleTo    string
 )
 
 var exampleCmd = &cobra.Command{
@@ -25,7 +27,7 @@ var exampleCmd = &cobra.Command{
 var exampleAddCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add an example to a requirement",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
 		log := GetLogger().WithComponent("example.add")
 
 		cfg, err := loadSpecConfig(cmd)
@@ -38,12 +40,7 @@ var exampleAddCmd = &cobra.Command{
 			return fmt.Errorf("--req is required")
 		}
 
-		path := filepath.Join(cfg.SpecDir, fmt.Sprintf("%s.yml", reqID))
-		if _, err := os.Stat(path); err != nil {
-			return fmt.Errorf("spec not found: %s", path)
-		}
-
-		s, err := spec.Load(path)
+		path, s, err := loadSpecByID(cfg.SpecDir, reqID)
 		if err != nil {
 			return err
 		}
@@ -54,7 +51,15 @@ var exampleAddCmd = &cobra.Command{
 			When:  exampleWhen,
 			Then:  exampleThen,
 		}
-		s.Examples = append(s.Examples, newExample)
+		if ruleID := strings.TrimSpace(exampleRule); ruleID != "" {
+			r := s.FindRule(ruleID)
+			if r == nil {
+				return fmt.Errorf("rule %s not found in %s", ruleID, s.ID)
+			}
+			r.Examples = append(r.Examples, newExample)
+		} else {
+			s.Examples = append(s.Examples, newExample)
+		}
 
 		if err := spec.Save(path, s); err != nil {
 			log.Error("Failed to save spec", "path", path, "error", err)
@@ -63,7 +68,142 @@ var exampleAddCmd = &cobra.Command{
 
 		fmt.Fprintf(cmd.OutOrStdout(), "added %s to %s\n", newExample.ID, path)
 		return nil
-	},
+	}),
+}
+
+var exampleEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the Given/When/Then of an example",
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("example.edit")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		path, s, err := loadSpecByID(cfg.SpecDir, exampleReqID)
+		if err != nil {
+			return err
+		}
+		ex := s.FindExample(strings.TrimSpace(exampleID))
+		if ex == nil {
+			return fmt.Errorf("example %s not found in %s", exampleID, s.ID)
+		}
+
+		flags := cmd.Flags()
+		if !flags.Changed("given") && !flags.Changed("when") && !flags.Changed("then") {
+			return fmt.Errorf("nothing to change: set --given, --when or --then")
+		}
+		if flags.Changed("given") {
+			ex.Given = exampleGiven
+		}
+		if flags.Changed("when") {
+			ex.When = exampleWhen
+		}
+		if flags.Changed("then") {
+			ex.Then = exampleThen
+		}
+
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "updated %s in %s\n", ex.ID, path)
+		return nil
+	}),
+}
+
+var exampleRmCmd = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove an example from a requirement",
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("example.rm")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		path, s, err := loadSpecByID(cfg.SpecDir, exampleReqID)
+		if err != nil {
+			return err
+		}
+		ex, _, ok := s.RemoveExample(strings.TrimSpace(exampleID))
+		if !ok {
+			return fmt.Errorf("example %s not found in %s", exampleID, s.ID)
+		}
+
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "removed %s from %s\n", ex.ID, path)
+		return nil
+	}),
+}
+
+var exampleMoveCmd = &cobra.Command{
+	Use:   "move",
+	Short: "Move an example to another requirement or rule",
+	Long: `Move an example to another requirement (--to) and/or into a rule (--rule).
+The example keeps its ID when it is free in the target requirement; otherwise it
+gets the next free ID there, and tests referring to the old ID must be updated.`,
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("example.move")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		srcPath, src, err := loadSpecByID(cfg.SpecDir, exampleReqID)
+		if err != nil {
+			return err
+		}
+		dstPath, dst := srcPath, src
+		if to := strings.TrimSpace(exampleTo); to != "" && to != src.ID {
+			if dstPath, dst, err = loadSpecByID(cfg.SpecDir, to); err != nil {
+				return err
+			}
+		}
+
+		ex, _, ok := src.RemoveExample(strings.TrimSpace(exampleID))
+		if !ok {
+			return fmt.Errorf("example %s not found in %s", exampleID, src.ID)
+		}
+		oldID := ex.ID
+		if dst.FindExample(ex.ID) != nil {
+			ex.ID = spec.NextExampleID(dst)
+		}
+		if ruleID := strings.TrimSpace(exampleRule); ruleID != "" {
+			r := dst.FindRule(ruleID)
+			if r == nil {
+				return fmt.Errorf("rule %s not found in %s", ruleID, dst.ID)
+			}
+			r.Examples = append(r.Examples, ex)
+		} else {
+			dst.Examples = append(dst.Examples, ex)
+		}
+
+		// Save both specs together so the example is never lost or duplicated
+		tx := atomicfile.NewTx()
+		if err := spec.Stage(tx, dstPath, dst); err != nil {
+			return err
+		}
+		if dstPath != srcPath {
+			if err := spec.Stage(tx, srcPath, src); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			log.Error("Failed to save specs", "from", srcPath, "to", dstPath, "error", err)
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "moved %s %s to %s %s\n", src.ID, oldID, dst.ID, ex.ID)
+		if src.ID != dst.ID || oldID != ex.ID {
+			fmt.Fprintf(cmd.OutOrStdout(), "note: rename tests for \"%s %s\" to \"%s %s\"\n", src.ID, oldID, dst.ID, ex.ID)
+		}
+		return nil
+	}),
 }
 
 func init() {
@@ -74,8 +214,33 @@ func init() {
 	exampleAddCmd.Flags().StringVar(&exampleGiven, "given", "", "Given clause")
 	exampleAddCmd.Flags().StringVar(&exampleWhen, "when", "", "When clause")
 	exampleAddCmd.Flags().StringVar(&exampleThen, "then", "", "Then clause")
+	exampleAddCmd.Flags().StringVar(&exampleRule, "rule", "", "Attach the example to this rule (e.g., R1)")
 	_ = exampleAddCmd.MarkFlagRequired("req")
 	_ = exampleAddCmd.MarkFlagRequired("given")
 	_ = exampleAddCmd.MarkFlagRequired("when")
 	_ = exampleAddCmd.MarkFlagRequired("then")
+
+	exampleCmd.AddCommand(exampleEditCmd)
+	exampleCmd.AddCommand(exampleRmCmd)
+	exampleCmd.AddCommand(exampleMoveCmd)
+
+	exampleEditCmd.Flags().StringVar(&exampleReqID, "req", "", "Requirement ID (e.g., REQ-001)")
+	exampleEditCmd.Flags().StringVar(&exampleID, "id", "", "Example ID (e.g., E1)")
+	exampleEditCmd.Flags().StringVar(&exampleGiven, "given", "", "New Given clause")
+	exampleEditCmd.Flags().StringVar(&exampleWhen, "when", "", "New When clause")
+	exampleEditCmd.Flags().StringVar(&exampleThen, "then", "", "New Then clause")
+	_ = exampleEditCmd.MarkFlagRequired("req")
+	_ = exampleEditCmd.MarkFlagRequired("id")
+
+	exampleRmCmd.Flags().StringVar(&exampleReqID, "req", "", "Requirement ID (e.g., REQ-001)")
+	exampleRmCmd.Flags().StringVar(&exampleID, "id", "", "Example ID (e.g., E1)")
+	_ = exampleRmCmd.MarkFlagRequired("req")
+	_ = exampleRmCmd.MarkFlagRequired("id")
+
+	exampleMoveCmd.Flags().StringVar(&exampleReqID, "req", "", "Requirement ID the example belongs to")
+	exampleMoveCmd.Flags().StringVar(&exampleID, "id", "", "Example ID (e.g., E1)")
+	exampleMoveCmd.Flags().StringVar(&exampleTo, "to", "", "Target requirement ID (default: the same requirement)")
+	exampleMoveCmd.Flags().StringVar(&exampleRule, "rule", "", "Target rule in the target requirement (default: top-level examples)")
+	_ = exampleMoveCmd.MarkFlagRequired("req")
+	_ = exampleMoveCmd.MarkFlagRequired("id")
 }
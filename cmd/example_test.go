@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
@@ -50,3 +53,76 @@ func TestExampleAddCommand(t *testing.T) {
 		t.Fatalf("expected example ID E1, got %q", loaded.Examples[0].ID)
 	}
 }
+
+func TestExampleEditRmMove(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login", Examples: []spec.Example{
+			{ID: "E1", Given: "a user", When: "logging in", Then: "ok"},
+			{ID: "E2", Given: "a locked user", When: "logging in", Then: "rejected"},
+		}},
+		&spec.Spec{ID: "REQ-002", Title: "Lockout",
+			Examples: []spec.Example{{ID: "E1", Given: "3 failures", When: "logging in", Then: "locked"}},
+			Rules:    []spec.Rule{{ID: "R1", Text: "Locked users cannot log in"}},
+		},
+	)
+	for _, c := range []*cobra.Command{exampleEditCmd, exampleRmCmd, exampleMoveCmd} {
+		resetCmdFlags(t, c)
+		c.SetOut(&bytes.Buffer{})
+	}
+
+	_ = exampleEditCmd.Flags().Set("req", "REQ-001")
+	_ = exampleEditCmd.Flags().Set("id", "E1")
+	_ = exampleEditCmd.Flags().Set("then", "redirected to the dashboard")
+	if err := exampleEditCmd.RunE(exampleEditCmd, nil); err != nil {
+		t.Fatalf("example edit error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	exampleMoveCmd.SetOut(&buf)
+	_ = exampleMoveCmd.Flags().Set("req", "REQ-001")
+	_ = exampleMoveCmd.Flags().Set("id", "E2")
+	_ = exampleMoveCmd.Flags().Set("to", "REQ-002")
+	_ = exampleMoveCmd.Flags().Set("rule", "R1")
+	if err := exampleMoveCmd.RunE(exampleMoveCmd, nil); err != nil {
+		t.Fatalf("example move error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "moved REQ-001 E2 to REQ-002 E2\n") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	// E1 is taken in REQ-002, so the moved example is renumbered
+	_ = exampleMoveCmd.Flags().Set("id", "E1")
+	_ = exampleMoveCmd.Flags().Set("rule", "")
+	if err := exampleMoveCmd.RunE(exampleMoveCmd, nil); err != nil {
+		t.Fatalf("example move error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "moved REQ-001 E1 to REQ-002 E3\nnote: rename tests") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	src, err := spec.Load(filepath.Join(specDir, "REQ-001.yml"))
+	if err != nil || len(src.AllExamples()) != 0 {
+		t.Errorf("source spec = %+v, %v", src, err)
+	}
+	dst, err := spec.Load(filepath.Join(specDir, "REQ-002.yml"))
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if ex := dst.FindExample("E3"); ex == nil || ex.Then != "redirected to the dashboard" {
+		t.Errorf("moved example = %+v", ex)
+	}
+	if len(dst.Rules[0].Examples) != 1 || dst.Rules[0].Examples[0].ID != "E2" {
+		t.Errorf("rule examples = %+v", dst.Rules[0].Examples)
+	}
+
+	_ = exampleRmCmd.Flags().Set("req", "REQ-002")
+	_ = exampleRmCmd.Flags().Set("id", "E2")
+	if err := exampleRmCmd.RunE(exampleRmCmd, nil); err != nil {
+		t.Fatalf("example rm error: %v", err)
+	}
+	if err := exampleRmCmd.RunE(exampleRmCmd, nil); err == nil {
+		t.Error("expected error removing a missing example")
+	}
+}
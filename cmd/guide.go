@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
 	"github.com/thirdlf03/spec-tdd/internal/config"
 	"github.com/thirdlf03/spec-tdd/internal/guide"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
@@ -14,13 +15,15 @@ import (
 var guideCmd = &cobra.Command{
 	Use:   "guide",
 	Short: "Generate implementation guide from specs and dependencies",
-	RunE:  runGuide,
+	RunE:  withReportLock(runGuide),
 }
 
 func init() {
 	rootCmd.AddCommand(guideCmd)
 
 	guideCmd.Flags().String("output", ".tdd/GUIDE.md", "Output path for the guide")
+	guideCmd.Flags().StringSlice("status", nil, "Only include specs in these statuses (default: all but excluded, e.g. deprecated)")
+	addSelectFlag(guideCmd)
 }
 
 func runGuide(cmd *cobra.Command, args []string) error {
@@ -45,6 +48,24 @@ func runGuide(cmd *cobra.Command, args []string) error {
 	if err := spec.ValidateDependsRefs(specs); err != nil {
 		return fmt.Errorf("dependency validation failed: %w", err)
 	}
+	specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+	if err != nil {
+		return err
+	}
+
+	statuses, _ := cmd.Flags().GetStringSlice("status")
+	specs, _, err = filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+	if err != nil {
+		return err
+	}
+	specs, err = filterSpecsBySelect(cmd, specs, cfg.EffectiveLifecycle())
+	if err != nil {
+		return err
+	}
+	if len(specs) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "no specs match the filters\n")
+		return nil
+	}
 
 	sortResult := guide.TopologicalSort(specs)
 
@@ -77,7 +98,7 @@ func runGuide(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+	if err := atomicfile.WriteFile(outputPath, []byte(content), 0644); err != nil {
 		log.Error("Failed to write guide", "path", outputPath, "error", err)
 		return err
 	}
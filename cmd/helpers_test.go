@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// setupWorkspace changes into a new temporary directory with an empty
+// .tdd/specs and returns it. The working directory is restored after the
+// test.
+func setupWorkspace(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(oldWD)
+	})
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".tdd", "specs"), 0755); err != nil {
+		t.Fatalf("mkdir error: %v", err)
+	}
+	return tmpDir
+}
+
+// resetCmdFlags restores every flag of cmd to its default after the test.
+func resetCmdFlags(t *testing.T, cmd *cobra.Command) {
+	t.Helper()
+	t.Cleanup(func() {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				_ = sv.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	})
+}
+
+// saveTestSpecs saves specs as <ID>.yml in specDir.
+func saveTestSpecs(t *testing.T, specDir string, specs ...*spec.Spec) {
+	t.Helper()
+	for _, s := range specs {
+		if err := spec.Save(filepath.Join(specDir, s.ID+".yml"), s); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+}
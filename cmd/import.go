@@ -1,17 +1,17 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/config"
 	"github.com/thirdlf03/spec-tdd/internal/enrich"
 	"github.com/thirdlf03/spec-tdd/internal/kire"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
@@ -25,7 +25,14 @@ var importCmd = &cobra.Command{
 var importKireCmd = &cobra.Command{
 	Use:   "kire",
 	Short: "Import specs from kire output (JSONL + Markdown segments)",
-	RunE:  runImportKire,
+	RunE:  withWorkspaceLock(runImportKire),
+}
+
+var importMarkdownCmd = &cobra.Command{
+	Use:   "markdown <file>",
+	Short: "Import specs from a single Markdown document (built-in heading segmenter)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  withWorkspaceLock(runImportMarkdown),
 }
 
 // testEnricher はテスト用に Enricher を差し替えるための変数。
@@ -38,35 +45,55 @@ var testBatchEnricher enrich.BatchEnricher
 func init() {
 	rootCmd.AddCommand(importCmd)
 	importCmd.AddCommand(importKireCmd)
+	importCmd.AddCommand(importMarkdownCmd)
 
 	importKireCmd.Flags().String("dir", ".kire", "Directory containing kire segment files")
 	importKireCmd.Flags().String("jsonl", ".kire/metadata.jsonl", "Path to kire JSONL metadata file")
-	importKireCmd.Flags().Bool("force", false, "Overwrite existing spec files")
-	importKireCmd.Flags().Bool("dry-run", false, "Preview without writing files")
-	importKireCmd.Flags().Bool("enrich", false, "Enable LLM enrichment (requires GEMINI_API_KEY)")
-	importKireCmd.Flags().String("enrich-model", "gemini-2.5-flash-lite", "Gemini model name for enrichment")
-	importKireCmd.Flags().Duration("enrich-timeout", 30*time.Second, "Timeout for each Gemini API call")
-	importKireCmd.Flags().String("enrich-example-model", "", "Example generation model (enables 2-pass batch mode)")
-	importKireCmd.Flags().Duration("enrich-example-timeout", 120*time.Second, "Timeout for batch example generation")
-}
+	addImportPipelineFlags(importKireCmd)
 
-var batchReqIDPattern = regexp.MustCompile(`REQ-(\d{3})`)
+	addImportPipelineFlags(importMarkdownCmd)
+}
 
 type importEntry struct {
 	seg  *kire.Segment
 	spec *spec.Spec
 }
 
-func runImportKire(cmd *cobra.Command, args []string) error {
-	log := GetLogger().WithComponent("import")
+// importOptions holds the flags and enrichers shared by every import source.
+type importOptions struct {
+	cfg           config.SpecConfig
+	namespace     string // --namespace, where new specs are created
+	force         bool
+	dryRun        bool
+	enrichEnabled bool
+	batchMode     bool
+	enricher      enrich.Enricher
+	batchEnricher enrich.BatchEnricher
+}
+
+// addImportPipelineFlags registers the flags consumed by prepareImport.
+func addImportPipelineFlags(c *cobra.Command) {
+	c.Flags().Bool("force", false, "Overwrite existing spec files")
+	c.Flags().Bool("dry-run", false, "Preview without writing files")
+	c.Flags().Bool("enrich", false, "Enable LLM enrichment (requires GEMINI_API_KEY)")
+	c.Flags().String("enrich-model", "gemini-2.5-flash-lite", "Gemini model name for enrichment")
+	c.Flags().Duration("enrich-timeout", 30*time.Second, "Timeout for each Gemini API call")
+	c.Flags().String("enrich-example-model", "", "Example generation model (enables 2-pass batch mode)")
+	c.Flags().Duration("enrich-example-timeout", 120*time.Second, "Timeout for batch example generation")
+}
 
+// prepareImport loads the spec config and initializes enrichers from flags.
+func prepareImport(cmd *cobra.Command) (importOptions, error) {
 	cfg, err := loadSpecConfig(cmd)
 	if err != nil {
-		return err
+		return importOptions{}, err
+	}
+
+	ns, err := importNamespace()
+	if err != nil {
+		return importOptions{}, err
 	}
 
-	dir, _ := cmd.Flags().GetString("dir")
-	jsonlPath, _ := cmd.Flags().GetString("jsonl")
 	force, _ := cmd.Flags().GetBool("force")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	enrichEnabled, _ := cmd.Flags().GetBool("enrich")
@@ -75,96 +102,137 @@ func runImportKire(cmd *cobra.Command, args []string) error {
 	enrichExampleModel, _ := cmd.Flags().GetString("enrich-example-model")
 	enrichExampleTimeout, _ := cmd.Flags().GetDuration("enrich-example-timeout")
 
-	// 2-pass batch mode: --enrich --enrich-example-model <model>
-	batchMode := enrichEnabled && enrichExampleModel != ""
+	opts := importOptions{
+		cfg:           cfg,
+		namespace:     ns,
+		force:         force,
+		dryRun:        dryRun,
+		enrichEnabled: enrichEnabled,
+		// 2-pass batch mode: --enrich --enrich-example-model <model>
+		batchMode: enrichEnabled && enrichExampleModel != "",
+	}
 
-	// Initialize enricher if --enrich is enabled (1-pass mode)
-	var enricher enrich.Enricher
-	var batchEnricher enrich.BatchEnricher
-	if enrichEnabled {
-		if batchMode {
-			// 2-pass batch mode
-			if testBatchEnricher != nil {
-				batchEnricher = testBatchEnricher
-			} else {
-				apiKey := os.Getenv("GEMINI_API_KEY")
-				if apiKey == "" {
-					return fmt.Errorf("GEMINI_API_KEY is required when --enrich is enabled. Set it with: export GEMINI_API_KEY=your-key")
-				}
-				be, err := enrich.NewGeminiBatchEnricher(enrich.GeminiBatchEnricherConfig{
-					APIKey:          apiKey,
-					ClassifyModel:   enrichModel,
-					ExampleModel:    enrichExampleModel,
-					ClassifyTimeout: enrichTimeout,
-					ExampleTimeout:  enrichExampleTimeout,
-				})
-				if err != nil {
-					return err
-				}
-				batchEnricher = be
-			}
-		} else {
-			// 1-pass mode
-			if testEnricher != nil {
-				enricher = testEnricher
-			} else {
-				apiKey := os.Getenv("GEMINI_API_KEY")
-				if apiKey == "" {
-					return fmt.Errorf("GEMINI_API_KEY is required when --enrich is enabled. Set it with: export GEMINI_API_KEY=your-key")
-				}
-				e, err := enrich.NewGeminiEnricher(enrich.GeminiEnricherConfig{
-					APIKey:  apiKey,
-					Model:   enrichModel,
-					Timeout: enrichTimeout,
-				})
-				if err != nil {
-					return err
-				}
-				enricher = e
-			}
+	if !enrichEnabled {
+		return opts, nil
+	}
+
+	if opts.batchMode {
+		// 2-pass batch mode
+		if testBatchEnricher != nil {
+			opts.batchEnricher = testBatchEnricher
+			return opts, nil
+		}
+		apiKey := os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			return importOptions{}, fmt.Errorf("GEMINI_API_KEY is required when --enrich is enabled. Set it with: export GEMINI_API_KEY=your-key")
 		}
+		be, err := enrich.NewGeminiBatchEnricher(enrich.GeminiBatchEnricherConfig{
+			APIKey:          apiKey,
+			ClassifyModel:   enrichModel,
+			ExampleModel:    enrichExampleModel,
+			ClassifyTimeout: enrichTimeout,
+			ExampleTimeout:  enrichExampleTimeout,
+		})
+		if err != nil {
+			return importOptions{}, err
+		}
+		opts.batchEnricher = be
+		return opts, nil
 	}
 
-	metas, err := kire.ParseJSONL(jsonlPath)
+	// 1-pass mode
+	if testEnricher != nil {
+		opts.enricher = testEnricher
+		return opts, nil
+	}
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return importOptions{}, fmt.Errorf("GEMINI_API_KEY is required when --enrich is enabled. Set it with: export GEMINI_API_KEY=your-key")
+	}
+	e, err := enrich.NewGeminiEnricher(enrich.GeminiEnricherConfig{
+		APIKey:  apiKey,
+		Model:   enrichModel,
+		Timeout: enrichTimeout,
+	})
 	if err != nil {
-		return err
+		return importOptions{}, err
 	}
+	opts.enricher = e
+	return opts, nil
+}
 
-	if err := os.MkdirAll(cfg.SpecDir, 0755); err != nil {
+func runImportKire(cmd *cobra.Command, args []string) error {
+	log := GetLogger().WithComponent("import")
+
+	opts, err := prepareImport(cmd)
+	if err != nil {
 		return err
 	}
 
-	// Phase 1: Read all segments and collect explicit IDs
-	var segments []*kire.Segment
-	maxExplicit := 0
+	dir, _ := cmd.Flags().GetString("dir")
+	jsonlPath, _ := cmd.Flags().GetString("jsonl")
 
+	metas, err := kire.ParseJSONL(jsonlPath)
+	if err != nil {
+		return err
+	}
+
+	// Phase 1: Read all segments
+	var validSegments []*kire.Segment
 	for _, meta := range metas {
 		seg, err := kire.ReadSegment(dir, meta)
 		if err != nil {
 			return err
 		}
-		segments = append(segments, seg) // nil segments are kept for index alignment
-
-		if seg != nil {
-			if id := kire.ExtractReqID(seg.Content); id != "" {
-				if m := batchReqIDPattern.FindStringSubmatch(id); len(m) == 2 {
-					n, _ := strconv.Atoi(m[1])
-					if n > maxExplicit {
-						maxExplicit = n
-					}
-				}
-			}
+		if seg == nil {
+			log.Warn("segment file not found, skipping", "segment_id", meta.SegmentID, "file", meta.FilePath)
+			fmt.Fprintf(cmd.OutOrStdout(), "warning: segment file not found: %s (%s)\n", meta.SegmentID, meta.FilePath)
+			continue
 		}
+		validSegments = append(validSegments, seg)
 	}
 
-	// Filter out nil segments
-	var validSegments []*kire.Segment
-	for i, seg := range segments {
-		if seg == nil {
-			log.Warn("segment file not found, skipping", "segment_id", metas[i].SegmentID, "file", metas[i].FilePath)
-			fmt.Fprintf(cmd.OutOrStdout(), "warning: segment file not found: %s (%s)\n", metas[i].SegmentID, metas[i].FilePath)
-		} else {
-			validSegments = append(validSegments, seg)
+	return runImportPipeline(cmd, opts, validSegments)
+}
+
+func runImportMarkdown(cmd *cobra.Command, args []string) error {
+	opts, err := prepareImport(cmd)
+	if err != nil {
+		return err
+	}
+
+	segments, err := kire.ReadMarkdown(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%d segments found in %s\n", len(segments), args[0])
+
+	return runImportPipeline(cmd, opts, segments)
+}
+
+// runImportPipeline converts segments into specs (regex extraction, optional
+// 1-pass or 2-pass enrichment, merge by REQ ID) and saves them.
+func runImportPipeline(cmd *cobra.Command, opts importOptions, validSegments []*kire.Segment) error {
+	log := GetLogger().WithComponent("import")
+
+	cfg := opts.cfg
+	force, dryRun := opts.force, opts.dryRun
+	enrichEnabled, batchMode := opts.enrichEnabled, opts.batchMode
+	enricher, batchEnricher := opts.enricher, opts.batchEnricher
+
+	if err := os.MkdirAll(cfg.SpecDir, 0755); err != nil {
+		return err
+	}
+
+	// Collect explicit IDs so auto-assigned IDs do not collide
+	maxExplicit := 0
+	for _, seg := range validSegments {
+		if id := kire.ExtractReqID(seg.Content); id != "" {
+			if n, ok := autoIDNumber(id); ok {
+				if n > maxExplicit {
+					maxExplicit = n
+				}
+			}
 		}
 	}
 
@@ -174,7 +242,7 @@ func runImportKire(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		if err := saveEntries(cmd, entries, cfg.SpecDir, force, dryRun); err != nil {
+		if err := saveEntries(cmd, entries, cfg.SpecDir, opts.namespace, force, dryRun); err != nil {
 			return err
 		}
 		if !dryRun {
@@ -236,11 +304,10 @@ func runImportKire(cmd *cobra.Command, args []string) error {
 			}
 			if id == "" {
 				autoNext++
-				id = fmt.Sprintf("REQ-%03d", autoNext)
+				id = autoID(autoNext)
 			} else {
 				// Track explicit ID
-				if m := batchReqIDPattern.FindStringSubmatch(id); len(m) == 2 {
-					n, _ := strconv.Atoi(m[1])
+				if n, ok := autoIDNumber(id); ok {
 					if n > maxExplicit {
 						maxExplicit = n
 						autoNext = maxExplicit
@@ -272,7 +339,7 @@ func runImportKire(cmd *cobra.Command, args []string) error {
 				ID:        id,
 				Title:     title,
 				Examples:  examples,
-				Questions: questions,
+				Questions: spec.QuestionsFromText(questions),
 				Source: spec.SourceInfo{
 					SegmentID:   seg.Meta.SegmentID,
 					HeadingPath: headingPath,
@@ -296,7 +363,7 @@ func runImportKire(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err := saveEntries(cmd, entries, cfg.SpecDir, force, dryRun); err != nil {
+	if err := saveEntries(cmd, entries, cfg.SpecDir, opts.namespace, force, dryRun); err != nil {
 		return err
 	}
 
@@ -368,10 +435,9 @@ func runBatchEnrich(cmd *cobra.Command, log interface{ Warn(string, ...any) }, b
 		}
 		if id == "" {
 			autoNext++
-			id = fmt.Sprintf("REQ-%03d", autoNext)
+			id = autoID(autoNext)
 		} else {
-			if m := batchReqIDPattern.FindStringSubmatch(id); len(m) == 2 {
-				n, _ := strconv.Atoi(m[1])
+			if n, ok := autoIDNumber(id); ok {
 				if n > maxExplicit {
 					maxExplicit = n
 					autoNext = maxExplicit
@@ -433,7 +499,7 @@ func runBatchEnrich(cmd *cobra.Command, log interface{ Warn(string, ...any) }, b
 			ID:        id,
 			Title:     title,
 			Examples:  examples,
-			Questions: questions,
+			Questions: spec.QuestionsFromText(questions),
 			Source: spec.SourceInfo{
 				SegmentID:   sid,
 				HeadingPath: headingPath,
@@ -503,42 +569,56 @@ func batchGenerateWithFallback(ctx context.Context, be enrich.BatchEnricher, tar
 }
 
 // saveEntries はエントリをファイルに保存する共通ヘルパー。
-func saveEntries(cmd *cobra.Command, entries []importEntry, specDir string, force, dryRun bool) error {
+// 新規 spec は namespace ns に作成し、既存 spec はその場所で上書きする。
+// 全ファイルを 1 つのトランザクションで書き込み、途中で失敗した場合はどのファイルも変更しない。
+func saveEntries(cmd *cobra.Command, entries []importEntry, specDir, ns string, force, dryRun bool) error {
 	var created, skipped, overwritten int
+	var report bytes.Buffer
+	tx := atomicfile.NewTx()
+	staged := make(map[string]bool)
 
 	for _, entry := range entries {
 		s := entry.spec
-		specPath := filepath.Join(specDir, s.ID+".yml")
+		specPath, err := spec.PathFor(specDir, ns, s.ID)
+		if err != nil {
+			return err
+		}
 
 		if dryRun {
 			fmt.Fprintf(cmd.OutOrStdout(), "[dry-run] %s: %s (%s)\n", s.ID, s.Title, specPath)
 			continue
 		}
 
+		// A path staged by an earlier entry counts as existing
 		_, statErr := os.Stat(specPath)
-		fileExists := statErr == nil
+		fileExists := statErr == nil || staged[specPath]
 
 		if fileExists && !force {
-			fmt.Fprintf(cmd.OutOrStdout(), "skip: %s already exists\n", specPath)
+			fmt.Fprintf(&report, "skip: %s already exists\n", specPath)
 			skipped++
 			continue
 		}
 
 		s.Normalize()
-		if err := spec.Save(specPath, s); err != nil {
+		if err := spec.Stage(tx, specPath, s); err != nil {
 			return err
 		}
+		staged[specPath] = true
 
 		if fileExists && force {
-			fmt.Fprintf(cmd.OutOrStdout(), "overwritten: %s\n", specPath)
+			fmt.Fprintf(&report, "overwritten: %s\n", specPath)
 			overwritten++
 		} else {
-			fmt.Fprintf(cmd.OutOrStdout(), "created: %s\n", specPath)
+			fmt.Fprintf(&report, "created: %s\n", specPath)
 			created++
 		}
 	}
 
 	if !dryRun {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), report.String())
 		fmt.Fprintf(cmd.OutOrStdout(), "\n%d created, %d skipped, %d overwritten\n", created, skipped, overwritten)
 	}
 
@@ -558,7 +638,7 @@ func buildEntryFromRegex(seg *kire.Segment, autoNext *int) importEntry {
 	id := kire.ExtractReqID(seg.Content)
 	if id == "" {
 		*autoNext++
-		id = fmt.Sprintf("REQ-%03d", *autoNext)
+		id = autoID(*autoNext)
 	}
 
 	examples := kire.ExtractExamples(seg.Content)
@@ -574,7 +654,7 @@ func buildEntryFromRegex(seg *kire.Segment, autoNext *int) importEntry {
 		ID:        id,
 		Title:     title,
 		Examples:  examples,
-		Questions: questions,
+		Questions: spec.QuestionsFromText(questions),
 		Source: spec.SourceInfo{
 			SegmentID:   seg.Meta.SegmentID,
 			HeadingPath: headingPath,
@@ -609,12 +689,12 @@ func mergeEntriesByReqID(entries []importEntry) []importEntry {
 			// Questions を結合（重複除去）
 			qSet := make(map[string]struct{}, len(existing.entry.spec.Questions))
 			for _, q := range existing.entry.spec.Questions {
-				qSet[q] = struct{}{}
+				qSet[q.Text] = struct{}{}
 			}
 			for _, q := range e.spec.Questions {
-				if _, dup := qSet[q]; !dup {
+				if _, dup := qSet[q.Text]; !dup {
 					existing.entry.spec.Questions = append(existing.entry.spec.Questions, q)
-					qSet[q] = struct{}{}
+					qSet[q.Text] = struct{}{}
 				}
 			}
 		} else {
@@ -623,7 +703,7 @@ func mergeEntriesByReqID(entries []importEntry) []importEntry {
 			exCopy := make([]spec.Example, len(e.spec.Examples))
 			copy(exCopy, e.spec.Examples)
 			specCopy.Examples = exCopy
-			qCopy := make([]string, len(e.spec.Questions))
+			qCopy := make([]spec.Question, len(e.spec.Questions))
 			copy(qCopy, e.spec.Questions)
 			specCopy.Questions = qCopy
 
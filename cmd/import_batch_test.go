@@ -535,7 +535,7 @@ func TestMergeEntriesByReqID(t *testing.T) {
 					ID:        "REQ-001",
 					Title:     "ログイン",
 					Examples:  []spec.Example{{ID: "E1", Given: "G1", When: "W1", Then: "T1"}},
-					Questions: []string{"Q1"},
+					Questions: spec.QuestionsFromText([]string{"Q1"}),
 				},
 			},
 			{
@@ -544,7 +544,7 @@ func TestMergeEntriesByReqID(t *testing.T) {
 					ID:        "REQ-002",
 					Title:     "ログアウト",
 					Examples:  []spec.Example{{ID: "E1", Given: "G2", When: "W2", Then: "T2"}},
-					Questions: []string{"Q2"},
+					Questions: spec.QuestionsFromText([]string{"Q2"}),
 				},
 			},
 			{
@@ -553,7 +553,7 @@ func TestMergeEntriesByReqID(t *testing.T) {
 					ID:        "REQ-001",
 					Title:     "ログイン (続き)",
 					Examples:  []spec.Example{{ID: "E1", Given: "G3", When: "W3", Then: "T3"}},
-					Questions: []string{"Q1", "Q3"},
+					Questions: spec.QuestionsFromText([]string{"Q1", "Q3"}),
 				},
 			},
 		}
@@ -587,7 +587,7 @@ func TestMergeEntriesByReqID(t *testing.T) {
 		if len(r1.spec.Questions) != 2 {
 			t.Fatalf("result[0].Questions count = %d, want 2", len(r1.spec.Questions))
 		}
-		if r1.spec.Questions[0] != "Q1" || r1.spec.Questions[1] != "Q3" {
+		if r1.spec.Questions[0].Text != "Q1" || r1.spec.Questions[1].Text != "Q3" {
 			t.Errorf("result[0].Questions = %v", r1.spec.Questions)
 		}
 
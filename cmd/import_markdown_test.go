@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/enrich"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func setupImportMarkdownTestDir(t *testing.T) string {
+	t.Helper()
+	tmpDir := setupWorkspace(t)
+
+	doc := `# Task API
+
+## 概要
+
+タスク管理APIの概要。
+
+## 認証
+
+### REQ-001: Login
+
+- Given: ユーザーが存在する
+- When: ログインする
+- Then: トークン返却
+
+セッション期限は？
+
+### Logout
+
+ログアウト機能。
+
+### REQ-001: Login (追加)
+
+- Given: パスワードが誤り
+- When: ログインする
+- Then: 401
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "spec.md"), []byte(doc), 0644); err != nil {
+		t.Fatalf("write doc error: %v", err)
+	}
+
+	return tmpDir
+}
+
+func TestImportMarkdownCommand(t *testing.T) {
+	t.Run("generates specs from a single Markdown file", func(t *testing.T) {
+		tmpDir := setupImportMarkdownTestDir(t)
+
+		var buf bytes.Buffer
+		importMarkdownCmd.SetOut(&buf)
+
+		if err := importMarkdownCmd.RunE(importMarkdownCmd, []string{"spec.md"}); err != nil {
+			t.Fatalf("importMarkdownCmd error: %v", err)
+		}
+
+		output := buf.String()
+		if !strings.Contains(output, "4 segments found") {
+			t.Errorf("expected segment count in output, got: %s", output)
+		}
+
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+		s1, err := spec.Load(filepath.Join(specDir, "REQ-001.yml"))
+		if err != nil {
+			t.Fatalf("Load REQ-001 error: %v", err)
+		}
+		if len(s1.Examples) != 1 {
+			t.Errorf("REQ-001 examples = %d, want 1", len(s1.Examples))
+		}
+		if len(s1.Questions) != 1 {
+			t.Errorf("REQ-001 questions = %d, want 1", len(s1.Questions))
+		}
+		if s1.Source.FilePath != "spec.md" {
+			t.Errorf("REQ-001 Source.FilePath = %q, want %q", s1.Source.FilePath, "spec.md")
+		}
+		if strings.Join(s1.Source.HeadingPath, " > ") != "Task API > 認証 > REQ-001: Login" {
+			t.Errorf("REQ-001 Source.HeadingPath = %v", s1.Source.HeadingPath)
+		}
+
+		// Auto-assigned IDs continue after the highest explicit ID
+		s3, err := spec.Load(filepath.Join(specDir, "REQ-003.yml"))
+		if err != nil {
+			t.Fatalf("Load REQ-003 error: %v", err)
+		}
+		if s3.Title != "Logout" {
+			t.Errorf("REQ-003 Title = %q, want %q", s3.Title, "Logout")
+		}
+	})
+
+	t.Run("batch mode merges segments with the same REQ ID", func(t *testing.T) {
+		tmpDir := setupImportMarkdownTestDir(t)
+
+		testBatchEnricher = &enrich.MockBatchEnricher{
+			ClassifyResults: []enrich.BatchClassifyResult{
+				{SegmentID: "seg-0000", Category: enrich.CategoryOverview, Title: "概要"},
+				{SegmentID: "seg-0001", Category: enrich.CategoryFunctionalRequirement, Title: "Login", ReqID: "REQ-001"},
+				{SegmentID: "seg-0002", Category: enrich.CategoryFunctionalRequirement, Title: "Logout"},
+				{SegmentID: "seg-0003", Category: enrich.CategoryFunctionalRequirement, Title: "Login", ReqID: "REQ-001"},
+			},
+		}
+		t.Cleanup(func() { testBatchEnricher = nil })
+
+		if err := importMarkdownCmd.Flags().Set("enrich", "true"); err != nil {
+			t.Fatalf("set enrich flag: %v", err)
+		}
+		if err := importMarkdownCmd.Flags().Set("enrich-example-model", "test-model"); err != nil {
+			t.Fatalf("set enrich-example-model flag: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = importMarkdownCmd.Flags().Set("enrich", "false")
+			_ = importMarkdownCmd.Flags().Set("enrich-example-model", "")
+		})
+
+		var buf bytes.Buffer
+		importMarkdownCmd.SetOut(&buf)
+
+		if err := importMarkdownCmd.RunE(importMarkdownCmd, []string{"spec.md"}); err != nil {
+			t.Fatalf("importMarkdownCmd error: %v", err)
+		}
+
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+		s1, err := spec.Load(filepath.Join(specDir, "REQ-001.yml"))
+		if err != nil {
+			t.Fatalf("Load REQ-001 error: %v", err)
+		}
+		if len(s1.Examples) != 2 {
+			t.Errorf("REQ-001 examples = %d, want 2 after merge", len(s1.Examples))
+		}
+		if _, err := os.Stat(filepath.Join(specDir, "REQ-002.yml")); err != nil {
+			t.Errorf("expected Logout to be auto-assigned REQ-002: %v", err)
+		}
+	})
+
+	t.Run("missing file returns error", func(t *testing.T) {
+		setupImportMarkdownTestDir(t)
+
+		var buf bytes.Buffer
+		importMarkdownCmd.SetOut(&buf)
+
+		if err := importMarkdownCmd.RunE(importMarkdownCmd, []string{"missing.md"}); err == nil {
+			t.Fatal("expected error for missing file")
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/kire"
+	"github.com/thirdlf03/spec-tdd/internal/openapi"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var importOpenAPICmd = &cobra.Command{
+	Use:   "openapi <file>",
+	Short: "Import specs from an OpenAPI 3 document (one spec per operation)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  withWorkspaceLock(runImportOpenAPI),
+}
+
+func init() {
+	importCmd.AddCommand(importOpenAPICmd)
+
+	importOpenAPICmd.Flags().Bool("force", false, "Overwrite existing spec files")
+	importOpenAPICmd.Flags().Bool("dry-run", false, "Preview without writing files")
+}
+
+func runImportOpenAPI(cmd *cobra.Command, args []string) error {
+	cfg, err := loadSpecConfig(cmd)
+	if err != nil {
+		return err
+	}
+	ns, err := importNamespace()
+	if err != nil {
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	doc, err := openapi.Load(args[0])
+	if err != nil {
+		return err
+	}
+	ops, err := doc.Operations()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%d operations found in %s\n", len(ops), args[0])
+
+	if err := os.MkdirAll(cfg.SpecDir, 0755); err != nil {
+		return err
+	}
+
+	existing, err := spec.LoadAll(cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+
+	ids, err := assignOpenAPIReqIDs(ops, existing)
+	if err != nil {
+		return err
+	}
+
+	sourceFile := filepath.Base(args[0])
+	entries := make([]importEntry, 0, len(ops))
+	for i, op := range ops {
+		entries = append(entries, importEntry{spec: openapi.ConvertToSpec(op, ids[i], sourceFile)})
+	}
+
+	return saveEntries(cmd, entries, cfg.SpecDir, ns, force, dryRun)
+}
+
+// assignOpenAPIReqIDs resolves a REQ ID for each operation so that re-imports
+// target the same files. Priority: x-req-id extension, then an existing spec
+// with the same operationId, then one imported from the same endpoint
+// ("METHOD /path", kept in Source.HeadingPath), then the next free number.
+func assignOpenAPIReqIDs(ops []openapi.Operation, existing []*spec.Spec) ([]string, error) {
+	byOperationID := make(map[string]string, len(existing))
+	byEndpoint := make(map[string]string, len(existing))
+	maxN := 0
+	trackMax := func(id string) {
+		if n, ok := autoIDNumber(id); ok && n > maxN {
+			maxN = n
+		}
+	}
+
+	for _, s := range existing {
+		trackMax(s.ID)
+		if s.Source.OperationID != "" {
+			byOperationID[s.Source.OperationID] = s.ID
+		}
+		if len(s.Source.HeadingPath) == 1 {
+			byEndpoint[s.Source.HeadingPath[0]] = s.ID
+		}
+	}
+	for _, op := range ops {
+		if op.ReqID == "" {
+			continue
+		}
+		if kire.ExtractReqID(op.ReqID) != strings.TrimSpace(op.ReqID) {
+			return nil, fmt.Errorf("%s: x-req-id %q must match %s", op.Endpoint(), op.ReqID, spec.CurrentIDScheme().Describe())
+		}
+		trackMax(op.ReqID)
+	}
+
+	ids := make([]string, len(ops))
+	used := make(map[string]string, len(ops))
+	for i, op := range ops {
+		id := strings.TrimSpace(op.ReqID)
+		if id == "" && op.OperationID != "" {
+			id = byOperationID[op.OperationID]
+		}
+		if id == "" {
+			id = byEndpoint[op.Endpoint()]
+		}
+		if id == "" {
+			maxN++
+			id = autoID(maxN)
+		}
+		if prev, dup := used[id]; dup {
+			return nil, fmt.Errorf("%s and %s both map to %s", prev, op.Endpoint(), id)
+		}
+		used[id] = op.Endpoint()
+		ids[i] = id
+	}
+	return ids, nil
+}
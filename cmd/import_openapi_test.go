@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+const testOpenAPIDoc = `openapi: 3.0.3
+paths:
+  /v1/tasks:
+    post:
+      operationId: createTask
+      summary: Create a task
+      requestBody:
+        content:
+          application/json:
+            example: {title: "Buy milk"}
+      responses:
+        "201":
+          description: Created
+        "422":
+          description: Validation error
+  /v1/tasks/{id}:
+    get:
+      operationId: getTask
+      x-req-id: REQ-010
+      responses:
+        "200":
+          description: OK
+        "404":
+          description: Not found
+`
+
+func setupImportOpenAPITestDir(t *testing.T) string {
+	t.Helper()
+	tmpDir := setupWorkspace(t)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "api.yaml"), []byte(testOpenAPIDoc), 0644); err != nil {
+		t.Fatalf("write doc error: %v", err)
+	}
+	return tmpDir
+}
+
+func TestImportOpenAPICommand(t *testing.T) {
+	t.Run("creates one spec per operation", func(t *testing.T) {
+		tmpDir := setupImportOpenAPITestDir(t)
+
+		var buf bytes.Buffer
+		importOpenAPICmd.SetOut(&buf)
+
+		if err := importOpenAPICmd.RunE(importOpenAPICmd, []string{"api.yaml"}); err != nil {
+			t.Fatalf("importOpenAPICmd error: %v", err)
+		}
+
+		output := buf.String()
+		if !strings.Contains(output, "2 created") {
+			t.Errorf("expected '2 created' in output, got: %s", output)
+		}
+
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+		get, err := spec.Load(filepath.Join(specDir, "REQ-010.yml"))
+		if err != nil {
+			t.Fatalf("Load REQ-010 error: %v", err)
+		}
+		if get.Source.OperationID != "getTask" {
+			t.Errorf("OperationID = %q, want getTask", get.Source.OperationID)
+		}
+
+		// Auto-assigned after the highest x-req-id
+		post, err := spec.Load(filepath.Join(specDir, "REQ-011.yml"))
+		if err != nil {
+			t.Fatalf("Load REQ-011 error: %v", err)
+		}
+		if len(post.Examples) != 2 {
+			t.Fatalf("expected 2 examples, got %d", len(post.Examples))
+		}
+		ex := post.Examples[0]
+		if ex.Given != `valid body {"title":"Buy milk"}` || ex.When != "POST /v1/tasks" || ex.Then != "201 (Created)" {
+			t.Errorf("unexpected example: %+v", ex)
+		}
+	})
+
+	t.Run("re-import matches by operationId and skips", func(t *testing.T) {
+		tmpDir := setupImportOpenAPITestDir(t)
+
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+		if err := spec.Save(filepath.Join(specDir, "REQ-003.yml"), &spec.Spec{
+			ID: "REQ-003", Title: "Existing", Source: spec.SourceInfo{OperationID: "createTask"},
+		}); err != nil {
+			t.Fatalf("save existing error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importOpenAPICmd.SetOut(&buf)
+
+		if err := importOpenAPICmd.RunE(importOpenAPICmd, []string{"api.yaml"}); err != nil {
+			t.Fatalf("importOpenAPICmd error: %v", err)
+		}
+
+		output := buf.String()
+		if !strings.Contains(output, "1 created, 1 skipped") {
+			t.Errorf("expected '1 created, 1 skipped', got: %s", output)
+		}
+		if _, err := os.Stat(filepath.Join(specDir, "REQ-011.yml")); err == nil {
+			t.Error("createTask should map to existing REQ-003, not a new ID")
+		}
+	})
+
+	t.Run("re-import matches operations without operationId by endpoint", func(t *testing.T) {
+		tmpDir := setupImportOpenAPITestDir(t)
+		doc := strings.Replace(testOpenAPIDoc, "      operationId: createTask\n", "", 1)
+		if err := os.WriteFile(filepath.Join(tmpDir, "api.yaml"), []byte(doc), 0644); err != nil {
+			t.Fatalf("write doc error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importOpenAPICmd.SetOut(&buf)
+		for _, want := range []string{"2 created", "0 created, 2 skipped"} {
+			buf.Reset()
+			if err := importOpenAPICmd.RunE(importOpenAPICmd, []string{"api.yaml"}); err != nil {
+				t.Fatalf("importOpenAPICmd error: %v", err)
+			}
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("expected %q, got: %s", want, buf.String())
+			}
+		}
+		files, _ := spec.ListFiles(filepath.Join(tmpDir, ".tdd", "specs"))
+		if len(files) != 2 {
+			t.Errorf("expected no duplicate specs, got %v", files)
+		}
+	})
+
+	t.Run("force overwrites matched spec", func(t *testing.T) {
+		tmpDir := setupImportOpenAPITestDir(t)
+
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+		if err := spec.Save(filepath.Join(specDir, "REQ-003.yml"), &spec.Spec{
+			ID: "REQ-003", Title: "Existing", Source: spec.SourceInfo{OperationID: "createTask"},
+		}); err != nil {
+			t.Fatalf("save existing error: %v", err)
+		}
+
+		if err := importOpenAPICmd.Flags().Set("force", "true"); err != nil {
+			t.Fatalf("set force flag: %v", err)
+		}
+		defer func() {
+			_ = importOpenAPICmd.Flags().Set("force", "false")
+		}()
+
+		var buf bytes.Buffer
+		importOpenAPICmd.SetOut(&buf)
+
+		if err := importOpenAPICmd.RunE(importOpenAPICmd, []string{"api.yaml"}); err != nil {
+			t.Fatalf("importOpenAPICmd error: %v", err)
+		}
+
+		s, err := spec.Load(filepath.Join(specDir, "REQ-003.yml"))
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		if s.Title != "Create a task" {
+			t.Errorf("expected overwritten title, got %q", s.Title)
+		}
+	})
+
+	t.Run("dry-run does not write files", func(t *testing.T) {
+		tmpDir := setupImportOpenAPITestDir(t)
+
+		if err := importOpenAPICmd.Flags().Set("dry-run", "true"); err != nil {
+			t.Fatalf("set dry-run flag: %v", err)
+		}
+		defer func() {
+			_ = importOpenAPICmd.Flags().Set("dry-run", "false")
+		}()
+
+		var buf bytes.Buffer
+		importOpenAPICmd.SetOut(&buf)
+
+		if err := importOpenAPICmd.RunE(importOpenAPICmd, []string{"api.yaml"}); err != nil {
+			t.Fatalf("importOpenAPICmd error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, ".tdd", "specs", "REQ-010.yml")); err == nil {
+			t.Fatal("expected no file in dry-run mode")
+		}
+	})
+}
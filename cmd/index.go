@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Rebuild or clear the spec index cache",
+	Long: fmt.Sprintf(`Rebuild the spec index cache (%s).
+
+Commands cache parsed spec files in the index, keyed by path, size and
+modification time, and only parse files that changed (in parallel). The index
+updates itself, so this command is only needed to warm it up (e.g. in CI) or,
+with --clear, to remove it.`, config.DefaultIndexPath),
+	Args: cobra.NoArgs,
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("index")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := spec.ClearIndex(); err != nil {
+			log.Error("Failed to remove index", "path", config.DefaultIndexPath, "error", err)
+			return err
+		}
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", config.DefaultIndexPath)
+			return nil
+		}
+
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "indexed %d spec(s) in %s\n", len(specs), config.DefaultIndexPath)
+		return nil
+	}),
+}
+
+func init() {
+	rootCmd.AddCommand(indexCmd)
+
+	indexCmd.Flags().Bool("clear", false, "Remove the index instead of rebuilding it")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestIndexCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, indexCmd)
+	t.Cleanup(func() { spec.SetIndexPath("") })
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir, &spec.Spec{ID: "REQ-001", Title: "Login"}, &spec.Spec{ID: "REQ-002", Title: "Logout"})
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"REQ-001.yml", "REQ-002.yml"} {
+		if err := os.Chtimes(filepath.Join(specDir, name), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	indexPath := filepath.Join(tmpDir, ".tdd", "index.json")
+
+	var buf bytes.Buffer
+	indexCmd.SetOut(&buf)
+	if err := indexCmd.RunE(indexCmd, nil); err != nil {
+		t.Fatalf("index error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "indexed 2 spec(s)") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	data, err := os.ReadFile(indexPath)
+	if err != nil || !strings.Contains(string(data), "REQ-002.yml") {
+		t.Fatalf("index not written: %v", err)
+	}
+
+	_ = indexCmd.Flags().Set("clear", "true")
+	if err := indexCmd.RunE(indexCmd, nil); err != nil {
+		t.Fatalf("index --clear error: %v", err)
+	}
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Errorf("index should be removed, got %v", err)
+	}
+}
@@ -13,7 +13,7 @@ import (
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize spec-tdd workspace",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
 		log := GetLogger().WithComponent("init")
 
 		cfg := config.DefaultSpecConfig()
@@ -28,6 +28,12 @@ var initCmd = &cobra.Command{
 			return err
 		}
 
+		// The spec index, the workspace lock and the journal are local state
+		if err := config.EnsureGitignore(config.DefaultGitignorePath); err != nil {
+			log.Error("Failed to write .gitignore", "path", config.DefaultGitignorePath, "error", err)
+			return err
+		}
+
 		if _, err := os.Stat(config.DefaultSpecConfigPath); err == nil {
 			fmt.Fprintf(cmd.OutOrStdout(), "config already exists: %s\n", config.DefaultSpecConfigPath)
 			return nil
@@ -43,7 +49,7 @@ var initCmd = &cobra.Command{
 		fmt.Fprintf(cmd.OutOrStdout(), "created %s\n", config.DefaultSpecConfigPath)
 		fmt.Fprintf(cmd.OutOrStdout(), "created %s\n", cfg.SpecDir)
 		return nil
-	},
+	}),
 }
 
 func init() {
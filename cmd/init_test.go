@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/journal"
 )
 
 func TestInitCommand(t *testing.T) {
@@ -29,4 +33,45 @@ func TestInitCommand(t *testing.T) {
 	if _, err := os.Stat(filepath.Join(tmpDir, ".tdd", "specs")); err != nil {
 		t.Fatalf("expected specs dir, got %v", err)
 	}
+	if data, err := os.ReadFile(filepath.Join(tmpDir, ".tdd", ".gitignore")); err != nil || string(data) != "index.json\nlock\njournal/\n" {
+		t.Fatalf("expected .gitignore for the spec index, got %q, %v", data, err)
+	}
+}
+
+func TestGitignoreForExistingWorkspace(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	gitignore := filepath.Join(tmpDir, ".tdd", ".gitignore")
+	t.Cleanup(func() { reqAddTitle = "" })
+
+	// A workspace from before `init` wrote .gitignore
+	reqAddTitle = "Login"
+	reqAddCmd.SetOut(&bytes.Buffer{})
+	if err := reqAddCmd.RunE(reqAddCmd, nil); err != nil {
+		t.Fatalf("req add error: %v", err)
+	}
+	if data, err := os.ReadFile(gitignore); err != nil || string(data) != "index.json\nlock\njournal/\n" {
+		t.Errorf(".gitignore = %q, %v", data, err)
+	}
+	ops, err := journal.Load(config.DefaultJournalDir)
+	if err != nil || len(ops) != 1 {
+		t.Fatalf("journal = %v, %v", ops, err)
+	}
+	for _, c := range ops[0].Changes {
+		if filepath.Base(c.Path) == ".gitignore" {
+			t.Errorf(".gitignore should not be journaled: %+v", ops[0].Changes)
+		}
+	}
+
+	// Missing entries are appended to a .gitignore of the user
+	if err := os.WriteFile(gitignore, []byte("reports/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resetCmdFlags(t, reqListCmd)
+	reqListCmd.SetOut(&bytes.Buffer{})
+	if err := reqListCmd.RunE(reqListCmd, nil); err != nil {
+		t.Fatalf("req list error: %v", err)
+	}
+	if data, _ := os.ReadFile(gitignore); string(data) != "reports/\nindex.json\nlock\njournal/\n" {
+		t.Errorf(".gitignore = %q", data)
+	}
 }
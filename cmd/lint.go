@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/lint"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [REQ-ID...]",
+	Short: "Check requirement and example text against quality rules",
+	Long: `Check requirement and example text for vague words, unobservable Then
+clauses, duplicate examples, long titles and Given clauses without concrete
+state. Rules can be disabled or re-graded under "lint" in .tdd/config.yml.`,
+	RunE: runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().String("format", lint.FormatText, "Output format: text, json or sarif")
+	lintCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	lintCmd.Flags().String("fail-on", string(lint.SeverityError), "Exit with an error at this severity or above: error, warning, info or none")
+	lintCmd.Flags().Bool("list-rules", false, "List active rules and exit")
+	lintCmd.Flags().StringSlice("status", nil, "Only lint specs in these statuses (default: all but excluded, e.g. deprecated)")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	listRules, _ := cmd.Flags().GetBool("list-rules")
+	statuses, _ := cmd.Flags().GetStringSlice("status")
+
+	var threshold lint.Severity
+	if failOn != "none" {
+		sev, err := lint.ParseSeverity(failOn)
+		if err != nil {
+			return err
+		}
+		threshold = sev
+	}
+
+	cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	linter, err := lint.New(cfg.Lint)
+	if err != nil {
+		return err
+	}
+
+	if listRules {
+		for _, r := range linter.Rules() {
+			fmt.Fprintf(cmd.OutOrStdout(), "%-18s %-8s %s\n", r.ID, r.Severity, r.Description)
+		}
+		return nil
+	}
+
+	specs, err := spec.LoadAll(cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+	specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+	if err != nil {
+		return err
+	}
+	specs, _, err = filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		specs, err = selectSpecs(specs, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	findings := linter.Lint(specs)
+	locateFindings(findings, specs)
+
+	var buf bytes.Buffer
+	var w io.Writer = cmd.OutOrStdout()
+	if output != "" {
+		w = &buf
+	}
+	if err := lint.Write(w, format, findings, linter.Rules()); err != nil {
+		return err
+	}
+	if output != "" {
+		if err := atomicfile.WriteFile(output, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	if threshold != "" {
+		if n := lint.Count(findings, threshold); n > 0 {
+			return fmt.Errorf("lint found %d problem(s) at %s or above", n, threshold)
+		}
+	}
+	return nil
+}
+
+// locateFindings fills in the spec file and line of each finding.
+func locateFindings(findings []lint.Finding, specs []*spec.Spec) {
+	paths := make(map[string]string, len(specs))
+	for _, s := range specs {
+		paths[s.ID] = s.Path
+	}
+	files := make(map[string][]byte)
+	for i := range findings {
+		f := &findings[i]
+		f.File = paths[f.SpecID]
+		data, ok := files[f.File]
+		if !ok {
+			data, _ = os.ReadFile(f.File)
+			files[f.File] = data
+		}
+		f.Line = lint.Locate(data, f.Field)
+	}
+}
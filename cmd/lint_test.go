@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func resetLintFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		for _, name := range []string{"format", "output", "fail-on", "list-rules"} {
+			f := lintCmd.Flags().Lookup(name)
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+		status := lintCmd.Flags().Lookup("status")
+		_ = status.Value.(pflag.SliceValue).Replace(nil)
+		status.Changed = false
+	})
+}
+
+func TestLintCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetLintFlags(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	for _, s := range []*spec.Spec{
+		{ID: "REQ-001", Title: "Search is fast", Examples: []spec.Example{
+			{ID: "E1", Given: "index with 3 documents", When: "search for 'go'", Then: "it works"},
+		}},
+		{ID: "REQ-002", Title: "Logout", Examples: []spec.Example{
+			{ID: "E1", Given: "signed-in user", When: "clicks logout", Then: "session cookie is cleared"},
+		}},
+	} {
+		if err := spec.Save(filepath.Join(specDir, s.ID+".yml"), s); err != nil {
+			t.Fatalf("save spec error: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	lintCmd.SetOut(&buf)
+	lintCmd.SetErr(&bytes.Buffer{})
+
+	if err := lintCmd.RunE(lintCmd, nil); err != nil {
+		t.Fatalf("warnings should not fail with default --fail-on: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		filepath.Join(".tdd", "specs", "REQ-001.yml") + ":3: warning [vague-words] title",
+		"warning [unobservable-then] examples[E1].then",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "REQ-002") {
+		t.Errorf("REQ-002 should be clean, got:\n%s", out)
+	}
+
+	buf.Reset()
+	_ = lintCmd.Flags().Set("fail-on", "warning")
+	if err := lintCmd.RunE(lintCmd, nil); err == nil {
+		t.Error("expected error with --fail-on warning")
+	}
+	if err := lintCmd.RunE(lintCmd, []string{"REQ-002"}); err != nil {
+		t.Errorf("expected REQ-002 alone to pass, got %v", err)
+	}
+
+	sarifPath := filepath.Join(tmpDir, "lint.sarif")
+	_ = lintCmd.Flags().Set("fail-on", "none")
+	_ = lintCmd.Flags().Set("format", "sarif")
+	_ = lintCmd.Flags().Set("output", sarifPath)
+	if err := lintCmd.RunE(lintCmd, nil); err != nil {
+		t.Fatalf("sarif run error: %v", err)
+	}
+	data, err := os.ReadFile(sarifPath)
+	if err != nil {
+		t.Fatalf("read sarif error: %v", err)
+	}
+	var log map[string]any
+	if err := json.Unmarshal(data, &log); err != nil || log["version"] != "2.1.0" {
+		t.Errorf("unexpected sarif output: %s (%v)", data, err)
+	}
+}
+
+func TestLintLocatesSpecFiles(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetLintFlags(t)
+	// Neither the extension nor the file name has to match the ID
+	path := filepath.Join(tmpDir, ".tdd", "specs", "auth", "login.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := spec.Save(path, &spec.Spec{ID: "REQ-001", Title: "Search is fast"}); err != nil {
+		t.Fatalf("save spec error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	lintCmd.SetOut(&buf)
+	lintCmd.SetErr(&bytes.Buffer{})
+	if err := lintCmd.RunE(lintCmd, nil); err != nil {
+		t.Fatalf("lint error: %v", err)
+	}
+	if want := filepath.Join(".tdd", "specs", "auth", "login.yaml") + ":3: warning [vague-words] title"; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+	}
+}
+
+func TestLintCommandConfig(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetLintFlags(t)
+	s := &spec.Spec{ID: "REQ-001", Title: "Search is fast"}
+	if err := spec.Save(filepath.Join(tmpDir, ".tdd", "specs", "REQ-001.yml"), s); err != nil {
+		t.Fatalf("save spec error: %v", err)
+	}
+	cfg := "specDir: .tdd/specs\nlint:\n  severity:\n    vague-words: error\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tdd", "config.yml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("write config error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	lintCmd.SetOut(&buf)
+	lintCmd.SetErr(&bytes.Buffer{})
+	if err := lintCmd.RunE(lintCmd, nil); err == nil {
+		t.Error("expected error after raising vague-words to error")
+	}
+
+	buf.Reset()
+	_ = lintCmd.Flags().Set("list-rules", "true")
+	if err := lintCmd.RunE(lintCmd, nil); err != nil {
+		t.Fatalf("list-rules error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "vague-words") || !strings.Contains(buf.String(), "error") {
+		t.Errorf("unexpected rule list:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/journal"
+)
+
+// journalTimeFormat is how log and undo print operation times.
+const journalTimeFormat = "2006-01-02 15:04:05"
+
+// logItem is one operation in `log --format json`.
+type logItem struct {
+	ID      int           `json:"id"`
+	Command string        `json:"command"`
+	Time    time.Time     `json:"time"`
+	Undoes  int           `json:"undoes,omitempty"`
+	Undone  bool          `json:"undone"`
+	Files   []logItemFile `json:"files"`
+}
+
+type logItemFile struct {
+	Path       string `json:"path"`
+	Change     string `json:"change"`
+	BeforeHash string `json:"beforeHash,omitempty"`
+	AfterHash  string `json:"afterHash,omitempty"`
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List the operations recorded in the journal",
+	Long: fmt.Sprintf(`List the commands that modified the workspace, newest first, as recorded in
+the journal (%s). Each operation shows the files it changed with
+--files; --format json also includes the content hashes.
+
+The journal keeps the last %d operations. Use "undo" to revert the last one.`, config.DefaultJournalDir, journal.MaxOperations),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q (allowed: text, json)", format)
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		showFiles, _ := cmd.Flags().GetBool("files")
+
+		ops, err := journal.Load(config.DefaultJournalDir)
+		if err != nil {
+			return err
+		}
+		undone := journal.Undone(ops)
+		items := make([]logItem, 0, len(ops))
+		for i := len(ops) - 1; i >= 0 && (limit <= 0 || len(items) < limit); i-- {
+			op := ops[i]
+			item := logItem{ID: op.ID, Command: op.Command, Time: op.Time, Undoes: op.Undoes, Undone: undone[op.ID], Files: []logItemFile{}}
+			for _, c := range op.Changes {
+				item.Files = append(item.Files, logItemFile{Path: c.Path, Change: c.Kind(), BeforeHash: c.BeforeHash, AfterHash: c.AfterHash})
+			}
+			items = append(items, item)
+		}
+
+		w := cmd.OutOrStdout()
+		if format == "json" {
+			data, err := json.MarshalIndent(items, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, string(data))
+			return nil
+		}
+		if len(items) == 0 {
+			fmt.Fprintln(w, "no operations recorded")
+			return nil
+		}
+		for _, it := range items {
+			note := ""
+			if it.Undoes > 0 {
+				note = fmt.Sprintf(", undoes #%d", it.Undoes)
+			}
+			if it.Undone {
+				note += ", undone"
+			}
+			fmt.Fprintf(w, "#%d  %s  %s  (%d file(s)%s)\n", it.ID, it.Time.Local().Format(journalTimeFormat), it.Command, len(it.Files), note)
+			if showFiles {
+				for _, f := range it.Files {
+					fmt.Fprintf(w, "    %-8s  %s\n", f.Change, f.Path)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+
+	logCmd.Flags().String("format", "text", "Output format (text or json)")
+	logCmd.Flags().IntP("limit", "n", 0, "Show only the newest N operations (0 = all)")
+	logCmd.Flags().Bool("files", false, "List the files each operation changed")
+}
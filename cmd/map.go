@@ -7,18 +7,20 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
 	"github.com/thirdlf03/spec-tdd/internal/config"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"github.com/thirdlf03/spec-tdd/internal/trace"
 )
 
 func hasSource(s *spec.Spec) bool {
-	return s.Source.SegmentID != "" || len(s.Source.HeadingPath) > 0 || s.Source.FilePath != ""
+	return s.Source.SegmentID != "" || len(s.Source.HeadingPath) > 0 || s.Source.FilePath != "" || s.Source.OperationID != ""
 }
 
 var mapCmd = &cobra.Command{
 	Use:   "map",
 	Short: "Generate example mapping report",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: withReportLock(func(cmd *cobra.Command, args []string) error {
 		log := GetLogger().WithComponent("map")
 
 		cfg, err := loadSpecConfig(cmd)
@@ -30,6 +32,22 @@ var mapCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if err := spec.ValidateHierarchy(specs); err != nil {
+			return err
+		}
+		specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+		if err != nil {
+			return err
+		}
+		statuses, _ := cmd.Flags().GetStringSlice("status")
+		specs, _, err = filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+		if err != nil {
+			return err
+		}
+		specs, err = filterSpecsBySelect(cmd, specs, cfg.EffectiveLifecycle())
+		if err != nil {
+			return err
+		}
 
 		outputDir := filepath.Dir(config.DefaultSpecConfigPath)
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -37,27 +55,74 @@ var mapCmd = &cobra.Command{
 			return err
 		}
 
+		counts, err := trace.CountTestsByReq(cfg.TestDir)
+		if err != nil {
+			return err
+		}
+
 		outputPath := filepath.Join(outputDir, "map.md")
-		content := renderMapMarkdown(specs)
-		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		content := renderMapMarkdown(specs, counts)
+		if err := atomicfile.WriteFile(outputPath, []byte(content), 0644); err != nil {
 			log.Error("Failed to write map", "path", outputPath, "error", err)
 			return err
 		}
 
 		fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\n", outputPath)
 		return nil
-	},
+	}),
 }
 
 func init() {
 	rootCmd.AddCommand(mapCmd)
+
+	mapCmd.Flags().StringSlice("status", nil, "Only include specs in these statuses (default: all but excluded, e.g. deprecated)")
+	addSelectFlag(mapCmd)
 }
 
-func renderMapMarkdown(specs []*spec.Spec) string {
+// renderMapMarkdown renders the example map. counts are test counts by REQ
+// ID, used to roll coverage up the requirement hierarchy.
+func renderMapMarkdown(specs []*spec.Spec, counts map[string]int) string {
 	var sb strings.Builder
 	sb.WriteString("# Example Mapping\n\n")
+
+	report := trace.BuildReport(specs, counts)
+	report.AddHierarchy(specs)
+	if tree := report.HierarchyMarkdown(); tree != "" {
+		sb.WriteString(tree)
+		sb.WriteString("\n")
+	}
+	hierarchy := spec.NewHierarchy(specs)
+
+	// Open questions block progress, so they are listed before everything else
+	var open []string
+	for _, s := range specs {
+		for _, q := range s.OpenQuestions() {
+			open = append(open, fmt.Sprintf("- **%s %s**: %s%s\n", s.ID, questionLabel(q), q.Text, ownerSuffix(q)))
+		}
+	}
+	if len(open) > 0 {
+		sb.WriteString(fmt.Sprintf("## Open Questions (%d)\n\n", len(open)))
+		for _, line := range open {
+			sb.WriteString(line)
+		}
+		sb.WriteString("\n")
+	}
+
 	for _, s := range specs {
 		sb.WriteString(fmt.Sprintf("## %s: %s\n\n", s.ID, s.Title))
+		if s.Status != "" {
+			sb.WriteString(fmt.Sprintf("Status: %s\n\n", s.Status))
+		}
+		if s.Parent != "" {
+			sb.WriteString(fmt.Sprintf("Parent: %s\n\n", s.Parent))
+		}
+		if children := hierarchy.Children(s.ID); len(children) > 0 {
+			ids := make([]string, 0, len(children))
+			for _, c := range children {
+				ids = append(ids, c.ID)
+			}
+			sb.WriteString(fmt.Sprintf("Children: %s\n\n", strings.Join(ids, ", ")))
+		}
 		if strings.TrimSpace(s.Description) != "" {
 			sb.WriteString(s.Description)
 			sb.WriteString("\n\n")
@@ -69,17 +134,39 @@ func renderMapMarkdown(specs []*spec.Spec) string {
 			if s.Source.FilePath != "" {
 				src += fmt.Sprintf(", file_path=%s", s.Source.FilePath)
 			}
+			if s.Source.OperationID != "" {
+				src += fmt.Sprintf(", operation_id=%s", s.Source.OperationID)
+			}
 			sb.WriteString(src + "\n\n")
 		}
 
-		if len(s.Examples) > 0 {
-			sb.WriteString("Examples:\n")
-			for _, ex := range s.Examples {
-				id := ex.ID
+		// Story (the requirement) → rules → examples → questions, as on the table
+		if len(s.Rules) > 0 {
+			sb.WriteString("Rules:\n")
+			for _, r := range s.Rules {
+				id := r.ID
 				if id == "" {
-					id = "E?"
+					id = "R?"
+				}
+				sb.WriteString(fmt.Sprintf("- **%s**: %s\n", id, r.Text))
+				if len(r.Examples) == 0 {
+					sb.WriteString("  - _no examples yet_\n")
 				}
-				sb.WriteString(fmt.Sprintf("- %s: Given %s / When %s / Then %s\n", id, ex.Given, ex.When, ex.Then))
+				for _, ex := range r.Examples {
+					sb.WriteString("  " + mapExampleLine(ex))
+				}
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(s.Examples) > 0 {
+			if len(s.Rules) > 0 {
+				sb.WriteString("Other examples:\n")
+			} else {
+				sb.WriteString("Examples:\n")
+			}
+			for _, ex := range s.Examples {
+				sb.WriteString(mapExampleLine(ex))
 			}
 			sb.WriteString("\n")
 		}
@@ -87,10 +174,40 @@ func renderMapMarkdown(specs []*spec.Spec) string {
 		if len(s.Questions) > 0 {
 			sb.WriteString("Questions:\n")
 			for _, q := range s.Questions {
-				sb.WriteString(fmt.Sprintf("- %s\n", q))
+				if q.IsOpen() {
+					sb.WriteString(fmt.Sprintf("- [open] %s: %s%s\n", questionLabel(q), q.Text, ownerSuffix(q)))
+					continue
+				}
+				line := fmt.Sprintf("- [resolved] %s: %s", questionLabel(q), q.Text)
+				if q.Answer != "" {
+					line += " → " + q.Answer
+				}
+				sb.WriteString(line + "\n")
 			}
 			sb.WriteString("\n")
 		}
 	}
 	return sb.String()
 }
+
+func mapExampleLine(ex spec.Example) string {
+	id := ex.ID
+	if id == "" {
+		id = "E?"
+	}
+	return fmt.Sprintf("- %s: Given %s / When %s / Then %s\n", id, ex.Given, ex.When, ex.Then)
+}
+
+func questionLabel(q spec.Question) string {
+	if q.ID == "" {
+		return "Q?"
+	}
+	return q.ID
+}
+
+func ownerSuffix(q spec.Question) string {
+	if q.Owner == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (owner: %s)", q.Owner)
+}
@@ -23,7 +23,7 @@ func TestRenderMapMarkdown_WithSource(t *testing.T) {
 			},
 		}
 
-		output := renderMapMarkdown(specs)
+		output := renderMapMarkdown(specs, nil)
 
 		if !strings.Contains(output, "Source: segment_id=seg-001") {
 			t.Errorf("expected source info in output, got:\n%s", output)
@@ -46,13 +46,32 @@ func TestRenderMapMarkdown_WithSource(t *testing.T) {
 			},
 		}
 
-		output := renderMapMarkdown(specs)
+		output := renderMapMarkdown(specs, nil)
 
 		if !strings.Contains(output, "file_path=seg-0001.md") {
 			t.Errorf("expected file_path in output, got:\n%s", output)
 		}
 	})
 
+	t.Run("source info includes operation_id when present", func(t *testing.T) {
+		specs := []*spec.Spec{
+			{
+				ID:    "REQ-001",
+				Title: "Create a task",
+				Source: spec.SourceInfo{
+					OperationID: "createTask",
+					HeadingPath: []string{"POST /v1/tasks"},
+				},
+			},
+		}
+
+		output := renderMapMarkdown(specs, nil)
+
+		if !strings.Contains(output, "operation_id=createTask") {
+			t.Errorf("expected operation_id in output, got:\n%s", output)
+		}
+	})
+
 	t.Run("source info is omitted when zero value", func(t *testing.T) {
 		specs := []*spec.Spec{
 			{
@@ -64,10 +83,90 @@ func TestRenderMapMarkdown_WithSource(t *testing.T) {
 			},
 		}
 
-		output := renderMapMarkdown(specs)
+		output := renderMapMarkdown(specs, nil)
 
 		if strings.Contains(output, "Source:") {
 			t.Errorf("expected no source info for zero value, got:\n%s", output)
 		}
 	})
 }
+
+func TestRenderMapMarkdown_Questions(t *testing.T) {
+	specs := []*spec.Spec{
+		{
+			ID:    "REQ-001",
+			Title: "Login",
+			Questions: []spec.Question{
+				{ID: "Q1", Text: "Lockout threshold?", Owner: "security", Status: spec.QuestionOpen},
+				{ID: "Q2", Text: "Remember me?", Status: spec.QuestionResolved, Answer: "no"},
+			},
+		},
+	}
+
+	output := renderMapMarkdown(specs, nil)
+
+	summary := strings.Index(output, "## Open Questions (1)")
+	detail := strings.Index(output, "## REQ-001")
+	if summary < 0 || summary > detail {
+		t.Errorf("expected open questions summary before specs, got:\n%s", output)
+	}
+	for _, want := range []string{
+		"- **REQ-001 Q1**: Lockout threshold? (owner: security)",
+		"- [open] Q1: Lockout threshold? (owner: security)",
+		"- [resolved] Q2: Remember me? → no",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderMapMarkdown_Rules(t *testing.T) {
+	specs := []*spec.Spec{
+		{
+			ID:       "REQ-001",
+			Title:    "Withdraw cash",
+			Examples: []spec.Example{{ID: "E1", Given: "a", When: "b", Then: "c"}},
+			Rules: []spec.Rule{
+				{ID: "R1", Text: "Balance must cover the amount", Examples: []spec.Example{
+					{ID: "E2", Given: "balance 10", When: "withdraw 50", Then: "rejected"},
+				}},
+				{ID: "R2", Text: "Daily limit applies"},
+			},
+		},
+	}
+
+	output := renderMapMarkdown(specs, nil)
+
+	for _, want := range []string{
+		"Rules:\n- **R1**: Balance must cover the amount\n  - E2: Given balance 10 / When withdraw 50 / Then rejected\n",
+		"- **R2**: Daily limit applies\n  - _no examples yet_\n",
+		"Other examples:\n- E1: Given a / When b / Then c\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderMapMarkdown_Hierarchy(t *testing.T) {
+	ex := spec.Example{ID: "E1", Given: "a", When: "b", Then: "c"}
+	specs := []*spec.Spec{
+		{ID: "REQ-001", Title: "Checkout"},
+		{ID: "REQ-002", Title: "Cart", Parent: "REQ-001", Examples: []spec.Example{ex}},
+		{ID: "REQ-003", Title: "Payment", Parent: "REQ-001", Examples: []spec.Example{ex}},
+	}
+
+	output := renderMapMarkdown(specs, map[string]int{"REQ-002": 1})
+
+	for _, want := range []string{
+		"## Hierarchy\n\n- REQ-001 Checkout — 1/3 requirements covered, 1/2 examples (PARTIAL)\n",
+		"  - REQ-003 Payment — 0/1 examples (MISSING)\n",
+		"## REQ-001: Checkout\n\nChildren: REQ-002, REQ-003\n",
+		"## REQ-002: Cart\n\nParent: REQ-001\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
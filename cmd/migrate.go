@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/migrate"
+	"github.com/thirdlf03/spec-tdd/internal/rename"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade spec files and .tdd/config.yml to the current format",
+	Long: fmt.Sprintf(`Upgrade spec files and .tdd/config.yml to the format of this spec-tdd
+(spec format %d, config format %d). Files record their format in a top-level
+"version" key; files without one are at version 1.
+
+Migrations edit the YAML in place, so comments, key order and unknown keys are
+kept. Running the command again changes nothing. All files are written as one
+unit: if one cannot be written, none are.
+
+With --dry-run, the changes are shown as a diff and nothing is written.`, spec.FormatVersion, config.ConfigVersion),
+	Args: cobra.NoArgs,
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("migrate")
+
+		cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		type target struct {
+			path    string
+			steps   []migrate.Step
+			current int
+		}
+		var targets []target
+		if _, err := os.Stat(config.DefaultSpecConfigPath); err == nil {
+			targets = append(targets, target{config.DefaultSpecConfigPath, config.Migrations, config.ConfigVersion})
+		}
+		files, err := spec.ListFiles(cfg.SpecDir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		for _, f := range files {
+			targets = append(targets, target{f, spec.Migrations, spec.FormatVersion})
+		}
+
+		out := cmd.OutOrStdout()
+		var changes []rename.FileChange
+		for _, t := range targets {
+			data, err := os.ReadFile(t.path)
+			if err != nil {
+				return apperrors.Wrap("migrate", err)
+			}
+			res, err := migrate.Run(data, t.steps, t.current)
+			if err != nil {
+				return apperrors.Wrapf("migrate", err, "%s", t.path)
+			}
+			if !res.Changed() {
+				continue
+			}
+			fmt.Fprintf(out, "%s: %d -> %d%s\n", t.path, res.From, res.To, describeSteps(res.Applied))
+			changes = append(changes, rename.FileChange{Path: t.path, NewPath: t.path, Before: res.Before, After: res.After})
+		}
+
+		if len(changes) == 0 {
+			fmt.Fprintln(out, "all files are up to date")
+			return nil
+		}
+		if dryRun {
+			for _, c := range changes {
+				fmt.Fprintf(out, "\n%s", rename.Diff(c))
+			}
+			return nil
+		}
+		if err := rename.Apply(changes); err != nil {
+			log.Error("Failed to migrate files", "error", err)
+			return err
+		}
+		fmt.Fprintf(out, "migrated %d file(s)\n", len(changes))
+		return nil
+	}),
+}
+
+// describeSteps lists what the applied steps did, e.g.
+// " (structured questions ...)".
+func describeSteps(steps []migrate.Step) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	descs := make([]string, 0, len(steps))
+	for _, s := range steps {
+		descs = append(descs, s.Description)
+	}
+	return " (" + strings.Join(descs, "; ") + ")"
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().Bool("dry-run", false, "Show the changes as a diff without writing them")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, migrateCmd)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	old := "id: REQ-001\ntitle: Login\nquestions:\n    - Which provider?\n"
+	current := "version: 2\nid: REQ-002\ntitle: Logout\n"
+	for name, data := range map[string]string{"REQ-001.yml": old, "REQ-002.yml": current} {
+		if err := os.WriteFile(filepath.Join(specDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldPath := filepath.Join(".tdd", "specs", "REQ-001.yml")
+
+	var buf bytes.Buffer
+	migrateCmd.SetOut(&buf)
+	migrateCmd.SetErr(&bytes.Buffer{})
+	_ = migrateCmd.Flags().Set("dry-run", "true")
+	if err := migrateCmd.RunE(migrateCmd, nil); err != nil {
+		t.Fatalf("migrate dry-run error: %v", err)
+	}
+	for _, want := range []string{
+		oldPath + ": 1 -> 2 (",
+		"+version: 2\n",
+		"-    - Which provider?\n+    - id: Q1\n+      text: Which provider?\n+      status: open\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in dry-run output, got:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "REQ-002") {
+		t.Errorf("up-to-date spec should not be listed:\n%s", buf.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join(specDir, "REQ-001.yml")); string(data) != old {
+		t.Errorf("dry run wrote the spec:\n%s", data)
+	}
+
+	buf.Reset()
+	_ = migrateCmd.Flags().Set("dry-run", "false")
+	if err := migrateCmd.RunE(migrateCmd, nil); err != nil {
+		t.Fatalf("migrate error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "migrated 1 file(s)") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	data, _ := os.ReadFile(filepath.Join(specDir, "REQ-001.yml"))
+	if want := "version: 2\nid: REQ-001\ntitle: Login\nquestions:\n    - id: Q1\n      text: Which provider?\n      status: open\n"; string(data) != want {
+		t.Errorf("migrated spec = %q, want %q", data, want)
+	}
+
+	buf.Reset()
+	if err := migrateCmd.RunE(migrateCmd, nil); err != nil {
+		t.Fatalf("second migrate error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "all files are up to date") {
+		t.Errorf("second run should change nothing:\n%s", buf.String())
+	}
+}
+
+func TestMigrateRejectsNewerFiles(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, migrateCmd)
+	path := filepath.Join(tmpDir, ".tdd", "specs", "REQ-001.yml")
+	if err := os.WriteFile(path, []byte("version: 99\ntitle: Login\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	migrateCmd.SetOut(&bytes.Buffer{})
+	migrateCmd.SetErr(&bytes.Buffer{})
+	err := migrateCmd.RunE(migrateCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "upgrade spec-tdd") {
+		t.Fatalf("expected newer version error, got %v", err)
+	}
+}
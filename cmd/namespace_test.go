@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestReqAddDirAndNamespaceFilter(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqListCmd)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	t.Cleanup(func() { reqAddTitle, reqAddID, reqAddDir, namespaceFilter = "", "", "", "" })
+
+	reqAddCmd.SetOut(&bytes.Buffer{})
+	for _, r := range []struct{ title, dir string }{
+		{"Login", "auth"},
+		{"Invoice", "billing/invoices"},
+		{"Home", ""},
+		{"Logout", "./auth/"},
+	} {
+		reqAddTitle, reqAddID, reqAddDir = r.title, "", r.dir
+		if err := reqAddCmd.RunE(reqAddCmd, nil); err != nil {
+			t.Fatalf("req add %s error: %v", r.title, err)
+		}
+	}
+	for _, want := range []string{"auth/REQ-001.yml", "billing/invoices/REQ-002.yml", "REQ-003.yml", "auth/REQ-004.yml"} {
+		if _, err := os.Stat(filepath.Join(specDir, filepath.FromSlash(want))); err != nil {
+			t.Errorf("expected %s: %v", want, err)
+		}
+	}
+
+	// IDs stay unique across namespaces
+	reqAddTitle, reqAddID, reqAddDir = "Dup", "REQ-001", "billing"
+	if err := reqAddCmd.RunE(reqAddCmd, nil); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected duplicate ID error, got %v", err)
+	}
+	reqAddID, reqAddDir = "", "../outside"
+	if err := reqAddCmd.RunE(reqAddCmd, nil); err == nil {
+		t.Error("expected error for a namespace outside the spec directory")
+	}
+
+	// Commands find specs by ID in any namespace
+	exampleReqID, exampleGiven, exampleWhen, exampleThen = "REQ-002", "a", "b", "c"
+	t.Cleanup(func() { exampleReqID, exampleGiven, exampleWhen, exampleThen = "", "", "", "" })
+	exampleAddCmd.SetOut(&bytes.Buffer{})
+	if err := exampleAddCmd.RunE(exampleAddCmd, nil); err != nil {
+		t.Fatalf("example add error: %v", err)
+	}
+	s, err := spec.Load(filepath.Join(specDir, "billing", "invoices", "REQ-002.yml"))
+	if err != nil || len(s.Examples) != 1 {
+		t.Fatalf("example not added to the namespaced spec: %v", err)
+	}
+
+	list := func(ns string) []reqListItem {
+		t.Helper()
+		namespaceFilter = ns
+		var buf bytes.Buffer
+		reqListCmd.SetOut(&buf)
+		reqListCmd.SetErr(&bytes.Buffer{})
+		_ = reqListCmd.Flags().Set("format", "json")
+		if err := reqListCmd.RunE(reqListCmd, nil); err != nil {
+			t.Fatalf("req list --namespace %q error: %v", ns, err)
+		}
+		var items []reqListItem
+		if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return items
+	}
+	tests := []struct {
+		ns   string
+		want []string
+	}{
+		{"", []string{"REQ-001", "REQ-002", "REQ-003", "REQ-004"}},
+		{"auth", []string{"REQ-001", "REQ-004"}},
+		{"billing", []string{"REQ-002"}},
+		{"billing/invoices/", []string{"REQ-002"}},
+	}
+	for _, tt := range tests {
+		t.Run("namespace "+tt.ns, func(t *testing.T) {
+			var got []string
+			for _, it := range list(tt.ns) {
+				got = append(got, it.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if items := list("auth"); items[0].Namespace != "auth" {
+		t.Errorf("namespace = %q, want auth", items[0].Namespace)
+	}
+
+	namespaceFilter = "payments"
+	if err := reqListCmd.RunE(reqListCmd, nil); err == nil || !strings.Contains(err.Error(), "unknown namespace") {
+		t.Errorf("expected unknown namespace error, got %v", err)
+	}
+}
+
+func TestLoadAllRejectsDuplicateIDsAcrossNamespaces(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir, &spec.Spec{ID: "REQ-001", Title: "Login"})
+	saveTestSpecs(t, filepath.Join(specDir, "auth"), &spec.Spec{ID: "REQ-001", Title: "Login again"})
+	resetCmdFlags(t, reqListCmd)
+
+	reqListCmd.SetOut(&bytes.Buffer{})
+	reqListCmd.SetErr(&bytes.Buffer{})
+	if err := reqListCmd.RunE(reqListCmd, nil); err == nil || !strings.Contains(err.Error(), "duplicate ID REQ-001") {
+		t.Errorf("expected duplicate ID error, got %v", err)
+	}
+}
+
+func TestImportNamespace(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, filepath.Join(specDir, "billing"), &spec.Spec{ID: "REQ-001", Title: "Invoice"})
+	resetCmdFlags(t, importCSVCmd)
+	resetCmdFlags(t, exportReqIFCmd)
+	t.Cleanup(func() { namespaceFilter = "" })
+
+	exists := func(rel string) bool {
+		_, err := os.Stat(filepath.Join(specDir, filepath.FromSlash(rel)))
+		return err == nil
+	}
+
+	t.Run("csv creates new specs in the namespace", func(t *testing.T) {
+		namespaceFilter = "./auth/"
+		table := "id,title,given,when,then\nREQ-001,Invoice,g,w,t\n,Login,g,w,t\n"
+		if err := os.WriteFile("reqs.csv", []byte(table), 0644); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		importCSVCmd.SetOut(&buf)
+		if err := importCSVCmd.RunE(importCSVCmd, []string{"reqs.csv"}); err != nil {
+			t.Fatalf("import csv error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "1 created, 1 skipped") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+		if !exists("auth/REQ-002.yml") || exists("REQ-002.yml") {
+			t.Error("expected REQ-002 in the auth namespace")
+		}
+		if exists("auth/REQ-001.yml") {
+			t.Error("existing REQ-001 must not be duplicated into the auth namespace")
+		}
+	})
+
+	t.Run("reqif updates specs in place and creates new ones in the namespace", func(t *testing.T) {
+		namespaceFilter = ""
+		exportReqIFCmd.SetErr(&bytes.Buffer{})
+		if err := exportReqIFCmd.Flags().Set("output", "out.reqif"); err != nil {
+			t.Fatal(err)
+		}
+		if err := exportReqIFCmd.RunE(exportReqIFCmd, nil); err != nil {
+			t.Fatalf("export error: %v", err)
+		}
+		data, err := os.ReadFile("out.reqif")
+		if err != nil {
+			t.Fatal(err)
+		}
+		edited := strings.Replace(string(data), `THE-VALUE="Invoice"`, `THE-VALUE="Invoices"`, 1)
+		if err := os.WriteFile("out.reqif", []byte(edited), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(filepath.Join(specDir, "auth", "REQ-002.yml")); err != nil {
+			t.Fatal(err)
+		}
+
+		namespaceFilter = "payments"
+		var buf bytes.Buffer
+		importReqIFCmd.SetOut(&buf)
+		if err := importReqIFCmd.RunE(importReqIFCmd, []string{"out.reqif"}); err != nil {
+			t.Fatalf("import reqif error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "1 created, 1 updated") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+		if s, err := spec.Load(filepath.Join(specDir, "billing", "REQ-001.yml")); err != nil || s.Title != "Invoices" {
+			t.Errorf("REQ-001 should be updated in billing: %+v, %v", s, err)
+		}
+		if !exists("payments/REQ-002.yml") || exists("payments/REQ-001.yml") {
+			t.Error("expected only the new REQ-002 in the payments namespace")
+		}
+	})
+
+	t.Run("invalid namespace", func(t *testing.T) {
+		namespaceFilter = "../outside"
+		importCSVCmd.SetOut(&bytes.Buffer{})
+		if err := importCSVCmd.RunE(importCSVCmd, []string{"reqs.csv"}); err == nil || !strings.Contains(err.Error(), "invalid namespace") {
+			t.Errorf("expected invalid namespace error, got %v", err)
+		}
+	})
+}
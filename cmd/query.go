@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/query"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query <expression>",
+	Short: "Select specs with a query",
+	Long: fmt.Sprintf(`Print the IDs of the specs matching a query, one per line, or a list like
+"req list --format json" with --format json. The same queries select specs
+for scaffold, trace, map, guide, deps, ready and req list with --select.
+
+A query combines field:value terms with AND, OR, NOT and parentheses; NOT
+binds tightest, then AND, then OR. Adjacent terms are ANDed, "-term" is
+short for "NOT term" and "-(...)" for "NOT (...)". Keywords and field names
+are case-insensitive.
+
+Fields:
+  id:REQ-00*          ID ("*" and "?" are wildcards, also for tag, parent
+                      and depends-on)
+  title:login         title contains the text (case-insensitive); quote
+                      text with spaces: title:"reset password"
+  status:ready        lifecycle status (specs without one have the initial
+                      status)
+  tag:auth            has the tag
+  namespace:billing   in the namespace or below it
+  parent:REQ-001      direct child of the requirement
+  depends-on:REQ-003  depends directly on the requirement
+  has:examples        has: %s
+
+Unlike the other commands, query does not leave out excluded statuses
+(e.g. deprecated); add "-status:deprecated" to do so.`, strings.Join(query.HasValues, ", ")),
+	Example: `  spec-tdd query 'tag:auth AND status:ready AND NOT has:examples'
+  spec-tdd query depends-on:REQ-003
+  spec-tdd query 'has:open-questions OR has:stale-approvals' --format json
+  spec-tdd scaffold --select 'tag:auth -status:draft'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q (allowed: text, json)", format)
+		}
+
+		cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		lc := cfg.EffectiveLifecycle()
+		// Unquoted queries arrive as several arguments
+		q, err := parseQuery(strings.Join(args, " "), lc)
+		if err != nil {
+			return err
+		}
+
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+		if err != nil {
+			return err
+		}
+		specs = matchQuery(q, specs, lc)
+
+		w := cmd.OutOrStdout()
+		if format == "json" {
+			items := make([]reqListItem, 0, len(specs))
+			for _, s := range specs {
+				item := newReqListItem(s)
+				item.Status = lc.Effective(s.Status)
+				items = append(items, item)
+			}
+			data, err := json.MarshalIndent(items, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, string(data))
+			return nil
+		}
+		for _, s := range specs {
+			fmt.Fprintln(w, s.ID)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().String("format", "text", "Output format: text (IDs) or json")
+}
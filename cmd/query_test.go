@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/deps"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func setupQueryTestDir(t *testing.T) string {
+	t.Helper()
+	tmpDir := setupWorkspace(t)
+	ex := spec.Example{ID: "E1", Given: "a", When: "b", Then: "c"}
+	saveTestSpecs(t, filepath.Join(tmpDir, ".tdd", "specs"),
+		&spec.Spec{ID: "REQ-001", Title: "Login", Status: "ready", Tags: []string{"auth"}, Examples: []spec.Example{ex}},
+		&spec.Spec{ID: "REQ-002", Title: "Reset password", Tags: []string{"auth"}, Depends: []string{"REQ-001"}},
+		&spec.Spec{ID: "REQ-003", Title: "Invoices", Depends: []string{"REQ-001"}, Examples: []spec.Example{ex}},
+		&spec.Spec{ID: "REQ-004", Title: "Legacy SSO", Status: "deprecated", Tags: []string{"auth"}},
+	)
+	return tmpDir
+}
+
+func TestQueryCommand(t *testing.T) {
+	setupQueryTestDir(t)
+	resetCmdFlags(t, queryCmd)
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		var buf bytes.Buffer
+		queryCmd.SetOut(&buf)
+		queryCmd.SetErr(&bytes.Buffer{})
+		err := queryCmd.RunE(queryCmd, args)
+		return buf.String(), err
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"tag", []string{"tag:auth"}, "REQ-001\nREQ-002\nREQ-004\n"},
+		{"unquoted arguments", []string{"tag:auth", "AND", "NOT", "has:examples", "-status:deprecated"}, "REQ-002\n"},
+		{"initial status", []string{"status:draft"}, "REQ-002\nREQ-003\n"},
+		{"depends-on", []string{"depends-on:REQ-001 AND has:examples"}, "REQ-003\n"},
+		{"no match", []string{"tag:billing"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := run(t, tt.args...)
+			if err != nil {
+				t.Fatalf("query error: %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		if err := queryCmd.Flags().Set("format", "json"); err != nil {
+			t.Fatal(err)
+		}
+		defer queryCmd.Flags().Set("format", "text")
+		out, err := run(t, "id:REQ-002")
+		if err != nil {
+			t.Fatalf("query error: %v", err)
+		}
+		var items []reqListItem
+		if err := json.Unmarshal([]byte(out), &items); err != nil {
+			t.Fatalf("invalid JSON %q: %v", out, err)
+		}
+		if len(items) != 1 || items[0].ID != "REQ-002" || items[0].Status != "draft" || items[0].Depends[0] != "REQ-001" {
+			t.Errorf("unexpected items: %+v", items)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for args, want := range map[string]string{
+			"status:redy":  `unknown status "redy" in query`,
+			"tag:auth AND": "expected a term at end of query",
+		} {
+			if _, err := run(t, args); err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("query %q error = %v, want %q", args, err, want)
+			}
+		}
+	})
+}
+
+func TestSelectFlag(t *testing.T) {
+	tmpDir := setupQueryTestDir(t)
+
+	t.Run("req list", func(t *testing.T) {
+		resetCmdFlags(t, reqListCmd)
+		if err := reqListCmd.Flags().Set("select", "tag:auth"); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		reqListCmd.SetOut(&buf)
+		reqListCmd.SetErr(&bytes.Buffer{})
+		if err := reqListCmd.RunE(reqListCmd, nil); err != nil {
+			t.Fatalf("req list error: %v", err)
+		}
+		// Excluded statuses are left out before --select applies
+		want := "ID       STATUS  EXAMPLES  TITLE\nREQ-001  ready   1         Login\nREQ-002  draft   0         Reset password\n"
+		if buf.String() != want {
+			t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		resetCmdFlags(t, readyCmd)
+		if err := readyCmd.Flags().Set("select", "label:auth"); err != nil {
+			t.Fatal(err)
+		}
+		readyCmd.SetOut(&bytes.Buffer{})
+		err := readyCmd.RunE(readyCmd, nil)
+		if err == nil || !strings.Contains(err.Error(), `--select: query.Parse: unknown field "label"`) {
+			t.Errorf("error = %v", err)
+		}
+	})
+
+	t.Run("deps only updates selected specs", func(t *testing.T) {
+		resetCmdFlags(t, depsCmd)
+		oldDetector := testDepsDetector
+		t.Cleanup(func() { testDepsDetector = oldDetector })
+		testDepsDetector = &deps.MockDetector{Results: []deps.DepsResult{
+			{ID: "REQ-002", Depends: []string{"REQ-003"}},
+			{ID: "REQ-003", Depends: []string{"REQ-002"}},
+		}}
+		// Other deps tests run through rootCmd and may leave --dry-run set
+		if err := depsCmd.Flags().Set("dry-run", "false"); err != nil {
+			t.Fatal(err)
+		}
+		if err := depsCmd.Flags().Set("select", "title:invoice"); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		depsCmd.SetOut(&buf)
+		if err := depsCmd.RunE(depsCmd, nil); err != nil {
+			t.Fatalf("deps error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "1 specs updated") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+		for id, want := range map[string]string{"REQ-002": "REQ-001", "REQ-003": "REQ-002"} {
+			s, err := spec.Load(filepath.Join(specDir, id+".yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(s.Depends, ",") != want {
+				t.Errorf("%s depends = %v, want %s", id, s.Depends, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var (
+	questionReqID  string
+	questionText   string
+	questionOwner  string
+	questionID     string
+	questionAnswer string
+	questionAll    bool
+)
+
+// questionNow is replaced in tests to get a fixed resolved_at.
+var questionNow = time.Now
+
+var questionCmd = &cobra.Command{
+	Use:   "question",
+	Short: "Manage open questions on requirements",
+}
+
+var questionAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a question to a requirement",
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("question.add")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		path, s, err := loadSpecByID(cfg.SpecDir, questionReqID)
+		if err != nil {
+			return err
+		}
+
+		q := spec.Question{
+			ID:     spec.NextQuestionID(s),
+			Text:   strings.TrimSpace(questionText),
+			Owner:  strings.TrimSpace(questionOwner),
+			Status: spec.QuestionOpen,
+		}
+		s.Questions = append(s.Questions, q)
+
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "added %s to %s\n", q.ID, path)
+		return nil
+	}),
+}
+
+var questionAnswerCmd = &cobra.Command{
+	Use:   "answer",
+	Short: "Record the answer to a question and resolve it",
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("question.answer")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		path, s, err := loadSpecByID(cfg.SpecDir, questionReqID)
+		if err != nil {
+			return err
+		}
+
+		// Plain-string questions from older files have no ID yet
+		s.Normalize()
+		q := s.FindQuestion(strings.TrimSpace(questionID))
+		if q == nil {
+			return fmt.Errorf("question %s not found in %s", questionID, s.ID)
+		}
+
+		q.Answer = strings.TrimSpace(questionAnswer)
+		if q.Answer == "" {
+			return fmt.Errorf("--answer must not be empty")
+		}
+		q.Status = spec.QuestionResolved
+		q.ResolvedAt = questionNow().UTC().Truncate(time.Second)
+
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "resolved %s in %s\n", q.ID, path)
+		return nil
+	}),
+}
+
+var questionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List open questions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+		if err != nil {
+			return err
+		}
+
+		reqID := strings.TrimSpace(questionReqID)
+		count := 0
+		for _, s := range specs {
+			if reqID != "" && s.ID != reqID {
+				continue
+			}
+			s.Normalize()
+			for _, q := range s.Questions {
+				if !questionAll && !q.IsOpen() {
+					continue
+				}
+				line := fmt.Sprintf("%s %s [%s] %s", s.ID, q.ID, q.Status, q.Text)
+				if q.Owner != "" {
+					line += fmt.Sprintf(" (owner: %s)", q.Owner)
+				}
+				if q.Answer != "" {
+					line += " → " + q.Answer
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+				count++
+			}
+		}
+
+		if count == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no questions found")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(questionCmd)
+	questionCmd.AddCommand(questionAddCmd)
+	questionCmd.AddCommand(questionAnswerCmd)
+	questionCmd.AddCommand(questionListCmd)
+
+	questionAddCmd.Flags().StringVar(&questionReqID, "req", "", "Requirement ID (e.g., REQ-001)")
+	questionAddCmd.Flags().StringVar(&questionText, "text", "", "Question text")
+	questionAddCmd.Flags().StringVar(&questionOwner, "owner", "", "Who is expected to answer")
+	_ = questionAddCmd.MarkFlagRequired("req")
+	_ = questionAddCmd.MarkFlagRequired("text")
+
+	questionAnswerCmd.Flags().StringVar(&questionReqID, "req", "", "Requirement ID (e.g., REQ-001)")
+	questionAnswerCmd.Flags().StringVar(&questionID, "id", "", "Question ID (e.g., Q1)")
+	questionAnswerCmd.Flags().StringVar(&questionAnswer, "answer", "", "Answer text")
+	_ = questionAnswerCmd.MarkFlagRequired("req")
+	_ = questionAnswerCmd.MarkFlagRequired("id")
+	_ = questionAnswerCmd.MarkFlagRequired("answer")
+
+	questionListCmd.Flags().StringVar(&questionReqID, "req", "", "Only list questions of this requirement")
+	questionListCmd.Flags().BoolVar(&questionAll, "all", false, "Include resolved questions")
+}
+
+// loadSpecByID loads the spec file for reqID from specDir.
+func loadSpecByID(specDir, reqID string) (string, *spec.Spec, error) {
+	reqID = strings.TrimSpace(reqID)
+	if reqID == "" {
+		return "", nil, fmt.Errorf("--req is required")
+	}
+
+	path, err := spec.FindFile(specDir, reqID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return "", nil, fmt.Errorf("spec not found: %s", filepath.Join(specDir, reqID+".yml"))
+		}
+		return "", nil, err
+	}
+
+	s, err := spec.Load(path)
+	if err != nil {
+		return "", nil, err
+	}
+	s.Namespace = spec.NamespaceOf(specDir, path)
+	return path, s, nil
+}
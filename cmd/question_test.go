@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestQuestionCommands(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	path := filepath.Join(specDir, "REQ-001.yml")
+
+	// Legacy plain-string question
+	legacy := "id: REQ-001\ntitle: Login\nquestions:\n  - Remember me?\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	fixed := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	questionNow = func() time.Time { return fixed }
+	t.Cleanup(func() {
+		questionNow = time.Now
+		questionReqID, questionText, questionOwner = "", "", ""
+		questionID, questionAnswer, questionAll = "", "", false
+	})
+
+	var buf bytes.Buffer
+	questionAddCmd.SetOut(&buf)
+	questionReqID = "REQ-001"
+	questionText = "Lockout threshold?"
+	questionOwner = "security"
+	if err := questionAddCmd.RunE(questionAddCmd, nil); err != nil {
+		t.Fatalf("questionAddCmd error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "added Q1") {
+		t.Errorf("expected Q1, got: %s", buf.String())
+	}
+
+	questionAnswerCmd.SetOut(&buf)
+	questionID = "Q1"
+	questionAnswer = "5 attempts"
+	if err := questionAnswerCmd.RunE(questionAnswerCmd, nil); err != nil {
+		t.Fatalf("questionAnswerCmd error: %v", err)
+	}
+
+	s, err := spec.Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(s.Questions) != 2 {
+		t.Fatalf("expected 2 questions, got %+v", s.Questions)
+	}
+	// The legacy question got an ID when the file was normalized
+	if s.Questions[0].ID != "Q2" || !s.Questions[0].IsOpen() {
+		t.Errorf("legacy question = %+v", s.Questions[0])
+	}
+	answered := s.Questions[1]
+	if answered.Status != spec.QuestionResolved || answered.Answer != "5 attempts" || !answered.ResolvedAt.Equal(fixed) {
+		t.Errorf("answered question = %+v", answered)
+	}
+
+	t.Run("list shows open questions only by default", func(t *testing.T) {
+		var out bytes.Buffer
+		questionListCmd.SetOut(&out)
+		questionReqID = ""
+		if err := questionListCmd.RunE(questionListCmd, nil); err != nil {
+			t.Fatalf("questionListCmd error: %v", err)
+		}
+		if !strings.Contains(out.String(), "REQ-001 Q2 [open] Remember me?") || strings.Contains(out.String(), "Lockout") {
+			t.Errorf("unexpected list output: %s", out.String())
+		}
+
+		out.Reset()
+		questionAll = true
+		if err := questionListCmd.RunE(questionListCmd, nil); err != nil {
+			t.Fatalf("questionListCmd error: %v", err)
+		}
+		if !strings.Contains(out.String(), "[resolved] Lockout threshold? (owner: security) → 5 attempts") {
+			t.Errorf("expected resolved question with --all, got: %s", out.String())
+		}
+	})
+
+	t.Run("unknown question ID", func(t *testing.T) {
+		questionReqID = "REQ-001"
+		questionID = "Q9"
+		if err := questionAnswerCmd.RunE(questionAnswerCmd, nil); err == nil {
+			t.Fatal("expected error for unknown question")
+		}
+	})
+}
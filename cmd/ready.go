@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/ready"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var readyCmd = &cobra.Command{
+	Use:   "ready [REQ-ID...]",
+	Short: "Check specs against the Definition of Ready",
+	Long: `Check specs against the readiness rules in .tdd/config.yml and report
+each failing spec with its reasons. Exits with an error if any spec is not ready.`,
+	RunE: runReady,
+}
+
+func init() {
+	rootCmd.AddCommand(readyCmd)
+
+	readyCmd.Flags().StringSlice("status", nil, "Only check specs in these statuses (default: all but excluded, e.g. deprecated)")
+	addSelectFlag(readyCmd)
+}
+
+func runReady(cmd *cobra.Command, args []string) error {
+	cfg, err := loadSpecConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	all, err := spec.LoadAll(cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+
+	statuses, _ := cmd.Flags().GetStringSlice("status")
+	specs, err := filterSpecsByNamespace(cfg.SpecDir, all)
+	if err != nil {
+		return err
+	}
+	specs, _, err = filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+	if err != nil {
+		return err
+	}
+	specs, err = filterSpecsBySelect(cmd, specs, cfg.EffectiveLifecycle())
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		specs, err = selectSpecs(specs, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	results := ready.Check(specs, all, cfg.EffectiveReadiness())
+	notReady := 0
+	for _, r := range results {
+		if r.Ready() {
+			fmt.Fprintf(cmd.OutOrStdout(), "ok:        %s %s\n", r.ID, r.Title)
+			continue
+		}
+		notReady++
+		fmt.Fprintf(cmd.OutOrStdout(), "not ready: %s %s\n", r.ID, r.Title)
+		for _, f := range r.Failures {
+			fmt.Fprintf(cmd.OutOrStdout(), "  - [%s] %s\n", f.Rule, f.Reason)
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "\n%d ready, %d not ready\n", len(results)-notReady, notReady)
+	if notReady > 0 {
+		return fmt.Errorf("%d spec(s) not ready", notReady)
+	}
+	return nil
+}
+
+// selectSpecs returns the specs with the given IDs, in the order given.
+func selectSpecs(specs []*spec.Spec, ids []string) ([]*spec.Spec, error) {
+	byID := make(map[string]*spec.Spec, len(specs))
+	for _, s := range specs {
+		byID[s.ID] = s
+	}
+	out := make([]*spec.Spec, 0, len(ids))
+	for _, id := range ids {
+		s, ok := byID[strings.TrimSpace(id)]
+		if !ok {
+			return nil, fmt.Errorf("spec not found: %s", id)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// formatFailures joins failure reasons for one-line messages.
+func formatFailures(r ready.Result) string {
+	reasons := make([]string, 0, len(r.Failures))
+	for _, f := range r.Failures {
+		reasons = append(reasons, f.Reason)
+	}
+	return strings.Join(reasons, "; ")
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func setupReadyTestDir(t *testing.T) string {
+	t.Helper()
+	tmpDir := setupWorkspace(t)
+	saveTestSpecs(t, filepath.Join(tmpDir, ".tdd", "specs"),
+		&spec.Spec{ID: "REQ-001", Title: "Login", Examples: []spec.Example{{ID: "E1", Given: "a", When: "b", Then: "c"}}},
+		&spec.Spec{ID: "REQ-002", Title: "Logout", Questions: []spec.Question{{ID: "Q1", Text: "Where to redirect?"}}},
+	)
+	return tmpDir
+}
+
+func TestReadyCommand(t *testing.T) {
+	setupReadyTestDir(t)
+
+	var buf bytes.Buffer
+	readyCmd.SetOut(&buf)
+	err := readyCmd.RunE(readyCmd, nil)
+	if err == nil {
+		t.Fatal("expected error when a spec is not ready")
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"ok:        REQ-001 Login",
+		"not ready: REQ-002 Logout",
+		"  - [has-examples] no examples",
+		"  - [no-open-questions] open Q1: Where to redirect?",
+		"1 ready, 1 not ready",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := readyCmd.RunE(readyCmd, []string{"REQ-001"}); err != nil {
+		t.Errorf("expected REQ-001 alone to be ready, got %v", err)
+	}
+}
+
+func TestScaffoldReadyEnforcement(t *testing.T) {
+	tmpDir := setupReadyTestDir(t)
+	t.Cleanup(func() {
+		scaffoldRunner, scaffoldForce, scaffoldReady = "", false, ""
+	})
+	scaffoldRunner = "vitest"
+	scaffoldForce = true
+
+	var stderr bytes.Buffer
+	scaffoldCmd.SetOut(&bytes.Buffer{})
+	scaffoldCmd.SetErr(&stderr)
+
+	scaffoldReady = "error"
+	if err := scaffoldCmd.RunE(scaffoldCmd, nil); err == nil || !strings.Contains(err.Error(), "REQ-002") {
+		t.Fatalf("expected refusal naming REQ-002, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "tests")); err == nil {
+		entries, _ := os.ReadDir(filepath.Join(tmpDir, "tests"))
+		if len(entries) > 0 {
+			t.Errorf("expected no test files written in error mode, got %d", len(entries))
+		}
+	}
+
+	stderr.Reset()
+	scaffoldReady = "warn"
+	if err := scaffoldCmd.RunE(scaffoldCmd, nil); err != nil {
+		t.Fatalf("warn mode should not fail: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "warn: REQ-002 is not ready: no examples") {
+		t.Errorf("expected warning, got: %s", stderr.String())
+	}
+
+	// Readiness is enforced from config as well
+	cfg := "specDir: .tdd/specs\ntestDir: tests\nrunner: vitest\nfileNamePattern: \"req-{{id}}-{{slug}}.test.ts\"\nreadiness:\n  rules: [no-open-questions]\n  scaffold: error\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tdd", "config.yml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("write config error: %v", err)
+	}
+	scaffoldReady = ""
+	if err := scaffoldCmd.RunE(scaffoldCmd, nil); err == nil {
+		t.Fatal("expected config-driven refusal")
+	}
+}
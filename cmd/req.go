@@ -1,20 +1,53 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/rename"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
 var (
-	reqAddTitle string
-	reqAddID    string
+	reqAddTitle       string
+	reqAddID          string
+	reqAddParent      string
+	reqAddPrefix      string
+	reqAddProvisional bool
+	reqAddDir         string
+	reqStatusForce    bool
+	reqFinalizeDryRun bool
+	reqMvDryRun       bool
+	reqRenumberPrefix string
+	reqRenumberDryRun bool
 )
 
+// gitBranch returns the current git branch. Tests replace it.
+var gitBranch = currentGitBranch
+
+// currentGitBranch asks git for the branch HEAD points to. Unlike
+// rev-parse, symbolic-ref also works on a branch without commits.
+func currentGitBranch() (string, error) {
+	out, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", fmt.Errorf("branch-local IDs need a git branch, but HEAD is detached; check out a branch first")
+	}
+	if err != nil {
+		return "", fmt.Errorf("branch-local IDs need a git branch: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 var reqCmd = &cobra.Command{
 	Use:   "req",
 	Short: "Manage requirement specs",
@@ -23,7 +56,7 @@ var reqCmd = &cobra.Command{
 var reqAddCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a new requirement",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
 		log := GetLogger().WithComponent("req.add")
 
 		cfg, err := loadSpecConfig(cmd)
@@ -38,20 +71,44 @@ var reqAddCmd = &cobra.Command{
 
 		id := strings.TrimSpace(reqAddID)
 		if id == "" {
-			id, err = spec.NextReqID(cfg.SpecDir)
+			ids := spec.CurrentIDScheme()
+			prefix := strings.TrimSpace(reqAddPrefix)
+			if prefix == "" {
+				prefix = ids.DefaultPrefix()
+			}
+			if !ids.HasPrefix(prefix) {
+				return fmt.Errorf("unknown ID prefix %q (allowed: %s)", prefix, strings.Join(ids.Prefixes, ", "))
+			}
+			id, err = nextReqID(cfg.SpecDir, prefix, cfg.IDs.Provisional, reqAddProvisional)
 			if err != nil {
 				return err
 			}
 		}
 
-		filePath := filepath.Join(cfg.SpecDir, fmt.Sprintf("%s.yml", id))
-		if _, err := os.Stat(filePath); err == nil {
-			return fmt.Errorf("spec already exists: %s", filePath)
+		ns := spec.CleanNamespace(reqAddDir)
+		if ns != "" {
+			if err := spec.ValidateNamespace(ns); err != nil {
+				return err
+			}
+		}
+		// IDs are unique across all namespaces
+		if existing, err := spec.FindFile(cfg.SpecDir, id); err == nil {
+			return fmt.Errorf("spec already exists: %s", existing)
+		}
+		filePath := filepath.Join(cfg.SpecDir, filepath.FromSlash(ns), fmt.Sprintf("%s.yml", id))
+
+		parent := strings.TrimSpace(reqAddParent)
+		if parent != "" {
+			if _, _, err := loadSpecByID(cfg.SpecDir, parent); err != nil {
+				return err
+			}
 		}
 
 		newSpec := &spec.Spec{
-			ID:    id,
-			Title: reqAddTitle,
+			ID:     id,
+			Title:  reqAddTitle,
+			Status: cfg.EffectiveLifecycle().Initial,
+			Parent: parent,
 		}
 
 		if err := spec.Save(filePath, newSpec); err != nil {
@@ -61,14 +118,400 @@ var reqAddCmd = &cobra.Command{
 
 		fmt.Fprintf(cmd.OutOrStdout(), "created %s\n", filePath)
 		return nil
+	}),
+}
+
+var reqStatusCmd = &cobra.Command{
+	Use:   "status <REQ-ID> [status]",
+	Short: "Show or change the lifecycle status of a requirement",
+	Long: `Show the status of a requirement, or move it to a new status.
+Only transitions allowed by the lifecycle in .tdd/config.yml are accepted
+unless --force is given.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("req.status")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		lc := cfg.EffectiveLifecycle()
+
+		path, s, err := loadSpecByID(cfg.SpecDir, args[0])
+		if err != nil {
+			return err
+		}
+		current := lc.Effective(s.Status)
+
+		if len(args) == 1 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", s.ID, current)
+			if next := lc.Transitions[current]; len(next) > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "allowed: %s\n", strings.Join(next, ", "))
+			}
+			return nil
+		}
+
+		target := strings.TrimSpace(args[1])
+		if !lc.IsState(target) {
+			return fmt.Errorf("unknown status %q (allowed: %s)", target, strings.Join(lc.States(), ", "))
+		}
+		if target == current {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is already %s\n", s.ID, current)
+			return nil
+		}
+		if !reqStatusForce && !lc.CanTransition(current, target) {
+			allowed := strings.Join(lc.Transitions[current], ", ")
+			if allowed == "" {
+				allowed = "none"
+			}
+			return fmt.Errorf("cannot move %s from %s to %s (allowed: %s)", s.ID, current, target, allowed)
+		}
+
+		s.Status = target
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s -> %s\n", s.ID, current, target)
+		return nil
+	}),
+}
+
+// nextReqID returns a permanent sequential ID, or a provisional one when the
+// configured mode (or --provisional) asks for it. --provisional without a
+// configured mode uses ULIDs, which need no git branch.
+func nextReqID(specDir, prefix, mode string, force bool) (string, error) {
+	if force && (mode == "" || mode == spec.ProvisionalOff) {
+		mode = spec.ProvisionalULID
+	}
+	ids := spec.CurrentIDScheme()
+	switch mode {
+	case spec.ProvisionalULID:
+		return ids.NewULIDID(prefix, time.Now())
+	case spec.ProvisionalBranch:
+		branch, err := gitBranch()
+		if err != nil {
+			return "", err
+		}
+		existing, err := spec.ListIDs(specDir)
+		if err != nil {
+			return "", err
+		}
+		return ids.NextBranchID(prefix, branch, existing), nil
+	default:
+		return spec.NextReqIDFor(specDir, prefix)
+	}
+}
+
+var reqFinalizeCmd = &cobra.Command{
+	Use:   "finalize [REQ-ID...]",
+	Short: "Replace provisional IDs with permanent sequential IDs",
+	Long: `Assign the next free permanent ID to each provisional requirement
+(all of them, or the given ones) and rewrite every reference: spec file names,
+parent and depends entries, IDs mentioned in spec text, and test names and
+test file names. Run it after merging, on the main branch.`,
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("req.finalize")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		ids := spec.CurrentIDScheme()
+
+		known, err := spec.ListIDs(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		targets := args
+		if len(targets) == 0 {
+			for _, id := range known {
+				if ids.IsProvisional(id) {
+					targets = append(targets, id)
+				}
+			}
+		}
+		for _, id := range targets {
+			if !ids.IsProvisional(id) {
+				return fmt.Errorf("%s is not a provisional ID", id)
+			}
+			if !slices.Contains(known, id) {
+				return fmt.Errorf("spec not found: %s", id)
+			}
+		}
+		if len(targets) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no provisional IDs")
+			return nil
+		}
+		ids.Sort(targets)
+
+		mapping := rename.Mapping{}
+		for _, id := range targets {
+			prefix, _ := ids.ProvisionalPrefix(id)
+			next := ids.Next(prefix, known)
+			mapping[id] = next
+			known = append(known, next)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s -> %s\n", id, next)
+		}
+
+		n, err := applyRenames(cmd.OutOrStdout(), cfg.SpecDir, cfg.TestDir, mapping, reqFinalizeDryRun)
+		if err != nil {
+			log.Error("Failed to apply renames", "error", err)
+			return err
+		}
+		if !reqFinalizeDryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "\nfinalized %d requirement(s), %d file(s) changed\n", len(mapping), n)
+		}
+		return nil
+	}),
+}
+
+var reqMvCmd = &cobra.Command{
+	Use:   "mv <OLD-ID> <NEW-ID>",
+	Short: "Rename a requirement and rewrite every reference to it",
+	Long: `Rename a requirement and rewrite every reference: the spec file name and id,
+parent and depends entries of other specs, IDs mentioned in spec text, and
+test names and test file names. The change is refused if NEW-ID already exists
+or is already mentioned anywhere. Use --dry-run to see the diff first.`,
+	Args: cobra.ExactArgs(2),
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("req.mv")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		mapping := rename.Mapping{strings.TrimSpace(args[0]): strings.TrimSpace(args[1])}
+		n, err := applyRenames(cmd.OutOrStdout(), cfg.SpecDir, cfg.TestDir, mapping, reqMvDryRun)
+		if err != nil {
+			log.Error("Failed to rename requirement", "error", err)
+			return err
+		}
+		if !reqMvDryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "\nrenamed %s -> %s, %d file(s) changed\n", args[0], args[1], n)
+		}
+		return nil
+	}),
+}
+
+var reqRenumberCmd = &cobra.Command{
+	Use:   "renumber",
+	Short: "Renumber requirements sequentially, closing gaps",
+	Long: `Renumber permanent requirement IDs from 1 in their current order, closing the
+gaps left by removed requirements and applying the configured padding. Every
+reference is rewritten as with "req mv". Provisional IDs are left alone
+(see "req finalize"). Use --dry-run to see the diff first.`,
+	Args: cobra.NoArgs,
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("req.renumber")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		ids := spec.CurrentIDScheme()
+		prefix := strings.TrimSpace(reqRenumberPrefix)
+		if prefix != "" {
+			if !ids.HasPrefix(prefix) {
+				return fmt.Errorf("unknown ID prefix %q (allowed: %s)", prefix, strings.Join(ids.Prefixes, ", "))
+			}
+			if ids.Numbering == spec.NumberingGlobal {
+				return fmt.Errorf("--prefix cannot be used with global numbering")
+			}
+		}
+
+		known, err := spec.ListIDs(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		mapping := renumberMapping(ids, known, prefix)
+		if len(mapping) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "already numbered sequentially")
+			return nil
+		}
+		olds := make([]string, 0, len(mapping))
+		for old := range mapping {
+			olds = append(olds, old)
+		}
+		ids.Sort(olds)
+		for _, old := range olds {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s -> %s\n", old, mapping[old])
+		}
+
+		n, err := applyRenames(cmd.OutOrStdout(), cfg.SpecDir, cfg.TestDir, mapping, reqRenumberDryRun)
+		if err != nil {
+			log.Error("Failed to renumber requirements", "error", err)
+			return err
+		}
+		if !reqRenumberDryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "\nrenumbered %d requirement(s), %d file(s) changed\n", len(mapping), n)
+		}
+		return nil
+	}),
+}
+
+// renumberMapping maps permanent IDs to sequential numbers in scheme order:
+// per prefix, or across all prefixes with global numbering. A non-empty
+// prefix limits the mapping to that prefix. Unchanged IDs are omitted.
+func renumberMapping(ids spec.IDScheme, known []string, prefix string) rename.Mapping {
+	type entry struct {
+		id, prefix string
+		n          int
+	}
+	var entries []entry
+	for _, id := range known {
+		p, n, ok := ids.Parse(id)
+		if ok && (prefix == "" || p == prefix) {
+			entries = append(entries, entry{id, p, n})
+		}
+	}
+	if ids.Numbering == spec.NumberingGlobal {
+		// One counter: keep the numeric order regardless of prefix
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].n != entries[j].n {
+				return entries[i].n < entries[j].n
+			}
+			return ids.Less(entries[i].id, entries[j].id)
+		})
+	} else {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return ids.Less(entries[i].id, entries[j].id)
+		})
+	}
+
+	mapping := rename.Mapping{}
+	counters := map[string]int{}
+	for _, e := range entries {
+		key := e.prefix
+		if ids.Numbering == spec.NumberingGlobal {
+			key = ""
+		}
+		counters[key]++
+		if next := ids.Format(e.prefix, counters[key]); next != e.id {
+			mapping[e.id] = next
+		}
+	}
+	return mapping
+}
+
+// applyRenames plans the ID mapping across specs and tests. On a dry run it
+// prints the diff of every affected file; otherwise it applies the changes
+// and lists them. It returns the number of files changed.
+func applyRenames(w io.Writer, specDir, testDir string, mapping rename.Mapping, dryRun bool) (int, error) {
+	changes, err := rename.Plan(specDir, testDir, mapping)
+	if err != nil {
+		return 0, err
+	}
+	if dryRun {
+		for _, c := range changes {
+			fmt.Fprintf(w, "\n%s", rename.Diff(c))
+		}
+		return len(changes), nil
+	}
+
+	if err := rename.Apply(changes); err != nil {
+		return 0, err
+	}
+	for _, c := range changes {
+		if c.Renamed() {
+			fmt.Fprintf(w, "rename %s -> %s\n", c.Path, c.NewPath)
+		} else {
+			fmt.Fprintf(w, "update %s\n", c.Path)
+		}
+	}
+	return len(changes), nil
+}
+
+var reqTreeCmd = &cobra.Command{
+	Use:   "tree [REQ-ID]",
+	Short: "Show the requirement hierarchy",
+	Long: `Show requirements as a tree of parent → children (e.g. epic → feature →
+requirement). With a REQ-ID, only that requirement and its descendants are shown.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		if err := spec.ValidateHierarchy(specs); err != nil {
+			return err
+		}
+		specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+		if err != nil {
+			return err
+		}
+		statuses, _ := cmd.Flags().GetStringSlice("status")
+		specs, _, err = filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+		if err != nil {
+			return err
+		}
+
+		h := spec.NewHierarchy(specs)
+		roots := h.Roots()
+		if len(args) == 1 {
+			root, err := selectSpecs(specs, args)
+			if err != nil {
+				return err
+			}
+			roots = root
+		}
+
+		w := cmd.OutOrStdout()
+		var printNode func(s *spec.Spec, prefix, branch, next string)
+		printNode = func(s *spec.Spec, prefix, branch, next string) {
+			line := fmt.Sprintf("%s%s%s %s", prefix, branch, s.ID, s.Title)
+			if s.Status != "" {
+				line += fmt.Sprintf(" [%s]", s.Status)
+			}
+			fmt.Fprintln(w, line)
+			children := h.Children(s.ID)
+			for i, c := range children {
+				if i == len(children)-1 {
+					printNode(c, prefix+next, "└── ", "    ")
+				} else {
+					printNode(c, prefix+next, "├── ", "│   ")
+				}
+			}
+		}
+		for _, r := range roots {
+			printNode(r, "", "", "")
+		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(reqCmd)
 	reqCmd.AddCommand(reqAddCmd)
+	reqCmd.AddCommand(reqStatusCmd)
+	reqCmd.AddCommand(reqTreeCmd)
+	reqCmd.AddCommand(reqFinalizeCmd)
+	reqCmd.AddCommand(reqMvCmd)
+	reqCmd.AddCommand(reqRenumberCmd)
 
 	reqAddCmd.Flags().StringVar(&reqAddTitle, "title", "", "Requirement title")
 	reqAddCmd.Flags().StringVar(&reqAddID, "id", "", "Requirement ID (e.g., REQ-001)")
+	reqAddCmd.Flags().StringVar(&reqAddPrefix, "prefix", "", "ID prefix for the generated ID (default: first of ids.prefixes)")
+	reqAddCmd.Flags().BoolVar(&reqAddProvisional, "provisional", false, "Create a provisional ID (ids.provisional mode, or a ULID) to be finalized later")
+	reqAddCmd.Flags().StringVar(&reqAddParent, "parent", "", "Parent requirement ID (e.g., an epic or feature)")
+	reqAddCmd.Flags().StringVar(&reqAddDir, "dir", "", "Namespace (subdirectory of specDir) to create the spec in, e.g. auth")
 	_ = reqAddCmd.MarkFlagRequired("title")
+
+	reqStatusCmd.Flags().BoolVar(&reqStatusForce, "force", false, "Allow transitions not defined in the lifecycle")
+
+	reqFinalizeCmd.Flags().BoolVar(&reqFinalizeDryRun, "dry-run", false, "Show the new IDs and a diff of affected files without writing")
+
+	reqMvCmd.Flags().BoolVar(&reqMvDryRun, "dry-run", false, "Show a diff of affected files without writing")
+
+	reqRenumberCmd.Flags().StringVar(&reqRenumberPrefix, "prefix", "", "Only renumber IDs with this prefix")
+	reqRenumberCmd.Flags().BoolVar(&reqRenumberDryRun, "dry-run", false, "Show the new IDs and a diff of affected files without writing")
+
+	reqTreeCmd.Flags().StringSlice("status", nil, "Only include specs in these statuses (default: all but excluded, e.g. deprecated)")
 }
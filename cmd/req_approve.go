@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// gitUserName returns the git user name, the default reviewer of
+// `req approve`. Tests replace it.
+var gitUserName = func() string {
+	out, err := exec.Command("git", "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+var reqApproveCmd = &cobra.Command{
+	Use:   "approve <REQ-ID>",
+	Short: "Record a review approval of a requirement",
+	Long: `Record that a reviewer approved a requirement in its current form. The
+approval stores the reviewer, the time and a hash of the spec content, so that
+any later change of the title, description, examples, rules, questions,
+depends or tags makes it stale; "ready" reports stale approvals
+(no-stale-approvals rule). Status changes do not invalidate approvals.
+
+The reviewer defaults to git config user.name. Approving again renews the
+reviewer's approval.`,
+	Example: `  spec-tdd req approve REQ-001
+  spec-tdd req approve REQ-001 --reviewer alice --note "checked with legal"`,
+	Args: cobra.ExactArgs(1),
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("req.approve")
+
+		reviewer, _ := cmd.Flags().GetString("reviewer")
+		reviewer = strings.TrimSpace(reviewer)
+		if reviewer == "" {
+			reviewer = gitUserName()
+		}
+		if reviewer == "" {
+			reviewer = os.Getenv("USER")
+		}
+		if reviewer == "" {
+			return fmt.Errorf("no reviewer: use --reviewer or set git config user.name")
+		}
+		note, _ := cmd.Flags().GetString("note")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		path, s, err := loadSpecByID(cfg.SpecDir, args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, a := range s.Approvals {
+			if a.Reviewer != reviewer {
+				continue
+			}
+			// A renewal keeps the note unless a new one is given
+			if !cmd.Flags().Changed("note") {
+				note = a.Note
+			}
+			if !a.Stale(s) && a.Note == note {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is already approved by %s (%s)\n", s.ID, reviewer, a.ApprovedAt.Local().Format(journalTimeFormat))
+				return nil
+			}
+		}
+		a := s.Approve(reviewer, note, time.Now())
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "approved %s as %s (hash %s)\n", s.ID, reviewer, a.Hash[:12])
+		for _, stale := range s.StaleApprovals() {
+			fmt.Fprintf(cmd.OutOrStdout(), "  stale: %s (%s)\n", stale.Reviewer, stale.ApprovedAt.Local().Format(journalTimeFormat))
+		}
+		return nil
+	}),
+}
+
+func init() {
+	reqCmd.AddCommand(reqApproveCmd)
+
+	reqApproveCmd.Flags().String("reviewer", "", "Reviewer name (default: git config user.name)")
+	reqApproveCmd.Flags().String("note", "", "Comment stored with the approval")
+}
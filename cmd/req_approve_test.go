@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestReqApproveCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir, &spec.Spec{ID: "REQ-001", Title: "Login",
+		Examples: []spec.Example{{ID: "E1", Given: "registered user", When: "login", Then: "dashboard shown"}}})
+	path := filepath.Join(specDir, "REQ-001.yml")
+	resetCmdFlags(t, reqApproveCmd)
+	origUser := gitUserName
+	t.Cleanup(func() { gitUserName = origUser })
+	gitUserName = func() string { return "alice" }
+
+	approve := func(t *testing.T, args ...string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		reqApproveCmd.SetOut(&buf)
+		if err := reqApproveCmd.ParseFlags(args[1:]); err != nil {
+			t.Fatalf("flag error: %v", err)
+		}
+		if err := reqApproveCmd.RunE(reqApproveCmd, args[:1]); err != nil {
+			t.Fatalf("req approve error: %v", err)
+		}
+		return buf.String()
+	}
+
+	t.Run("defaults to the git user", func(t *testing.T) {
+		out := approve(t, "REQ-001", "--note", "LGTM")
+		if !strings.Contains(out, "approved REQ-001 as alice") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		s, err := spec.Load(path)
+		if err != nil {
+			t.Fatalf("load error: %v", err)
+		}
+		if len(s.Approvals) != 1 || s.Approvals[0].Reviewer != "alice" || s.Approvals[0].Note != "LGTM" || s.Approvals[0].Stale(s) {
+			t.Errorf("unexpected approvals: %+v", s.Approvals)
+		}
+	})
+
+	t.Run("approving again is a no-op", func(t *testing.T) {
+		if out := approve(t, "REQ-001"); !strings.Contains(out, "REQ-001 is already approved by alice") {
+			t.Errorf("unexpected output: %s", out)
+		}
+	})
+
+	t.Run("changes make approvals stale", func(t *testing.T) {
+		approve(t, "REQ-001", "--reviewer", "bob")
+		s, _ := spec.Load(path)
+		s.Examples[0].Then = "home shown"
+		saveTestSpecs(t, specDir, s)
+
+		var buf bytes.Buffer
+		readyCmd.SetOut(&buf)
+		if err := readyCmd.RunE(readyCmd, []string{"REQ-001"}); err == nil {
+			t.Fatal("expected ready to fail with stale approvals")
+		}
+		for _, reviewer := range []string{"alice", "bob"} {
+			if want := "[no-stale-approvals] approval by " + reviewer; !strings.Contains(buf.String(), want) {
+				t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+			}
+		}
+
+		out := approve(t, "REQ-001", "--reviewer", "alice")
+		if !strings.Contains(out, "  stale: bob") {
+			t.Errorf("expected bob's approval to be listed as stale, got: %s", out)
+		}
+		s, _ = spec.Load(path)
+		if stale := s.StaleApprovals(); len(stale) != 1 || stale[0].Reviewer != "bob" {
+			t.Errorf("stale approvals = %+v, want only bob", stale)
+		}
+		if s.Approvals[0].Note != "LGTM" {
+			t.Errorf("renewal should keep the note, got %q", s.Approvals[0].Note)
+		}
+	})
+}
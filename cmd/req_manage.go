@@ -0,0 +1,444 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/guide"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"go.yaml.in/yaml/v3"
+)
+
+// openEditor opens path in $VISUAL or $EDITOR (vi when neither is set) and
+// waits for it to exit. Tests replace it.
+var openEditor = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// reqListItem is one requirement in `req list --format json`.
+type reqListItem struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Status        string   `json:"status"`
+	Namespace     string   `json:"namespace,omitempty"`
+	Parent        string   `json:"parent,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Depends       []string `json:"depends,omitempty"`
+	Examples      int      `json:"examples"`
+	OpenQuestions int      `json:"openQuestions"`
+}
+
+func newReqListItem(s *spec.Spec) reqListItem {
+	return reqListItem{
+		ID:            s.ID,
+		Title:         s.Title,
+		Status:        s.Status,
+		Namespace:     s.Namespace,
+		Parent:        s.Parent,
+		Tags:          s.Tags,
+		Depends:       s.Depends,
+		Examples:      len(s.AllExamples()),
+		OpenQuestions: len(s.OpenQuestions()),
+	}
+}
+
+var reqListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List requirements",
+	Long: `List requirements with their status and example count. Filter by status,
+tag, parent or ID prefix; --format json prints a machine-readable list.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q (allowed: text, json)", format)
+		}
+
+		cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+		specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+		if err != nil {
+			return err
+		}
+		statuses, _ := cmd.Flags().GetStringSlice("status")
+		specs, _, err = filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+		if err != nil {
+			return err
+		}
+		specs, err = filterSpecsBySelect(cmd, specs, cfg.EffectiveLifecycle())
+		if err != nil {
+			return err
+		}
+
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		parent, _ := cmd.Flags().GetString("parent")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		ids := spec.CurrentIDScheme()
+		items := make([]reqListItem, 0, len(specs))
+		for _, s := range specs {
+			if len(tags) > 0 && !slices.ContainsFunc(tags, func(t string) bool { return slices.Contains(s.Tags, t) }) {
+				continue
+			}
+			if parent != "" && s.Parent != parent {
+				continue
+			}
+			if prefix != "" {
+				p, _, ok := ids.Parse(s.ID)
+				if !ok {
+					p, ok = ids.ProvisionalPrefix(s.ID)
+				}
+				if !ok || p != prefix {
+					continue
+				}
+			}
+			items = append(items, newReqListItem(s))
+		}
+
+		w := cmd.OutOrStdout()
+		if format == "json" {
+			data, err := json.MarshalIndent(items, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, string(data))
+			return nil
+		}
+		if len(items) == 0 {
+			fmt.Fprintln(w, "no requirements found")
+			return nil
+		}
+		// The namespace column only appears once specs live in subdirectories
+		namespaced := slices.ContainsFunc(items, func(it reqListItem) bool { return it.Namespace != "" })
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if namespaced {
+			fmt.Fprintln(tw, "ID\tNAMESPACE\tSTATUS\tEXAMPLES\tTITLE")
+		} else {
+			fmt.Fprintln(tw, "ID\tSTATUS\tEXAMPLES\tTITLE")
+		}
+		for _, it := range items {
+			if namespaced {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", it.ID, it.Namespace, it.Status, it.Examples, it.Title)
+			} else {
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", it.ID, it.Status, it.Examples, it.Title)
+			}
+		}
+		return tw.Flush()
+	},
+}
+
+var reqShowCmd = &cobra.Command{
+	Use:   "show <REQ-ID>",
+	Short: "Show a requirement",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "yaml" && format != "json" {
+			return fmt.Errorf("unknown format %q (allowed: yaml, json)", format)
+		}
+
+		cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		_, s, err := loadSpecByID(cfg.SpecDir, args[0])
+		if err != nil {
+			return err
+		}
+		data, err := spec.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			// Go through YAML so the JSON keys match the spec file
+			var doc map[string]any
+			if err := yaml.Unmarshal(data, &doc); err != nil {
+				return err
+			}
+			if data, err = json.MarshalIndent(doc, "", "  "); err != nil {
+				return err
+			}
+			data = append(data, '\n')
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+// reqEditFields are the flags of `req edit`; without any of them the spec is
+// opened in an editor.
+var reqEditFields = []string{"title", "description", "parent", "tags", "depends"}
+
+var reqEditCmd = &cobra.Command{
+	Use:   "edit <REQ-ID>",
+	Short: "Edit a requirement",
+	Long: `Edit a requirement with flags, or without flags in $VISUAL / $EDITOR.
+The result is validated (including depends and parent references) before it is
+saved. The ID cannot be changed here; use "req mv", and "req status" for the
+lifecycle status.`,
+	Args: cobra.ExactArgs(1),
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("req.edit")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		path, s, err := loadSpecByID(cfg.SpecDir, args[0])
+		if err != nil {
+			return err
+		}
+		before, err := spec.Marshal(s)
+		if err != nil {
+			return err
+		}
+		id, status := s.ID, s.Status
+
+		flags := cmd.Flags()
+		if !slices.ContainsFunc(reqEditFields, flags.Changed) {
+			if s, err = editSpecInEditor(s); err != nil {
+				return err
+			}
+		} else {
+			if flags.Changed("title") {
+				s.Title, _ = flags.GetString("title")
+			}
+			if flags.Changed("description") {
+				s.Description, _ = flags.GetString("description")
+			}
+			if flags.Changed("parent") {
+				parent, _ := flags.GetString("parent")
+				s.Parent = strings.TrimSpace(parent)
+			}
+			if flags.Changed("tags") {
+				s.Tags, _ = flags.GetStringSlice("tags")
+			}
+			if flags.Changed("depends") {
+				s.Depends, _ = flags.GetStringSlice("depends")
+			}
+		}
+		if s.ID != id {
+			return fmt.Errorf("the ID cannot be changed by edit; use: spec-tdd req mv %s %s", id, s.ID)
+		}
+		if s.Status != status {
+			return fmt.Errorf("the status cannot be changed by edit; use: spec-tdd req status %s %s", id, s.Status)
+		}
+
+		after, err := spec.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(before, after) {
+			fmt.Fprintf(cmd.OutOrStdout(), "no changes to %s\n", s.ID)
+			return nil
+		}
+		if err := validateSpecRefs(cfg.SpecDir, s); err != nil {
+			return err
+		}
+
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "updated %s\n", path)
+		return nil
+	}),
+}
+
+// editSpecInEditor opens a copy of s in the user's editor and returns the
+// edited and validated spec.
+func editSpecInEditor(s *spec.Spec) (*spec.Spec, error) {
+	data, err := spec.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", s.ID+"-*.yml")
+	if err != nil {
+		return nil, err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := openEditor(tmp); err != nil {
+		return nil, err
+	}
+	edited, err := spec.Load(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("edited spec is invalid, nothing saved: %w", err)
+	}
+	return edited, nil
+}
+
+// validateSpecRefs checks the depends and parent references of s against
+// the other specs in specDir, as if s were saved.
+func validateSpecRefs(specDir string, s *spec.Spec) error {
+	specs, err := spec.LoadAll(specDir)
+	if err != nil {
+		return err
+	}
+	for i, other := range specs {
+		if other.ID == s.ID {
+			specs[i] = s
+		}
+	}
+	if err := spec.ValidateDependsRefs(specs); err != nil {
+		return err
+	}
+	if err := spec.ValidateDependsGraph(specs); err != nil {
+		return err
+	}
+	return spec.ValidateHierarchy(specs)
+}
+
+var reqRmCmd = &cobra.Command{
+	Use:   "rm <REQ-ID>",
+	Short: "Remove a requirement",
+	Long: `Remove a requirement spec. Removal is refused while other requirements
+depend on it or have it as their parent; --force removes it anyway and drops
+those references.`,
+	Args: cobra.ExactArgs(1),
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("req.rm")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		path, s, err := loadSpecByID(cfg.SpecDir, args[0])
+		if err != nil {
+			return err
+		}
+		specs, err := spec.LoadAll(cfg.SpecDir)
+		if err != nil {
+			return err
+		}
+
+		dependents := guide.BuildDependedByMap(specs)[s.ID]
+		children := spec.NewHierarchy(specs).Children(s.ID)
+		if len(dependents) > 0 || len(children) > 0 {
+			force, _ := cmd.Flags().GetBool("force")
+			if !force {
+				var refs []string
+				if len(dependents) > 0 {
+					refs = append(refs, "depended on by "+strings.Join(dependents, ", "))
+				}
+				if len(children) > 0 {
+					refs = append(refs, "parent of "+strings.Join(specIDs(children), ", "))
+				}
+				return fmt.Errorf("cannot remove %s: %s (use --force to remove it and drop those references)", s.ID, strings.Join(refs, "; "))
+			}
+		}
+
+		// Drop the references and remove the file together
+		tx := atomicfile.NewTx()
+		var report bytes.Buffer
+		if err := dropReferences(tx, &report, cfg.SpecDir, specs, s.ID); err != nil {
+			return err
+		}
+		tx.Remove(path)
+		if err := tx.Commit(); err != nil {
+			log.Error("Failed to remove spec", "path", path, "error", err)
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), report.String())
+		fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", path)
+		return nil
+	}),
+}
+
+// dropReferences removes id from the depends and parent of the other specs,
+// staging the changed specs in tx.
+func dropReferences(tx *atomicfile.Tx, w io.Writer, specDir string, specs []*spec.Spec, id string) error {
+	for _, other := range specs {
+		if other.ID == id {
+			continue
+		}
+		changed := false
+		if i := slices.Index(other.Depends, id); i >= 0 {
+			other.Depends = slices.Delete(other.Depends, i, i+1)
+			changed = true
+		}
+		if other.Parent == id {
+			other.Parent = ""
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		path, _, err := loadSpecByID(specDir, other.ID)
+		if err != nil {
+			return err
+		}
+		if err := spec.Stage(tx, path, other); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "dropped %s from %s\n", id, path)
+	}
+	return nil
+}
+
+func specIDs(specs []*spec.Spec) []string {
+	ids := make([]string, len(specs))
+	for i, s := range specs {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func init() {
+	reqCmd.AddCommand(reqListCmd)
+	reqCmd.AddCommand(reqShowCmd)
+	reqCmd.AddCommand(reqEditCmd)
+	reqCmd.AddCommand(reqRmCmd)
+
+	reqListCmd.Flags().StringSlice("status", nil, "Only list specs in these statuses (default: all but excluded, e.g. deprecated)")
+	reqListCmd.Flags().StringSlice("tag", nil, "Only list specs with any of these tags")
+	reqListCmd.Flags().String("parent", "", "Only list direct children of this requirement")
+	reqListCmd.Flags().String("prefix", "", "Only list IDs with this prefix")
+	reqListCmd.Flags().String("format", "text", "Output format: text or json")
+	addSelectFlag(reqListCmd)
+
+	reqShowCmd.Flags().String("format", "yaml", "Output format: yaml or json")
+
+	reqEditCmd.Flags().String("title", "", "New title")
+	reqEditCmd.Flags().String("description", "", "New description")
+	reqEditCmd.Flags().String("parent", "", "New parent requirement ID (empty to clear)")
+	reqEditCmd.Flags().StringSlice("tags", nil, "Replace the tags")
+	reqEditCmd.Flags().StringSlice("depends", nil, "Replace the depends list")
+
+	reqRmCmd.Flags().Bool("force", false, "Remove even if other requirements refer to it, dropping those references")
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/lock"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestReqListCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqListCmd)
+	ex := spec.Example{ID: "E1", Given: "a", When: "b", Then: "c"}
+	saveTestSpecs(t, filepath.Join(tmpDir, ".tdd", "specs"),
+		&spec.Spec{ID: "REQ-001", Title: "Login", Tags: []string{"auth"}, Examples: []spec.Example{ex}},
+		&spec.Spec{ID: "REQ-002", Title: "Logout", Status: "ready", Parent: "REQ-001"},
+		&spec.Spec{ID: "REQ-003", Title: "Legacy", Status: "deprecated"},
+	)
+
+	var buf bytes.Buffer
+	reqListCmd.SetOut(&buf)
+	reqListCmd.SetErr(&bytes.Buffer{})
+	if err := reqListCmd.RunE(reqListCmd, nil); err != nil {
+		t.Fatalf("req list error: %v", err)
+	}
+	want := "ID       STATUS  EXAMPLES  TITLE\nREQ-001  draft   1         Login\nREQ-002  ready   0         Logout\n"
+	if buf.String() != want {
+		t.Errorf("text output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  []string
+	}{
+		{"tag", map[string]string{"tag": "auth"}, []string{"REQ-001"}},
+		{"parent", map[string]string{"parent": "REQ-001"}, []string{"REQ-002"}},
+		{"status", map[string]string{"status": "deprecated"}, []string{"REQ-003"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetCmdFlags(t, reqListCmd)
+			_ = reqListCmd.Flags().Set("format", "json")
+			for k, v := range tt.flags {
+				_ = reqListCmd.Flags().Set(k, v)
+			}
+			buf.Reset()
+			if err := reqListCmd.RunE(reqListCmd, nil); err != nil {
+				t.Fatalf("req list error: %v", err)
+			}
+			var items []reqListItem
+			if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+			}
+			var got []string
+			for _, it := range items {
+				got = append(got, it.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReqShowCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqShowCmd)
+	saveTestSpecs(t, filepath.Join(tmpDir, ".tdd", "specs"), &spec.Spec{ID: "REQ-001", Title: "Login", Tags: []string{"auth"}})
+
+	var buf bytes.Buffer
+	reqShowCmd.SetOut(&buf)
+	reqShowCmd.SetErr(&bytes.Buffer{})
+	if err := reqShowCmd.RunE(reqShowCmd, []string{"REQ-001"}); err != nil {
+		t.Fatalf("req show error: %v", err)
+	}
+	if buf.String() != "version: 2\nid: REQ-001\ntitle: Login\ntags:\n    - auth\n" {
+		t.Errorf("yaml output = %q", buf.String())
+	}
+
+	buf.Reset()
+	_ = reqShowCmd.Flags().Set("format", "json")
+	if err := reqShowCmd.RunE(reqShowCmd, []string{"REQ-001"}); err != nil {
+		t.Fatalf("req show error: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil || doc["title"] != "Login" {
+		t.Errorf("json output = %s, %v", buf.String(), err)
+	}
+
+	if err := reqShowCmd.RunE(reqShowCmd, []string{"REQ-009"}); err == nil {
+		t.Error("expected error for a missing spec")
+	}
+}
+
+func TestReqEditCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login"},
+		&spec.Spec{ID: "REQ-002", Title: "Logout", Depends: []string{"REQ-001"}},
+	)
+	origEditor := openEditor
+	t.Cleanup(func() { openEditor = origEditor })
+	reqEditCmd.SetOut(&bytes.Buffer{})
+
+	t.Run("flags", func(t *testing.T) {
+		resetCmdFlags(t, reqEditCmd)
+		_ = reqEditCmd.Flags().Set("title", "Sign in")
+		_ = reqEditCmd.Flags().Set("tags", "auth,web")
+		if err := reqEditCmd.RunE(reqEditCmd, []string{"REQ-001"}); err != nil {
+			t.Fatalf("req edit error: %v", err)
+		}
+		s, err := spec.Load(filepath.Join(specDir, "REQ-001.yml"))
+		if err != nil || s.Title != "Sign in" || strings.Join(s.Tags, ",") != "auth,web" {
+			t.Errorf("edited spec = %+v, %v", s, err)
+		}
+	})
+
+	t.Run("rejects a dependency cycle", func(t *testing.T) {
+		resetCmdFlags(t, reqEditCmd)
+		_ = reqEditCmd.Flags().Set("depends", "REQ-002")
+		if err := reqEditCmd.RunE(reqEditCmd, []string{"REQ-001"}); err == nil {
+			t.Error("expected cycle error")
+		}
+	})
+
+	t.Run("editor", func(t *testing.T) {
+		resetCmdFlags(t, reqEditCmd)
+		openEditor = func(path string) error {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(path, append(data, []byte("description: Ends the session\n")...), 0644)
+		}
+		if err := reqEditCmd.RunE(reqEditCmd, []string{"REQ-002"}); err != nil {
+			t.Fatalf("req edit error: %v", err)
+		}
+		s, err := spec.Load(filepath.Join(specDir, "REQ-002.yml"))
+		if err != nil || s.Description != "Ends the session" {
+			t.Errorf("edited spec = %+v, %v", s, err)
+		}
+	})
+
+	t.Run("editor cannot change the ID", func(t *testing.T) {
+		resetCmdFlags(t, reqEditCmd)
+		openEditor = func(path string) error {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte(strings.Replace(string(data), "REQ-002", "REQ-005", 1)), 0644)
+		}
+		err := reqEditCmd.RunE(reqEditCmd, []string{"REQ-002"})
+		if err == nil || !strings.Contains(err.Error(), "req mv REQ-002 REQ-005") {
+			t.Errorf("expected ID change refusal, got %v", err)
+		}
+	})
+}
+
+func TestReqRmCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqRmCmd)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login"},
+		&spec.Spec{ID: "REQ-002", Title: "Logout", Depends: []string{"REQ-001"}},
+		&spec.Spec{ID: "REQ-003", Title: "Remember me", Parent: "REQ-001"},
+	)
+	var buf bytes.Buffer
+	reqRmCmd.SetOut(&buf)
+
+	err := reqRmCmd.RunE(reqRmCmd, []string{"REQ-001"})
+	if err == nil || !strings.Contains(err.Error(), "depended on by REQ-002; parent of REQ-003") {
+		t.Fatalf("expected refusal, got %v", err)
+	}
+
+	_ = reqRmCmd.Flags().Set("force", "true")
+	if err := reqRmCmd.RunE(reqRmCmd, []string{"REQ-001"}); err != nil {
+		t.Fatalf("req rm --force error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(specDir, "REQ-001.yml")); !os.IsNotExist(err) {
+		t.Error("spec should be removed")
+	}
+	specs, err := spec.LoadAll(specDir)
+	if err != nil {
+		t.Fatalf("LoadAll error: %v", err)
+	}
+	for _, s := range specs {
+		if len(s.Depends) > 0 || s.Parent != "" {
+			t.Errorf("reference to REQ-001 left in %s: %+v", s.ID, s)
+		}
+	}
+}
+
+func TestWorkspaceLock(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqRmCmd)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir, &spec.Spec{ID: "REQ-001", Title: "Login"})
+	oldTimeout := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { lockTimeout = oldTimeout })
+
+	held, err := lock.Acquire(config.DefaultLockPath, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire error: %v", err)
+	}
+	err = reqRmCmd.RunE(reqRmCmd, []string{"REQ-001"})
+	if !apperrors.IsConflict(err) {
+		t.Fatalf("req rm while locked: error = %v, want a conflict", err)
+	}
+	if _, err := os.Stat(filepath.Join(specDir, "REQ-001.yml")); err != nil {
+		t.Errorf("spec should be kept while locked: %v", err)
+	}
+
+	_ = held.Release()
+	reqRmCmd.SetOut(&bytes.Buffer{})
+	if err := reqRmCmd.RunE(reqRmCmd, []string{"REQ-001"}); err != nil {
+		t.Fatalf("req rm after release error: %v", err)
+	}
+}
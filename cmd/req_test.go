@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
 func TestReqAddCommand(t *testing.T) {
@@ -30,3 +36,405 @@ func TestReqAddCommand(t *testing.T) {
 		t.Fatalf("expected spec file, got %v", err)
 	}
 }
+
+func TestReqStatusCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	path := filepath.Join(tmpDir, ".tdd", "specs", "REQ-001.yml")
+	// A spec written before statuses existed starts out as draft
+	if err := spec.Save(path, &spec.Spec{ID: "REQ-001", Title: "Login"}); err != nil {
+		t.Fatalf("save spec error: %v", err)
+	}
+	t.Cleanup(func() { reqStatusForce = false })
+
+	var buf bytes.Buffer
+	reqStatusCmd.SetOut(&buf)
+
+	if err := reqStatusCmd.RunE(reqStatusCmd, []string{"REQ-001"}); err != nil {
+		t.Fatalf("reqStatusCmd error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "REQ-001: draft\nallowed: ready, deprecated") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	if err := reqStatusCmd.RunE(reqStatusCmd, []string{"REQ-001", "implemented"}); err == nil {
+		t.Fatal("expected draft -> implemented to be rejected")
+	}
+	if err := reqStatusCmd.RunE(reqStatusCmd, []string{"REQ-001", "shipped"}); err == nil {
+		t.Fatal("expected unknown status to be rejected")
+	}
+
+	if err := reqStatusCmd.RunE(reqStatusCmd, []string{"REQ-001", "ready"}); err != nil {
+		t.Fatalf("draft -> ready error: %v", err)
+	}
+	s, err := spec.Load(path)
+	if err != nil {
+		t.Fatalf("load spec error: %v", err)
+	}
+	if s.Status != "ready" {
+		t.Errorf("status = %q, want ready", s.Status)
+	}
+
+	reqStatusForce = true
+	if err := reqStatusCmd.RunE(reqStatusCmd, []string{"REQ-001", "implemented"}); err != nil {
+		t.Fatalf("forced transition error: %v", err)
+	}
+}
+
+func TestTraceExcludesDeprecated(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	ex := []spec.Example{{ID: "E1", Given: "a", When: "b", Then: "c"}}
+	for _, s := range []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Status: "ready", Examples: ex},
+		{ID: "REQ-002", Title: "Legacy login", Status: "deprecated", Examples: ex},
+		{ID: "REQ-003", Title: "Logout", Examples: ex},
+	} {
+		if err := spec.Save(filepath.Join(specDir, s.ID+".yml"), s); err != nil {
+			t.Fatalf("save spec error: %v", err)
+		}
+	}
+
+	traceCmd.SetOut(&bytes.Buffer{})
+	if err := traceCmd.RunE(traceCmd, nil); err != nil {
+		t.Fatalf("traceCmd error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".tdd", "trace.md"))
+	if err != nil {
+		t.Fatalf("read trace error: %v", err)
+	}
+	md := string(data)
+	if strings.Contains(md, "| REQ-002 |") || !strings.Contains(md, "Excluded from coverage: REQ-002") {
+		t.Errorf("deprecated spec should be excluded:\n%s", md)
+	}
+	if !strings.Contains(md, "| REQ-003 | Logout | draft |") {
+		t.Errorf("spec without status should be reported as draft:\n%s", md)
+	}
+
+	if err := traceCmd.Flags().Set("status", "ready"); err != nil {
+		t.Fatalf("set status error: %v", err)
+	}
+	defer func() {
+		_ = traceCmd.Flags().Lookup("status").Value.(pflag.SliceValue).Replace(nil)
+		traceCmd.Flags().Lookup("status").Changed = false
+	}()
+	if err := traceCmd.RunE(traceCmd, nil); err != nil {
+		t.Fatalf("traceCmd error: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(tmpDir, ".tdd", "trace.md"))
+	if strings.Contains(string(data), "| REQ-003 |") || !strings.Contains(string(data), "| REQ-001 |") {
+		t.Errorf("expected only ready specs:\n%s", data)
+	}
+}
+
+func TestReqTreeCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	t.Cleanup(func() { reqAddTitle, reqAddID, reqAddParent = "", "", "" })
+
+	reqAddCmd.SetOut(&bytes.Buffer{})
+	for _, r := range []struct{ title, parent string }{
+		{"Checkout", ""},
+		{"Cart", "REQ-001"},
+		{"Add item", "REQ-002"},
+		{"Payment", "REQ-001"},
+	} {
+		reqAddTitle, reqAddID, reqAddParent = r.title, "", r.parent
+		if err := reqAddCmd.RunE(reqAddCmd, nil); err != nil {
+			t.Fatalf("req add %s error: %v", r.title, err)
+		}
+	}
+
+	reqAddTitle, reqAddParent = "Orphan", "REQ-099"
+	if err := reqAddCmd.RunE(reqAddCmd, nil); err == nil {
+		t.Error("expected error for missing parent")
+	}
+
+	s, err := spec.Load(filepath.Join(specDir, "REQ-003.yml"))
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if s.Parent != "REQ-002" {
+		t.Errorf("parent = %q, want REQ-002", s.Parent)
+	}
+
+	var buf bytes.Buffer
+	reqTreeCmd.SetOut(&buf)
+	if err := reqTreeCmd.RunE(reqTreeCmd, nil); err != nil {
+		t.Fatalf("req tree error: %v", err)
+	}
+	want := "REQ-001 Checkout [draft]\n" +
+		"├── REQ-002 Cart [draft]\n" +
+		"│   └── REQ-003 Add item [draft]\n" +
+		"└── REQ-004 Payment [draft]\n"
+	if buf.String() != want {
+		t.Errorf("tree =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := reqTreeCmd.RunE(reqTreeCmd, []string{"REQ-002"}); err != nil {
+		t.Fatalf("req tree REQ-002 error: %v", err)
+	}
+	if buf.String() != "REQ-002 Cart [draft]\n└── REQ-003 Add item [draft]\n" {
+		t.Errorf("subtree =\n%s", buf.String())
+	}
+
+	// A cycle introduced by hand is reported instead of looping
+	if err := spec.Save(filepath.Join(specDir, "REQ-002.yml"), &spec.Spec{ID: "REQ-002", Title: "Cart", Parent: "REQ-003"}); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	if err := reqTreeCmd.RunE(reqTreeCmd, nil); err == nil || !strings.Contains(err.Error(), "parent cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestReqAddWithIDScheme(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	t.Cleanup(func() {
+		reqAddTitle, reqAddID, reqAddPrefix = "", "", ""
+		spec.SetIDScheme(spec.DefaultIDScheme())
+	})
+	writeConfig := func(ids string) {
+		t.Helper()
+		cfg := "specDir: .tdd/specs\ntestDir: tests\nrunner: vitest\nfileNamePattern: \"req-{{id}}-{{slug}}.test.ts\"\nids:\n" + ids
+		if err := os.WriteFile(filepath.Join(tmpDir, ".tdd", "config.yml"), []byte(cfg), 0644); err != nil {
+			t.Fatalf("write config error: %v", err)
+		}
+	}
+	add := func(prefix string) (string, error) {
+		t.Helper()
+		var buf bytes.Buffer
+		reqAddCmd.SetOut(&buf)
+		reqAddTitle, reqAddID, reqAddPrefix = "Requirement", "", prefix
+		err := reqAddCmd.RunE(reqAddCmd, nil)
+		return buf.String(), err
+	}
+
+	writeConfig("  prefixes: [AUTH, BILL]\n")
+	for _, tc := range []struct{ prefix, want string }{
+		{"", "AUTH-001.yml"},
+		{"AUTH", "AUTH-002.yml"},
+		{"BILL", "BILL-001.yml"},
+	} {
+		out, err := add(tc.prefix)
+		if err != nil || !strings.Contains(out, tc.want) {
+			t.Fatalf("req add --prefix %q = %q, %v; want %s", tc.prefix, out, err, tc.want)
+		}
+	}
+	if _, err := add("REQ"); err == nil || !strings.Contains(err.Error(), "unknown ID prefix") {
+		t.Errorf("expected unknown prefix error, got %v", err)
+	}
+
+	writeConfig("  prefixes: [AUTH, BILL]\n  padding: 4\n  numbering: global\n")
+	if out, err := add("BILL"); err != nil || !strings.Contains(out, "BILL-0003.yml") {
+		t.Errorf("global numbering = %q, %v; want BILL-0003", out, err)
+	}
+}
+
+func TestCurrentGitBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmpDir := setupWorkspace(t)
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("symbolic-ref", "HEAD", "refs/heads/feature/login")
+
+	t.Run("branch without commits", func(t *testing.T) {
+		branch, err := currentGitBranch()
+		if err != nil || branch != "feature/login" {
+			t.Errorf("currentGitBranch() = %q, %v; want feature/login", branch, err)
+		}
+	})
+
+	t.Run("detached HEAD", func(t *testing.T) {
+		saveTestSpecs(t, filepath.Join(tmpDir, ".tdd", "specs"), &spec.Spec{ID: "REQ-001", Title: "Login"})
+		gitCommitAll(t, "first")
+		git("checkout", "-q", "--detach")
+		_, err := currentGitBranch()
+		if err == nil || !strings.Contains(err.Error(), "HEAD is detached") {
+			t.Errorf("error = %v, want a detached HEAD error", err)
+		}
+	})
+}
+
+func TestReqProvisionalAndFinalize(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	origBranch := gitBranch
+	gitBranch = func() (string, error) { return "feature/login", nil }
+	t.Cleanup(func() {
+		gitBranch = origBranch
+		reqAddTitle, reqAddID, reqAddProvisional, reqFinalizeDryRun = "", "", false, false
+	})
+	cfg := "specDir: .tdd/specs\ntestDir: tests\nrunner: vitest\nfileNamePattern: \"req-{{id}}-{{slug}}.test.ts\"\nids:\n  provisional: branch\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tdd", "config.yml"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("write config error: %v", err)
+	}
+	// Meanwhile main gained REQ-001
+	if err := spec.Save(filepath.Join(specDir, "REQ-001.yml"), &spec.Spec{ID: "REQ-001", Title: "Main"}); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	reqAddCmd.SetOut(&bytes.Buffer{})
+	for _, title := range []string{"Login", "Lockout"} {
+		reqAddTitle = title
+		if err := reqAddCmd.RunE(reqAddCmd, nil); err != nil {
+			t.Fatalf("req add error: %v", err)
+		}
+	}
+	lockout, err := spec.Load(filepath.Join(specDir, "REQ-tmp-feature-login.2.yml"))
+	if err != nil {
+		t.Fatalf("expected branch-local ID: %v", err)
+	}
+	lockout.Depends = []string{"REQ-tmp-feature-login.1"}
+	if err := spec.Save(filepath.Join(specDir, lockout.ID+".yml"), lockout); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	testPath := filepath.Join(tmpDir, "tests", "req-REQ-tmp-feature-login.1-login.test.ts")
+	if err := os.MkdirAll(filepath.Dir(testPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(testPath, []byte(`it("REQ-tmp-feature-login.1 E1: ok", () => {})`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	reqFinalizeCmd.SetOut(&buf)
+	reqFinalizeDryRun = true
+	if err := reqFinalizeCmd.RunE(reqFinalizeCmd, nil); err != nil {
+		t.Fatalf("finalize dry-run error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "REQ-tmp-feature-login.1 -> REQ-002\nREQ-tmp-feature-login.2 -> REQ-003\n") {
+		t.Errorf("unexpected plan:\n%s", buf.String())
+	}
+	if _, err := os.Stat(testPath); err != nil {
+		t.Fatal("dry-run must not touch files")
+	}
+
+	buf.Reset()
+	reqFinalizeDryRun = false
+	if err := reqFinalizeCmd.RunE(reqFinalizeCmd, nil); err != nil {
+		t.Fatalf("finalize error: %v", err)
+	}
+	finalized, err := spec.Load(filepath.Join(specDir, "REQ-003.yml"))
+	if err != nil {
+		t.Fatalf("expected REQ-003: %v", err)
+	}
+	if finalized.Title != "Lockout" || len(finalized.Depends) != 1 || finalized.Depends[0] != "REQ-002" {
+		t.Errorf("finalized spec = %+v", finalized)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "tests", "req-REQ-002-login.test.ts"))
+	if err != nil || !strings.Contains(string(data), `it("REQ-002 E1: ok"`) {
+		t.Errorf("test file not rewritten: %q, %v", data, err)
+	}
+
+	buf.Reset()
+	if err := reqFinalizeCmd.RunE(reqFinalizeCmd, nil); err != nil || !strings.Contains(buf.String(), "no provisional IDs") {
+		t.Errorf("second finalize = %q, %v", buf.String(), err)
+	}
+	if err := reqFinalizeCmd.RunE(reqFinalizeCmd, []string{"REQ-001"}); err == nil {
+		t.Error("expected error for a permanent ID")
+	}
+}
+
+func TestReqMvCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	t.Cleanup(func() { reqMvDryRun = false })
+	for _, s := range []*spec.Spec{
+		{ID: "REQ-001", Title: "Login"},
+		{ID: "REQ-002", Title: "Logout", Depends: []string{"REQ-001"}},
+	} {
+		if err := spec.Save(filepath.Join(specDir, s.ID+".yml"), s); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+	testPath := filepath.Join(tmpDir, "tests", "req-REQ-001-login.test.ts")
+	if err := os.MkdirAll(filepath.Dir(testPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(testPath, []byte(`it("REQ-001 E1: ok", () => {})`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	reqMvCmd.SetOut(&buf)
+	reqMvDryRun = true
+	if err := reqMvCmd.RunE(reqMvCmd, []string{"REQ-001", "REQ-010"}); err != nil {
+		t.Fatalf("mv dry-run error: %v", err)
+	}
+	for _, want := range []string{
+		"--- .tdd/specs/REQ-001.yml\n+++ .tdd/specs/REQ-010.yml\n@@ -1,3 +1,3 @@\n version: 2\n-id: REQ-001\n+id: REQ-010\n",
+		"-    - REQ-001\n+    - REQ-010\n",
+		"+++ tests/req-REQ-010-login.test.ts\n@@ -1 +1 @@\n-it(\"REQ-001 E1: ok\", () => {})\n+it(\"REQ-010 E1: ok\", () => {})\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in dry-run output, got:\n%s", want, buf.String())
+		}
+	}
+	if _, err := os.Stat(testPath); err != nil {
+		t.Fatal("dry-run must not touch files")
+	}
+
+	if err := reqMvCmd.RunE(reqMvCmd, []string{"REQ-001", "REQ-002"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected refusal for an existing ID, got %v", err)
+	}
+
+	buf.Reset()
+	reqMvDryRun = false
+	if err := reqMvCmd.RunE(reqMvCmd, []string{"REQ-001", "REQ-010"}); err != nil {
+		t.Fatalf("mv error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "renamed REQ-001 -> REQ-010, 3 file(s) changed") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	logout, err := spec.Load(filepath.Join(specDir, "REQ-002.yml"))
+	if err != nil || logout.Depends[0] != "REQ-010" {
+		t.Errorf("depends not rewritten: %+v, %v", logout, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "tests", "req-REQ-010-login.test.ts")); err != nil {
+		t.Errorf("test file not renamed: %v", err)
+	}
+}
+
+func TestReqRenumberCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	t.Cleanup(func() { reqRenumberDryRun, reqRenumberPrefix = false, "" })
+	for _, s := range []*spec.Spec{
+		{ID: "REQ-001", Title: "Login"},
+		{ID: "REQ-004", Title: "Logout", Depends: []string{"REQ-007"}},
+		{ID: "REQ-007", Title: "Profile", Parent: "REQ-001"},
+	} {
+		if err := spec.Save(filepath.Join(specDir, s.ID+".yml"), s); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	reqRenumberCmd.SetOut(&buf)
+	if err := reqRenumberCmd.RunE(reqRenumberCmd, nil); err != nil {
+		t.Fatalf("renumber error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "REQ-004 -> REQ-002\nREQ-007 -> REQ-003\n") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	ids, err := spec.ListIDs(specDir)
+	if err != nil || strings.Join(ids, ",") != "REQ-001,REQ-002,REQ-003" {
+		t.Errorf("ids after renumber = %v, %v", ids, err)
+	}
+	logout, err := spec.Load(filepath.Join(specDir, "REQ-002.yml"))
+	if err != nil || logout.Title != "Logout" || logout.Depends[0] != "REQ-003" {
+		t.Errorf("renumbered spec = %+v, %v", logout, err)
+	}
+
+	buf.Reset()
+	if err := reqRenumberCmd.RunE(reqRenumberCmd, nil); err != nil || !strings.Contains(buf.String(), "already numbered sequentially") {
+		t.Errorf("second renumber = %q, %v", buf.String(), err)
+	}
+}
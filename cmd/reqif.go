@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/reqif"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var exportReqIFCmd = &cobra.Command{
+	Use:   "reqif",
+	Short: "Export specs as ReqIF XML for requirements management tools",
+	RunE:  runExportReqIF,
+}
+
+var importReqIFCmd = &cobra.Command{
+	Use:   "reqif <file>",
+	Short: "Import or update specs from a ReqIF XML file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  withWorkspaceLock(runImportReqIF),
+}
+
+func init() {
+	exportCmd.AddCommand(exportReqIFCmd)
+	importCmd.AddCommand(importReqIFCmd)
+
+	exportReqIFCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	importReqIFCmd.Flags().Bool("dry-run", false, "Preview without writing files")
+}
+
+func runExportReqIF(cmd *cobra.Command, args []string) error {
+	cfg, err := loadSpecConfigTo(cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	specs, err := spec.LoadAll(cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+	specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+	if err != nil {
+		return err
+	}
+
+	data, err := reqif.Marshal(reqif.Export(specs, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(output, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s (%d specs)\n", output, len(specs))
+	return nil
+}
+
+// runImportReqIF merges requirements from a ReqIF file into the spec
+// directory. Specs are matched by REQ ID, or by SPEC-OBJECT IDENTIFIER for
+// requirements created in another tool, so a file exported by spec-tdd and
+// edited elsewhere, or imported before, updates the existing specs. Fields not carried by ReqIF
+// (source, questions) are kept.
+func runImportReqIF(cmd *cobra.Command, args []string) error {
+	log := GetLogger().WithComponent("import.reqif")
+
+	cfg, err := loadSpecConfig(cmd)
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	ns, err := importNamespace()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	doc, err := reqif.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	existing, err := spec.LoadAll(cfg.SpecDir)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*spec.Spec, len(existing))
+	existingIDs := make([]string, 0, len(existing))
+	identifiers := make(map[string]string)
+	for _, s := range existing {
+		byID[s.ID] = s
+		existingIDs = append(existingIDs, s.ID)
+		if s.Source.ReqIFIdentifier != "" {
+			identifiers[s.Source.ReqIFIdentifier] = s.ID
+		}
+	}
+
+	imported, err := reqif.Import(doc, existingIDs, identifiers)
+	if err != nil {
+		return err
+	}
+
+	// Validate references against the merged set before writing anything
+	merged := make(map[string]*spec.Spec, len(existing)+len(imported))
+	for _, s := range existing {
+		merged[s.ID] = s
+	}
+	for _, s := range imported {
+		merged[s.ID] = s
+	}
+	all := make([]*spec.Spec, 0, len(merged))
+	for _, s := range merged {
+		all = append(all, s)
+	}
+	if err := spec.ValidateDependsRefs(all); err != nil {
+		return fmt.Errorf("dependency validation failed: %w", err)
+	}
+
+	var created, updated, unchanged int
+	var report bytes.Buffer
+	tx := atomicfile.NewTx()
+	for _, in := range imported {
+		// Existing specs stay where they are; new ones go to --namespace
+		cur, ok := byID[in.ID]
+		pathNS := ns
+		if ok {
+			pathNS = cur.Namespace
+		}
+		path, err := spec.PathFor(cfg.SpecDir, pathNS, in.ID)
+		if err != nil {
+			return err
+		}
+		target := in
+		status := "created"
+
+		if ok {
+			next := *cur
+			next.Title = in.Title
+			next.Description = in.Description
+			next.Tags = in.Tags
+			next.Depends = in.Depends
+			next.Examples = in.Examples
+			if reflect.DeepEqual(&next, cur) {
+				unchanged++
+				continue
+			}
+			target = &next
+			status = "updated"
+		}
+
+		if dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "[dry-run] %s: %s (%s)\n", status, in.ID, path)
+			continue
+		}
+		if err := spec.Stage(tx, path, target); err != nil {
+			return err
+		}
+		fmt.Fprintf(&report, "%s: %s\n", status, path)
+		if status == "created" {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	if !dryRun {
+		if err := tx.Commit(); err != nil {
+			log.Error("Failed to save specs", "error", err)
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), report.String())
+		fmt.Fprintf(cmd.OutOrStdout(), "\n%d created, %d updated, %d unchanged\n", created, updated, unchanged)
+	}
+	return nil
+}
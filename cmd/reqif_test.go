@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestReqIFRoundTrip(t *testing.T) {
+	t.Run("re-import updates specs and keeps source", func(t *testing.T) {
+		tmpDir := setupWorkspace(t)
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+
+		login := &spec.Spec{
+			ID:     "REQ-001",
+			Title:  "Login",
+			Source: spec.SourceInfo{FilePath: "docs/auth.md"},
+			Examples: []spec.Example{
+				{ID: "E1", Given: "valid user", When: "login", Then: "success"},
+			},
+		}
+		if err := spec.Save(filepath.Join(specDir, "REQ-001.yml"), login); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+
+		exportReqIFCmd.SetErr(&bytes.Buffer{})
+		if err := exportReqIFCmd.Flags().Set("output", "out.reqif"); err != nil {
+			t.Fatalf("set output error: %v", err)
+		}
+		defer func() { _ = exportReqIFCmd.Flags().Set("output", "") }()
+		if err := exportReqIFCmd.RunE(exportReqIFCmd, nil); err != nil {
+			t.Fatalf("exportReqIFCmd error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importReqIFCmd.SetOut(&buf)
+		if err := importReqIFCmd.RunE(importReqIFCmd, []string{"out.reqif"}); err != nil {
+			t.Fatalf("importReqIFCmd error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "0 created, 0 updated, 1 unchanged") {
+			t.Errorf("expected unchanged round trip, got: %s", buf.String())
+		}
+
+		// Simulate an edit in an external tool
+		data, err := os.ReadFile("out.reqif")
+		if err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+		edited := strings.Replace(string(data), `THE-VALUE="Login"`, `THE-VALUE="Sign in"`, 1)
+		if err := os.WriteFile("out.reqif", []byte(edited), 0644); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		buf.Reset()
+		if err := importReqIFCmd.RunE(importReqIFCmd, []string{"out.reqif"}); err != nil {
+			t.Fatalf("importReqIFCmd error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "0 created, 1 updated") {
+			t.Errorf("expected one update, got: %s", buf.String())
+		}
+
+		s, err := spec.Load(filepath.Join(specDir, "REQ-001.yml"))
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		if s.Title != "Sign in" || s.Source.FilePath != "docs/auth.md" {
+			t.Errorf("unexpected spec after update: %+v", s)
+		}
+		files, _ := spec.ListFiles(specDir)
+		if len(files) != 1 {
+			t.Errorf("expected no duplicate spec files, got %v", files)
+		}
+	})
+
+	t.Run("objects created in another tool are not duplicated", func(t *testing.T) {
+		tmpDir := setupWorkspace(t)
+		specDir := filepath.Join(tmpDir, ".tdd", "specs")
+
+		doc := `<REQ-IF><CORE-CONTENT><REQ-IF-CONTENT>
+<SPEC-TYPES>
+  <SPEC-OBJECT-TYPE IDENTIFIER="t" LONG-NAME="Requirement"><SPEC-ATTRIBUTES>
+    <ATTRIBUTE-DEFINITION-STRING IDENTIFIER="n" LONG-NAME="ReqIF.Name"/>
+  </SPEC-ATTRIBUTES></SPEC-OBJECT-TYPE>
+</SPEC-TYPES>
+<SPEC-OBJECTS>
+  <SPEC-OBJECT IDENTIFIER="doors-17"><TYPE><SPEC-OBJECT-TYPE-REF>t</SPEC-OBJECT-TYPE-REF></TYPE>
+    <VALUES><ATTRIBUTE-VALUE-STRING THE-VALUE="Search"><DEFINITION><ATTRIBUTE-DEFINITION-STRING-REF>n</ATTRIBUTE-DEFINITION-STRING-REF></DEFINITION></ATTRIBUTE-VALUE-STRING></VALUES>
+  </SPEC-OBJECT>
+</SPEC-OBJECTS>
+</REQ-IF-CONTENT></CORE-CONTENT></REQ-IF>`
+		if err := os.WriteFile("in.reqif", []byte(doc), 0644); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		importReqIFCmd.SetOut(&buf)
+		for _, want := range []string{"1 created, 0 updated, 0 unchanged", "0 created, 0 updated, 1 unchanged"} {
+			buf.Reset()
+			if err := importReqIFCmd.RunE(importReqIFCmd, []string{"in.reqif"}); err != nil {
+				t.Fatalf("importReqIFCmd error: %v", err)
+			}
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("expected %q, got: %s", want, buf.String())
+			}
+		}
+
+		files, _ := spec.ListFiles(specDir)
+		if len(files) != 1 {
+			t.Fatalf("expected one spec file, got %v", files)
+		}
+		s, err := spec.Load(files[0])
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		if s.ID != "REQ-001" || s.Source.ReqIFIdentifier != "doors-17" {
+			t.Errorf("unexpected spec: %+v", s)
+		}
+	})
+
+	t.Run("unknown depends are rejected", func(t *testing.T) {
+		setupWorkspace(t)
+
+		doc := `<REQ-IF><CORE-CONTENT><REQ-IF-CONTENT>
+<SPEC-TYPES>
+  <SPEC-OBJECT-TYPE IDENTIFIER="t" LONG-NAME="Requirement"><SPEC-ATTRIBUTES>
+    <ATTRIBUTE-DEFINITION-STRING IDENTIFIER="n" LONG-NAME="ReqIF.Name"/>
+  </SPEC-ATTRIBUTES></SPEC-OBJECT-TYPE>
+  <SPEC-RELATION-TYPE IDENTIFIER="r" LONG-NAME="Depends"/>
+</SPEC-TYPES>
+<SPEC-OBJECTS>
+  <SPEC-OBJECT IDENTIFIER="_spec-tdd_REQ-002"><TYPE><SPEC-OBJECT-TYPE-REF>t</SPEC-OBJECT-TYPE-REF></TYPE>
+    <VALUES><ATTRIBUTE-VALUE-STRING THE-VALUE="B"><DEFINITION><ATTRIBUTE-DEFINITION-STRING-REF>n</ATTRIBUTE-DEFINITION-STRING-REF></DEFINITION></ATTRIBUTE-VALUE-STRING></VALUES>
+  </SPEC-OBJECT>
+</SPEC-OBJECTS>
+<SPEC-RELATIONS>
+  <SPEC-RELATION IDENTIFIER="rel"><TYPE><SPEC-RELATION-TYPE-REF>r</SPEC-RELATION-TYPE-REF></TYPE>
+    <SOURCE><SPEC-OBJECT-REF>_spec-tdd_REQ-002</SPEC-OBJECT-REF></SOURCE>
+    <TARGET><SPEC-OBJECT-REF>_spec-tdd_REQ-009</SPEC-OBJECT-REF></TARGET>
+  </SPEC-RELATION>
+</SPEC-RELATIONS>
+</REQ-IF-CONTENT></CORE-CONTENT></REQ-IF>`
+		if err := os.WriteFile("in.reqif", []byte(doc), 0644); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		importReqIFCmd.SetOut(&bytes.Buffer{})
+		if err := importReqIFCmd.RunE(importReqIFCmd, []string{"in.reqif"}); err == nil {
+			t.Fatal("expected error for unknown relation target")
+		}
+	})
+}
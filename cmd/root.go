@@ -15,6 +15,10 @@ var (
 	debug     bool
 	logFormat string
 	appLogger *logger.Logger
+
+	// namespaceFilter limits commands to the specs in one namespace (a
+	// subdirectory of the spec directory) and below it.
+	namespaceFilter string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -49,6 +53,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./config/config.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode")
 	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format (text or json)")
+	rootCmd.PersistentFlags().StringVar(&namespaceFilter, "namespace", "", "only include specs in this spec subdirectory and below (e.g. auth)")
 }
 
 // initConfig reads in config file and ENV variables if set.
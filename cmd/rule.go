@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+var (
+	ruleReqID string
+	ruleText  string
+)
+
+var ruleCmd = &cobra.Command{
+	Use:   "rule",
+	Short: "Manage requirement rules",
+}
+
+var ruleAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a rule to a requirement",
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("rule.add")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		path, s, err := loadSpecByID(cfg.SpecDir, ruleReqID)
+		if err != nil {
+			return err
+		}
+
+		r := spec.Rule{
+			ID:   spec.NextRuleID(s),
+			Text: strings.TrimSpace(ruleText),
+		}
+		s.Rules = append(s.Rules, r)
+
+		if err := spec.Save(path, s); err != nil {
+			log.Error("Failed to save spec", "path", path, "error", err)
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "added %s to %s\n", r.ID, path)
+		return nil
+	}),
+}
+
+func init() {
+	rootCmd.AddCommand(ruleCmd)
+	ruleCmd.AddCommand(ruleAddCmd)
+
+	ruleAddCmd.Flags().StringVar(&ruleReqID, "req", "", "Requirement ID (e.g., REQ-001)")
+	ruleAddCmd.Flags().StringVar(&ruleText, "text", "", "Rule text")
+	_ = ruleAddCmd.MarkFlagRequired("req")
+	_ = ruleAddCmd.MarkFlagRequired("text")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestRuleAddAndExampleForRule(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	path := filepath.Join(tmpDir, ".tdd", "specs", "REQ-001.yml")
+	if err := spec.Save(path, &spec.Spec{ID: "REQ-001", Title: "Withdraw cash"}); err != nil {
+		t.Fatalf("save spec error: %v", err)
+	}
+	t.Cleanup(func() {
+		ruleReqID, ruleText = "", ""
+		exampleReqID, exampleGiven, exampleWhen, exampleThen, exampleRule = "", "", "", "", ""
+	})
+
+	ruleReqID = "REQ-001"
+	ruleText = "Balance must cover the amount"
+	if err := ruleAddCmd.RunE(ruleAddCmd, nil); err != nil {
+		t.Fatalf("ruleAddCmd error: %v", err)
+	}
+
+	exampleReqID = "REQ-001"
+	exampleGiven, exampleWhen, exampleThen = "balance 10", "withdraw 50", "rejected"
+	exampleRule = "R1"
+	if err := exampleAddCmd.RunE(exampleAddCmd, nil); err != nil {
+		t.Fatalf("exampleAddCmd error: %v", err)
+	}
+
+	loaded, err := spec.Load(path)
+	if err != nil {
+		t.Fatalf("load spec error: %v", err)
+	}
+	if len(loaded.Rules) != 1 || loaded.Rules[0].ID != "R1" {
+		t.Fatalf("expected rule R1, got %+v", loaded.Rules)
+	}
+	if len(loaded.Examples) != 0 || len(loaded.Rules[0].Examples) != 1 || loaded.Rules[0].Examples[0].ID != "E1" {
+		t.Errorf("expected E1 under R1, got %+v / %+v", loaded.Examples, loaded.Rules[0].Examples)
+	}
+
+	exampleRule = "R9"
+	if err := exampleAddCmd.RunE(exampleAddCmd, nil); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+}
@@ -7,19 +7,24 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/ready"
 	"github.com/thirdlf03/spec-tdd/internal/scaffold"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
 var (
-	scaffoldRunner string
-	scaffoldForce  bool
+	scaffoldRunner   string
+	scaffoldForce    bool
+	scaffoldStatuses []string
+	scaffoldReady    string
 )
 
 var scaffoldCmd = &cobra.Command{
 	Use:   "scaffold",
 	Short: "Generate test scaffolds from specs",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
 		log := GetLogger().WithComponent("scaffold")
 
 		cfg, err := loadSpecConfig(cmd)
@@ -35,10 +40,47 @@ var scaffoldCmd = &cobra.Command{
 			return fmt.Errorf("unsupported runner: %s", runner)
 		}
 
-		specs, err := spec.LoadAll(cfg.SpecDir)
+		all, err := spec.LoadAll(cfg.SpecDir)
 		if err != nil {
 			return err
 		}
+		specs, err := filterSpecsByNamespace(cfg.SpecDir, all)
+		if err != nil {
+			return err
+		}
+		specs, skipped, err := filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), scaffoldStatuses)
+		if err != nil {
+			return err
+		}
+		for _, s := range skipped {
+			fmt.Fprintf(cmd.OutOrStdout(), "skip: %s is %s\n", s.ID, s.Status)
+		}
+		specs, err = filterSpecsBySelect(cmd, specs, cfg.EffectiveLifecycle())
+		if err != nil {
+			return err
+		}
+
+		readiness := cfg.EffectiveReadiness()
+		mode := readiness.Scaffold
+		if strings.TrimSpace(scaffoldReady) != "" {
+			mode = scaffoldReady
+		}
+		if !config.IsEnforceMode(mode) {
+			return fmt.Errorf("unsupported --ready mode: %s (use off, warn or error)", mode)
+		}
+		if mode != config.EnforceOff {
+			var notReady []string
+			for _, r := range ready.Check(specs, all, readiness) {
+				if r.Ready() {
+					continue
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s is not ready: %s\n", mode, r.ID, formatFailures(r))
+				notReady = append(notReady, r.ID)
+			}
+			if mode == config.EnforceError && len(notReady) > 0 {
+				return fmt.Errorf("refusing to scaffold specs that are not ready: %s", strings.Join(notReady, ", "))
+			}
+		}
 
 		if err := os.MkdirAll(cfg.TestDir, 0755); err != nil {
 			log.Error("Failed to create test directory", "dir", cfg.TestDir, "error", err)
@@ -54,8 +96,10 @@ var scaffoldCmd = &cobra.Command{
 				return fmt.Errorf("test file exists: %s (use --force to overwrite)", path)
 			}
 
+			// Rule examples without IDs would otherwise collide in test names
+			s.Normalize()
 			content := scaffold.RenderTest(s, runner)
-			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			if err := atomicfile.WriteFile(path, []byte(content), 0644); err != nil {
 				log.Error("Failed to write test file", "path", path, "error", err)
 				return err
 			}
@@ -63,7 +107,7 @@ var scaffoldCmd = &cobra.Command{
 		}
 
 		return nil
-	},
+	}),
 }
 
 func init() {
@@ -71,4 +115,7 @@ func init() {
 
 	scaffoldCmd.Flags().StringVar(&scaffoldRunner, "runner", "", "Override test runner (vitest or jest)")
 	scaffoldCmd.Flags().BoolVar(&scaffoldForce, "force", false, "Overwrite existing test files")
+	scaffoldCmd.Flags().StringVar(&scaffoldReady, "ready", "", "Definition-of-Ready check: off, warn or error (default from config)")
+	scaffoldCmd.Flags().StringSliceVar(&scaffoldStatuses, "status", nil, "Only scaffold specs in these statuses (default: all but excluded, e.g. deprecated)")
+	addSelectFlag(scaffoldCmd)
 }
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/schema"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+const (
+	specSchemaFile   = "spec.schema.json"
+	configSchemaFile = "config.schema.json"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Write JSON Schemas for spec files and .tdd/config.yml",
+	Long: `Write JSON Schemas for spec files and .tdd/config.yml so that editors can
+validate and complete them. The spec schema follows the configured ID scheme and
+lifecycle; run the command again after changing them.
+
+With --inject, a "# yaml-language-server: $schema=..." line is put at the top of
+every spec file and of .tdd/config.yml (VS Code YAML extension and other
+yaml-language-server clients pick it up).`,
+	Args: cobra.NoArgs,
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("schema")
+
+		cfg, err := loadSpecConfig(cmd)
+		if err != nil {
+			return err
+		}
+		dir, _ := cmd.Flags().GetString("dir")
+		inject, _ := cmd.Flags().GetBool("inject")
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Error("Failed to create schema directory", "dir", dir, "error", err)
+			return err
+		}
+		specSchema := filepath.Join(dir, specSchemaFile)
+		configSchema := filepath.Join(dir, configSchemaFile)
+		for path, s := range map[string]*schema.Schema{
+			specSchema:   schema.Spec(spec.CurrentIDScheme(), cfg.EffectiveLifecycle()),
+			configSchema: schema.Config(),
+		} {
+			data, err := schema.Marshal(s)
+			if err != nil {
+				return err
+			}
+			if err := atomicfile.WriteFile(path, data, 0644); err != nil {
+				log.Error("Failed to write schema", "path", path, "error", err)
+				return err
+			}
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\nwrote %s\n", specSchema, configSchema)
+
+		if !inject {
+			return nil
+		}
+		files, err := spec.ListFiles(cfg.SpecDir)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		targets := make(map[string]string, len(files)+1)
+		for _, f := range files {
+			targets[f] = specSchema
+		}
+		if _, err := os.Stat(config.DefaultSpecConfigPath); err == nil {
+			targets[config.DefaultSpecConfigPath] = configSchema
+		}
+
+		count := 0
+		for _, path := range append(files, config.DefaultSpecConfigPath) {
+			schemaPath, ok := targets[path]
+			if !ok {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			updated, changed := schema.InjectHeader(data, schema.Ref(path, schemaPath))
+			if !changed {
+				continue
+			}
+			if err := atomicfile.WriteFile(path, updated, 0644); err != nil {
+				log.Error("Failed to inject schema header", "path", path, "error", err)
+				return err
+			}
+			count++
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "injected schema header into %d file(s)\n", count)
+		return nil
+	}),
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+
+	schemaCmd.Flags().String("dir", filepath.Join(".tdd", "schema"), "Directory to write the schemas to")
+	schemaCmd.Flags().Bool("inject", false, "Add a yaml-language-server $schema header to spec files and .tdd/config.yml")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, schemaCmd)
+	specPath := filepath.Join(tmpDir, ".tdd", "specs", "REQ-001.yml")
+	saveTestSpecs(t, filepath.Dir(specPath), &spec.Spec{ID: "REQ-001", Title: "Login"})
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tdd", "config.yml"), []byte("runner: jest\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	schemaCmd.SetOut(&buf)
+	_ = schemaCmd.Flags().Set("inject", "true")
+	if err := schemaCmd.RunE(schemaCmd, nil); err != nil {
+		t.Fatalf("schema error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "injected schema header into 2 file(s)") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".tdd", "schema", "spec.schema.json"))
+	if err != nil {
+		t.Fatalf("spec schema not written: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid spec schema: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".tdd", "schema", "config.schema.json")); err != nil {
+		t.Errorf("config schema not written: %v", err)
+	}
+
+	cfgData, _ := os.ReadFile(filepath.Join(tmpDir, ".tdd", "config.yml"))
+	if string(cfgData) != "# yaml-language-server: $schema=schema/config.schema.json\nrunner: jest\n" {
+		t.Errorf("config.yml = %q", cfgData)
+	}
+
+	// The header survives programmatic edits
+	s, err := spec.Load(specPath)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	s.Title = "Sign in"
+	if err := spec.Save(specPath, s); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	specData, _ := os.ReadFile(specPath)
+	if !strings.HasPrefix(string(specData), "# yaml-language-server: $schema=../schema/spec.schema.json\n") {
+		t.Errorf("spec file = %q", specData)
+	}
+
+	buf.Reset()
+	if err := schemaCmd.RunE(schemaCmd, nil); err != nil {
+		t.Fatalf("schema error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "injected schema header into 0 file(s)") {
+		t.Errorf("second run should not change files:\n%s", buf.String())
+	}
+}
@@ -2,18 +2,268 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
 	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/journal"
+	"github.com/thirdlf03/spec-tdd/internal/lock"
+	"github.com/thirdlf03/spec-tdd/internal/query"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
 func loadSpecConfig(cmd *cobra.Command) (config.SpecConfig, error) {
+	return loadSpecConfigTo(cmd.OutOrStdout())
+}
+
+// loadSpecConfigTo is loadSpecConfig with the defaults notice written to w.
+// Commands that stream data to stdout pass stderr instead.
+func loadSpecConfigTo(w io.Writer) (config.SpecConfig, error) {
 	cfg, loaded, err := config.LoadSpecConfig(config.DefaultSpecConfigPath)
 	if err != nil {
 		return config.SpecConfig{}, err
 	}
 	if !loaded {
-		fmt.Fprintf(cmd.OutOrStdout(), "config not found, using defaults at %s\n", config.DefaultSpecConfigPath)
+		fmt.Fprintf(w, "config not found, using defaults at %s\n", config.DefaultSpecConfigPath)
 	}
+	spec.SetIDScheme(spec.NewIDScheme(cfg.IDs.Prefixes, cfg.IDs.Padding, cfg.IDs.Numbering))
+	ensureGitignore()
+	spec.SetIndexPath(config.DefaultIndexPath)
 	return cfg, nil
 }
+
+// ensureGitignore keeps the index, lock and journal out of git, also in
+// workspaces created before `init` wrote .tdd/.gitignore. The file is
+// local setup, so a failure (e.g. a read-only checkout) is only logged.
+func ensureGitignore() {
+	if err := config.EnsureGitignore(config.DefaultGitignorePath); err != nil {
+		GetLogger().Warn("Failed to update .gitignore", "path", config.DefaultGitignorePath, "error", err)
+	}
+}
+
+// autoIDNumber returns the number of id when it counts towards automatically
+// assigned IDs: IDs with the default prefix, or any ID with global numbering.
+func autoIDNumber(id string) (int, bool) {
+	ids := spec.CurrentIDScheme()
+	prefix, n, ok := ids.Parse(id)
+	if !ok || (ids.Numbering != spec.NumberingGlobal && prefix != ids.DefaultPrefix()) {
+		return 0, false
+	}
+	return n, true
+}
+
+// autoID formats the n-th automatically assigned ID with the default prefix.
+func autoID(n int) string {
+	ids := spec.CurrentIDScheme()
+	return ids.Format(ids.DefaultPrefix(), n)
+}
+
+// lockTimeout is how long a command waits for another spec-tdd process to
+// finish modifying the workspace.
+var lockTimeout = 10 * time.Second
+
+// journalOp is the journal operation of the running command, set by
+// withWorkspaceLock.
+var journalOp *journal.Operation
+
+// withWorkspaceLock runs a command that modifies specs, config or reports
+// while holding the workspace lock, so concurrent runs (e.g. two `example
+// add`) cannot lose each other's updates. The files the command changes are
+// recorded in the journal (see `log` and `undo`), even if it fails halfway.
+func withWorkspaceLock(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		l, err := lock.Acquire(config.DefaultLockPath, lockTimeout)
+		if err != nil {
+			return err
+		}
+		defer l.Release()
+		ensureGitignore()
+
+		op := journal.New(commandLine(cmd, args))
+		index := filepath.Clean(config.DefaultIndexPath)
+		gitignore := filepath.Clean(config.DefaultGitignorePath)
+		journalOp = op
+		atomicfile.SetObserver(func(c atomicfile.Change) {
+			// The index is a cache and .gitignore local setup, not part of
+			// the workspace history
+			if p := filepath.Clean(c.Path); p != index && p != gitignore {
+				op.Record(c)
+			}
+		})
+		runErr := run(cmd, args)
+		atomicfile.SetObserver(nil)
+		journalOp = nil
+
+		if err := journal.Save(config.DefaultJournalDir, op); err != nil {
+			GetLogger().Error("Failed to write journal", "command", op.Command, "error", err)
+			if runErr == nil {
+				return err
+			}
+		}
+		return runErr
+	}
+}
+
+// withReportLock runs a command that only writes reports generated from the
+// specs (trace, map, guide) while holding the workspace lock. Reports are
+// rebuilt on every run, so they are not journaled: `undo` after `trace`
+// reverts the last change of the specs, not the report.
+func withReportLock(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		l, err := lock.Acquire(config.DefaultLockPath, lockTimeout)
+		if err != nil {
+			return err
+		}
+		defer l.Release()
+		ensureGitignore()
+		return run(cmd, args)
+	}
+}
+
+// commandLine reconstructs the invocation for the journal, e.g.
+// "spec-tdd req rm REQ-001 --force".
+func commandLine(cmd *cobra.Command, args []string) string {
+	parts := []string{cmd.CommandPath()}
+	for _, a := range args {
+		parts = append(parts, quoteArg(a))
+	}
+	visit := func(f *pflag.Flag) {
+		switch v := f.Value.(type) {
+		case pflag.SliceValue:
+			for _, item := range v.GetSlice() {
+				parts = append(parts, "--"+f.Name+"="+quoteArg(item))
+			}
+		default:
+			if f.Value.Type() == "bool" && f.Value.String() == "true" {
+				parts = append(parts, "--"+f.Name)
+			} else {
+				parts = append(parts, "--"+f.Name+"="+quoteArg(f.Value.String()))
+			}
+		}
+	}
+	cmd.Flags().Visit(visit)
+	cmd.InheritedFlags().Visit(visit)
+	return strings.Join(parts, " ")
+}
+
+func quoteArg(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"'") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// filterSpecsByNamespace keeps the specs selected by --namespace. A
+// namespace without a directory is an error, so a typo does not silently
+// select nothing.
+func filterSpecsByNamespace(specDir string, specs []*spec.Spec) ([]*spec.Spec, error) {
+	ns := spec.CleanNamespace(namespaceFilter)
+	if ns == "" {
+		return specs, nil
+	}
+	if err := spec.ValidateNamespace(ns); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(filepath.Join(specDir, filepath.FromSlash(ns))); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("unknown namespace %q (no directory %s)", ns, filepath.Join(specDir, filepath.FromSlash(ns)))
+	}
+	return spec.FilterNamespace(specs, ns), nil
+}
+
+// importNamespace returns the namespace given with --namespace, in which
+// importers create new specs. Unlike filterSpecsByNamespace, the directory
+// does not need to exist yet.
+func importNamespace() (string, error) {
+	ns := spec.CleanNamespace(namespaceFilter)
+	if ns == "" {
+		return "", nil
+	}
+	if err := spec.ValidateNamespace(ns); err != nil {
+		return "", err
+	}
+	return ns, nil
+}
+
+// filterSpecsByStatus fills in the initial status for specs without one
+// (in-memory) and keeps specs whose status is listed in statuses. With no
+// statuses, everything except the lifecycle's excluded statuses is kept.
+// The second result holds the specs that were left out.
+func filterSpecsByStatus(specs []*spec.Spec, lc config.LifecycleConfig, statuses []string) ([]*spec.Spec, []*spec.Spec, error) {
+	want := make(map[string]bool, len(statuses))
+	for _, st := range statuses {
+		st = strings.TrimSpace(st)
+		if !lc.IsState(st) {
+			return nil, nil, fmt.Errorf("unknown status %q (allowed: %s)", st, strings.Join(lc.States(), ", "))
+		}
+		want[st] = true
+	}
+
+	var kept, dropped []*spec.Spec
+	for _, s := range specs {
+		s.Status = lc.Effective(s.Status)
+		include := !lc.IsExcluded(s.Status)
+		if len(want) > 0 {
+			include = want[s.Status]
+		}
+		if include {
+			kept = append(kept, s)
+		} else {
+			dropped = append(dropped, s)
+		}
+	}
+	return kept, dropped, nil
+}
+
+// addSelectFlag registers --select on cmd.
+func addSelectFlag(cmd *cobra.Command) {
+	cmd.Flags().String("select", "", `Only include specs matching this query, e.g. "tag:auth AND NOT has:examples" (see "spec-tdd query --help")`)
+}
+
+// parseQuery parses a spec query. Status terms are checked against the
+// lifecycle, so a typo does not silently select nothing.
+func parseQuery(src string, lc config.LifecycleConfig) (*query.Query, error) {
+	q, err := query.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range q.Terms() {
+		if t.Field == "status" && !lc.IsState(t.Value) {
+			return nil, fmt.Errorf("unknown status %q in query (allowed: %s)", t.Value, strings.Join(lc.States(), ", "))
+		}
+	}
+	return q, nil
+}
+
+// filterSpecsBySelect keeps the specs matching the --select query of cmd, if any.
+// Specs without a status are matched with the lifecycle's initial status;
+// the specs themselves are not modified.
+func filterSpecsBySelect(cmd *cobra.Command, specs []*spec.Spec, lc config.LifecycleConfig) ([]*spec.Spec, error) {
+	src, _ := cmd.Flags().GetString("select")
+	if strings.TrimSpace(src) == "" {
+		return specs, nil
+	}
+	q, err := parseQuery(src, lc)
+	if err != nil {
+		return nil, fmt.Errorf("--select: %w", err)
+	}
+	return matchQuery(q, specs, lc), nil
+}
+
+func matchQuery(q *query.Query, specs []*spec.Spec, lc config.LifecycleConfig) []*spec.Spec {
+	out := make([]*spec.Spec, 0, len(specs))
+	for _, s := range specs {
+		c := *s
+		c.Status = lc.Effective(s.Status)
+		if q.Match(&c) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/baseline"
 	"github.com/thirdlf03/spec-tdd/internal/config"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 	"github.com/thirdlf03/spec-tdd/internal/trace"
@@ -14,7 +16,7 @@ import (
 var traceCmd = &cobra.Command{
 	Use:   "trace",
 	Short: "Generate traceability report",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	RunE: withReportLock(func(cmd *cobra.Command, args []string) error {
 		log := GetLogger().WithComponent("trace")
 
 		cfg, err := loadSpecConfig(cmd)
@@ -26,13 +28,48 @@ var traceCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if err := spec.ValidateHierarchy(specs); err != nil {
+			return err
+		}
+		all := specs
+		specs, err = filterSpecsByNamespace(cfg.SpecDir, specs)
+		if err != nil {
+			return err
+		}
+
+		statuses, _ := cmd.Flags().GetStringSlice("status")
+		specs, excluded, err := filterSpecsByStatus(specs, cfg.EffectiveLifecycle(), statuses)
+		if err != nil {
+			return err
+		}
+		specs, err = filterSpecsBySelect(cmd, specs, cfg.EffectiveLifecycle())
+		if err != nil {
+			return err
+		}
 
 		counts, err := trace.CountTestsByReq(cfg.TestDir)
 		if err != nil {
 			return err
 		}
 
+		exampleCounts, err := trace.CountTestsByExample(cfg.TestDir)
+		if err != nil {
+			return err
+		}
+
 		report := trace.BuildReport(specs, counts)
+		report.AddRuleCoverage(specs, exampleCounts)
+		report.AddHierarchy(specs)
+		for _, s := range excluded {
+			report.Excluded = append(report.Excluded, s.ID)
+		}
+		if name, _ := cmd.Flags().GetString("baseline"); name != "" {
+			b, err := baseline.Load(config.DefaultBaselineDir, name)
+			if err != nil {
+				return err
+			}
+			report.AddBaseline(compareBaseline(cfg, b, all), counts)
+		}
 
 		outputDir := filepath.Dir(config.DefaultSpecConfigPath)
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -45,13 +82,13 @@ var traceCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		if err := atomicfile.WriteFile(jsonPath, data, 0644); err != nil {
 			log.Error("Failed to write trace JSON", "path", jsonPath, "error", err)
 			return err
 		}
 
 		mdPath := filepath.Join(outputDir, "trace.md")
-		if err := os.WriteFile(mdPath, []byte(report.ToMarkdown()), 0644); err != nil {
+		if err := atomicfile.WriteFile(mdPath, []byte(report.ToMarkdown()), 0644); err != nil {
 			log.Error("Failed to write trace markdown", "path", mdPath, "error", err)
 			return err
 		}
@@ -59,9 +96,13 @@ var traceCmd = &cobra.Command{
 		fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\n", jsonPath)
 		fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\n", mdPath)
 		return nil
-	},
+	}),
 }
 
 func init() {
 	rootCmd.AddCommand(traceCmd)
+
+	traceCmd.Flags().String("baseline", "", "Flag requirements (and their tests) that changed since this baseline")
+	traceCmd.Flags().StringSlice("status", nil, "Only report specs in these statuses (default: all but excluded, e.g. deprecated)")
+	addSelectFlag(traceCmd)
 }
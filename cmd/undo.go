@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/journal"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last operation recorded in the journal",
+	Long: fmt.Sprintf(`Revert the files changed by the last command that modified the workspace,
+as recorded in the journal (%s, see "log"). Running undo again reverts the
+operation before that; undo itself is recorded but cannot be undone.
+
+Files changed since the operation are not overwritten unless --force is
+given. With --dry-run, the files are listed and nothing is written.`, config.DefaultJournalDir),
+	Args: cobra.NoArgs,
+	RunE: withWorkspaceLock(func(cmd *cobra.Command, args []string) error {
+		log := GetLogger().WithComponent("undo")
+
+		ops, err := journal.Load(config.DefaultJournalDir)
+		if err != nil {
+			return err
+		}
+		op, err := journal.LastUndoable(ops)
+		if err != nil {
+			return err
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		modified, err := journal.Modified(op)
+		if err != nil {
+			return err
+		}
+		if len(modified) > 0 && !force {
+			return apperrors.New("undo", apperrors.ErrConflict,
+				fmt.Sprintf("files changed since #%d (%s): %s; use --force to revert them anyway", op.ID, op.Command, strings.Join(modified, ", ")))
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "#%d %s (%s)\n", op.ID, op.Command, op.Time.Local().Format(journalTimeFormat))
+		for _, c := range op.Changes {
+			action := "restore"
+			if c.Kind() == "created" {
+				action = "remove"
+			}
+			fmt.Fprintf(out, "  %s %s\n", action, c.Path)
+		}
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			return nil
+		}
+
+		tx := atomicfile.NewTx()
+		journal.Revert(tx, op)
+		if err := tx.Commit(); err != nil {
+			log.Error("Failed to revert operation", "id", op.ID, "error", err)
+			return err
+		}
+		if journalOp != nil {
+			journalOp.Undoes = op.ID
+		}
+		fmt.Fprintf(out, "reverted %d file(s)\n", len(op.Changes))
+		return nil
+	}),
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().Bool("dry-run", false, "List the files that would be reverted without writing them")
+	undoCmd.Flags().Bool("force", false, "Revert files even if they changed since the operation")
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestUndoAndLog(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqRmCmd)
+	resetCmdFlags(t, undoCmd)
+	resetCmdFlags(t, logCmd)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir,
+		&spec.Spec{ID: "REQ-001", Title: "Login"},
+		&spec.Spec{ID: "REQ-002", Title: "Logout", Depends: []string{"REQ-001"}},
+	)
+	before, err := os.ReadFile(filepath.Join(specDir, "REQ-002.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqRmCmd.SetOut(&bytes.Buffer{})
+	_ = reqRmCmd.Flags().Set("force", "true")
+	if err := reqRmCmd.RunE(reqRmCmd, []string{"REQ-001"}); err != nil {
+		t.Fatalf("req rm error: %v", err)
+	}
+
+	t.Run("log lists the operation", func(t *testing.T) {
+		var buf bytes.Buffer
+		logCmd.SetOut(&buf)
+		_ = logCmd.Flags().Set("files", "true")
+		if err := logCmd.RunE(logCmd, nil); err != nil {
+			t.Fatalf("log error: %v", err)
+		}
+		for _, want := range []string{"#1", "spec-tdd req rm REQ-001 --force  (2 file(s))", "modified  .tdd/specs/REQ-002.yml", "removed   .tdd/specs/REQ-001.yml"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("log output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("undo reverts the operation", func(t *testing.T) {
+		var buf bytes.Buffer
+		undoCmd.SetOut(&buf)
+		if err := undoCmd.RunE(undoCmd, nil); err != nil {
+			t.Fatalf("undo error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "reverted 2 file(s)") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+		if _, err := os.Stat(filepath.Join(specDir, "REQ-001.yml")); err != nil {
+			t.Errorf("REQ-001 should be restored: %v", err)
+		}
+		if got, _ := os.ReadFile(filepath.Join(specDir, "REQ-002.yml")); !bytes.Equal(got, before) {
+			t.Errorf("REQ-002 = %q, want %q", got, before)
+		}
+
+		err := undoCmd.RunE(undoCmd, nil)
+		if !apperrors.IsNotFound(err) {
+			t.Errorf("second undo: error = %v, want nothing to undo", err)
+		}
+	})
+
+	t.Run("log marks the undone operation", func(t *testing.T) {
+		var buf bytes.Buffer
+		logCmd.SetOut(&buf)
+		_ = logCmd.Flags().Set("format", "json")
+		if err := logCmd.RunE(logCmd, nil); err != nil {
+			t.Fatalf("log error: %v", err)
+		}
+		var items []logItem
+		if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if len(items) != 2 || items[0].Undoes != 1 || items[0].Command != "spec-tdd undo" || !items[1].Undone {
+			t.Errorf("unexpected log: %+v", items)
+		}
+	})
+}
+
+func TestUndoSkipsReports(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqRmCmd)
+	resetCmdFlags(t, traceCmd)
+	resetCmdFlags(t, undoCmd)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir, &spec.Spec{ID: "REQ-001", Title: "Login"}, &spec.Spec{ID: "REQ-002", Title: "Logout"})
+	reqRmCmd.SetOut(&bytes.Buffer{})
+	if err := reqRmCmd.RunE(reqRmCmd, []string{"REQ-002"}); err != nil {
+		t.Fatalf("req rm error: %v", err)
+	}
+	traceCmd.SetOut(&bytes.Buffer{})
+	if err := traceCmd.RunE(traceCmd, nil); err != nil {
+		t.Fatalf("trace error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	undoCmd.SetOut(&buf)
+	if err := undoCmd.RunE(undoCmd, nil); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "spec-tdd req rm REQ-002") {
+		t.Errorf("undo should revert req rm, got:\n%s", buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(specDir, "REQ-002.yml")); err != nil {
+		t.Errorf("REQ-002 should be restored: %v", err)
+	}
+	for _, report := range []string{"trace.json", "trace.md"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, ".tdd", report)); err != nil {
+			t.Errorf("%s should be kept: %v", report, err)
+		}
+	}
+}
+
+func TestUndoRefusesChangedFiles(t *testing.T) {
+	tmpDir := setupWorkspace(t)
+	resetCmdFlags(t, reqRmCmd)
+	resetCmdFlags(t, undoCmd)
+	specDir := filepath.Join(tmpDir, ".tdd", "specs")
+	saveTestSpecs(t, specDir, &spec.Spec{ID: "REQ-001", Title: "Login"})
+	reqRmCmd.SetOut(&bytes.Buffer{})
+	if err := reqRmCmd.RunE(reqRmCmd, []string{"REQ-001"}); err != nil {
+		t.Fatalf("req rm error: %v", err)
+	}
+	saveTestSpecs(t, specDir, &spec.Spec{ID: "REQ-001", Title: "Recreated"})
+
+	undoCmd.SetOut(&bytes.Buffer{})
+	err := undoCmd.RunE(undoCmd, nil)
+	if !apperrors.IsConflict(err) || !strings.Contains(err.Error(), "REQ-001.yml") {
+		t.Fatalf("expected conflict naming REQ-001.yml, got %v", err)
+	}
+
+	_ = undoCmd.Flags().Set("force", "true")
+	if err := undoCmd.RunE(undoCmd, nil); err != nil {
+		t.Fatalf("undo --force error: %v", err)
+	}
+	s, err := spec.Load(filepath.Join(specDir, "REQ-001.yml"))
+	if err != nil || s.Title != "Login" {
+		t.Errorf("REQ-001 = %+v, %v; want the original", s, err)
+	}
+}
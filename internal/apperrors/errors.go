@@ -18,6 +18,9 @@ var (
 
 	// ErrInternal indicates an internal error occurred
 	ErrInternal = errors.New("internal error")
+
+	// ErrConflict indicates a resource is in use, e.g. a held lock
+	ErrConflict = errors.New("conflict")
 )
 
 // AppError represents an application-specific error with additional context
@@ -87,6 +90,21 @@ func IsInternal(err error) bool {
 	return errors.Is(err, ErrInternal)
 }
 
+// IsConflict checks if an error is ErrConflict
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// Message returns the user-facing message of an AppError, without the
+// operation prefix, or err.Error() when there is none.
+func Message(err error) string {
+	var appErr *AppError
+	if errors.As(err, &appErr) && appErr.Message != "" {
+		return appErr.Message
+	}
+	return err.Error()
+}
+
 // Join combines multiple errors into one
 // This is a convenience wrapper around errors.Join from Go 1.20+
 func Join(errs ...error) error {
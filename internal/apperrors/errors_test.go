@@ -195,6 +195,26 @@ func TestIsInternal(t *testing.T) {
 	}
 }
 
+func TestIsConflict(t *testing.T) {
+	if !IsConflict(Wrap("op", ErrConflict)) {
+		t.Error("IsConflict(wrapped ErrConflict) = false, want true")
+	}
+
+	if IsConflict(ErrInternal) {
+		t.Error("IsConflict(ErrInternal) = true, want false")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	err := New("spec.Validate", ErrInvalidInput, "title is required")
+	if got := Message(err); got != "title is required" {
+		t.Errorf("Message() = %q, want the AppError message", got)
+	}
+	if got := Message(errors.New("plain")); got != "plain" {
+		t.Errorf("Message() = %q, want plain", got)
+	}
+}
+
 func TestJoin(t *testing.T) {
 	err1 := errors.New("error 1")
 	err2 := errors.New("error 2")
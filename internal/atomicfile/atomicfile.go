@@ -0,0 +1,258 @@
+// Package atomicfile writes files so that readers never see partial content:
+// data goes to a temporary file in the target's directory, which is then
+// renamed over the target. Tx extends this to several files that must change
+// together.
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// tempPattern names staged files. The .tmp extension keeps spec discovery
+// and test scanning from picking them up.
+const tempPattern = ".spec-tdd-*.tmp"
+
+// Change describes a file change that has been made.
+type Change struct {
+	Path string
+	// Before is the previous content and Mode the previous permission
+	// bits; Existed is false if the file was created.
+	Before  []byte
+	Mode    os.FileMode
+	Existed bool
+	// After is the new content; Removed is true if the file was removed.
+	After   []byte
+	Removed bool
+}
+
+var observer func(Change)
+
+// rename is os.Rename; tests replace it to fail a commit halfway.
+var rename = os.Rename
+
+// SetObserver registers fn to be called after every successful write or
+// removal (e.g. to journal them). nil disables it.
+func SetObserver(fn func(Change)) {
+	observer = fn
+}
+
+// WriteFile writes data to path atomically, creating parent directories.
+// An existing file keeps its mode; perm applies to new files.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	var o original
+	if observer != nil {
+		var err error
+		if o, err = read(path); err != nil {
+			return err
+		}
+	}
+	tmp, err := stage(path, data, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if observer != nil {
+		observer(Change{Path: path, Before: o.data, Mode: o.perm, Existed: o.existed, After: data})
+	}
+	return nil
+}
+
+// stage writes data to a synced temporary file next to path. The file gets
+// the mode of path when it exists, as os.WriteFile keeps it, and perm
+// otherwise.
+func stage(path string, data []byte, perm os.FileMode) (string, error) {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, tempPattern)
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	fail := func(err error) (string, error) {
+		f.Close()
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		return fail(err)
+	}
+	if err := f.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := f.Chmod(perm); err != nil {
+		return fail(err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return tmp, nil
+}
+
+// Tx collects file writes and removals and applies them as one unit.
+type Tx struct {
+	writes  []write
+	removes []string
+}
+
+type write struct {
+	path string
+	data []byte
+	perm os.FileMode
+}
+
+// original is the state of a path before Commit touched it.
+type original struct {
+	existed bool
+	data    []byte
+	perm    os.FileMode
+}
+
+// NewTx returns an empty transaction.
+func NewTx() *Tx {
+	return &Tx{}
+}
+
+// Write schedules data to be written to path. A later Write to the same path
+// replaces the earlier one.
+func (tx *Tx) Write(path string, data []byte, perm os.FileMode) {
+	for i := range tx.writes {
+		if tx.writes[i].path == path {
+			tx.writes[i] = write{path, data, perm}
+			return
+		}
+	}
+	tx.writes = append(tx.writes, write{path, data, perm})
+}
+
+// Remove schedules path to be removed. Removals happen before writes, so a
+// file can be removed and another written in its place (e.g. swapping two
+// files' names).
+func (tx *Tx) Remove(path string) {
+	tx.removes = append(tx.removes, path)
+}
+
+// Len returns the number of scheduled operations.
+func (tx *Tx) Len() int {
+	return len(tx.writes) + len(tx.removes)
+}
+
+// Commit applies the transaction. Every new content is staged in a
+// temporary file first, so a failed write leaves every file untouched; if
+// moving the staged files into place fails, the original files are
+// restored (best effort).
+func (tx *Tx) Commit() error {
+	saved := make(map[string]original)
+	remember := func(path string) error {
+		if _, ok := saved[path]; ok {
+			return nil
+		}
+		o, err := read(path)
+		if err != nil {
+			return err
+		}
+		saved[path] = o
+		return nil
+	}
+	for _, path := range tx.removes {
+		if err := remember(path); err != nil {
+			return err
+		}
+	}
+	for _, w := range tx.writes {
+		if err := remember(w.path); err != nil {
+			return err
+		}
+	}
+
+	staged := make([]string, 0, len(tx.writes))
+	cleanup := func() {
+		for _, tmp := range staged {
+			_ = os.Remove(tmp)
+		}
+	}
+	for _, w := range tx.writes {
+		tmp, err := stage(w.path, w.data, w.perm)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		staged = append(staged, tmp)
+	}
+
+	for _, path := range tx.removes {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			cleanup()
+			restore(saved)
+			return err
+		}
+	}
+	for i, w := range tx.writes {
+		if err := rename(staged[i], w.path); err != nil {
+			cleanup()
+			restore(saved)
+			return err
+		}
+	}
+
+	if observer != nil {
+		written := make(map[string]bool, len(tx.writes))
+		for _, w := range tx.writes {
+			written[w.path] = true
+		}
+		for _, path := range tx.removes {
+			if o := saved[path]; o.existed && !written[path] {
+				observer(Change{Path: path, Before: o.data, Mode: o.perm, Existed: true, Removed: true})
+			}
+		}
+		for _, w := range tx.writes {
+			o := saved[w.path]
+			observer(Change{Path: w.path, Before: o.data, Mode: o.perm, Existed: o.existed, After: w.data})
+		}
+	}
+	return nil
+}
+
+// read returns the current state of path.
+func read(path string) (original, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return original{}, nil
+	}
+	if err != nil {
+		return original{}, err
+	}
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return original{existed: true, data: data, perm: perm}, nil
+}
+
+// restore puts every touched path back into its original state. It does
+// not notify the observer: the commit never happened.
+func restore(saved map[string]original) {
+	for path, o := range saved {
+		if !o.existed {
+			_ = os.Remove(path)
+			continue
+		}
+		tmp, err := stage(path, o.data, o.perm)
+		if err != nil {
+			continue
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			_ = os.Remove(tmp)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "a.yml")
+
+	for _, content := range []string{"first\n", "second\n"} {
+		if err := WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil || string(got) != content {
+			t.Fatalf("content = %q, %v; want %q", got, err, content)
+		}
+	}
+	assertNoTempFiles(t, filepath.Join(dir, "sub"))
+
+	t.Run("keeps the mode of an existing file", func(t *testing.T) {
+		path := filepath.Join(dir, "private.yml")
+		writeFile(t, path, "a\n")
+		if err := os.Chmod(path, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(path, []byte("b\n"), 0644); err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+		tx := NewTx()
+		tx.Write(path, []byte("c\n"), 0644)
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit error: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("mode = %o, want 600", perm)
+		}
+	})
+}
+
+func TestTx(t *testing.T) {
+	t.Run("commit applies writes and removals", func(t *testing.T) {
+		dir := t.TempDir()
+		a, b := filepath.Join(dir, "a.yml"), filepath.Join(dir, "b.yml")
+		writeFile(t, a, "a\n")
+
+		tx := NewTx()
+		tx.Remove(a)
+		tx.Write(b, []byte("draft\n"), 0644)
+		tx.Write(b, []byte("b\n"), 0644)
+		if tx.Len() != 2 {
+			t.Errorf("Len = %d, want 2", tx.Len())
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit error: %v", err)
+		}
+
+		if _, err := os.Stat(a); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed, got %v", a, err)
+		}
+		if got, _ := os.ReadFile(b); string(got) != "b\n" {
+			t.Errorf("%s = %q, want the last write", b, got)
+		}
+		assertNoTempFiles(t, dir)
+	})
+
+	t.Run("failed write leaves every file untouched", func(t *testing.T) {
+		dir := t.TempDir()
+		a := filepath.Join(dir, "a.yml")
+		writeFile(t, a, "a\n")
+		// A path below a regular file cannot be written
+		blocker := filepath.Join(dir, "blocker")
+		writeFile(t, blocker, "")
+
+		tx := NewTx()
+		tx.Write(a, []byte("changed\n"), 0644)
+		tx.Write(filepath.Join(dir, "new.yml"), []byte("new\n"), 0644)
+		tx.Write(filepath.Join(blocker, "c.yml"), []byte("c\n"), 0644)
+		if err := tx.Commit(); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if got, _ := os.ReadFile(a); string(got) != "a\n" {
+			t.Errorf("%s = %q, want it unchanged", a, got)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "new.yml")); !os.IsNotExist(err) {
+			t.Errorf("new.yml should not be created, got %v", err)
+		}
+		assertNoTempFiles(t, dir)
+	})
+
+	t.Run("failed rename is rolled back without notifying the observer", func(t *testing.T) {
+		dir := t.TempDir()
+		a, b, c := filepath.Join(dir, "a.yml"), filepath.Join(dir, "b.yml"), filepath.Join(dir, "c.yml")
+		writeFile(t, a, "a\n")
+		writeFile(t, b, "b\n")
+
+		var changes []Change
+		SetObserver(func(c Change) { changes = append(changes, c) })
+		t.Cleanup(func() { SetObserver(nil) })
+		t.Cleanup(func() { rename = os.Rename })
+		rename = func(oldpath, newpath string) error {
+			if newpath == b {
+				return errors.New("rename failed")
+			}
+			return os.Rename(oldpath, newpath)
+		}
+
+		tx := NewTx()
+		tx.Write(a, []byte("changed\n"), 0644)
+		tx.Write(c, []byte("new\n"), 0644)
+		tx.Write(b, []byte("changed\n"), 0644)
+		if err := tx.Commit(); err == nil {
+			t.Fatal("expected error")
+		}
+
+		for path, want := range map[string]string{a: "a\n", b: "b\n"} {
+			if got, _ := os.ReadFile(path); string(got) != want {
+				t.Errorf("%s = %q, want %q", path, got, want)
+			}
+		}
+		if _, err := os.Stat(c); !os.IsNotExist(err) {
+			t.Errorf("c.yml should be removed again, got %v", err)
+		}
+		if len(changes) > 0 {
+			t.Errorf("observer should not see a failed commit, got %+v", changes)
+		}
+		assertNoTempFiles(t, dir)
+	})
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, tempPattern))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
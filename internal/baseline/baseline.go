@@ -0,0 +1,255 @@
+// Package baseline freezes a set of specs (e.g. the requirements signed off
+// for a release) together with content hashes, and reports the requirements
+// and examples that changed since.
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"go.yaml.in/yaml/v3"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Baseline is a frozen set of specs.
+type Baseline struct {
+	Name      string    `yaml:"name"`
+	CreatedAt time.Time `yaml:"created_at"`
+	// Namespace and Statuses record which specs the baseline was taken
+	// from, so that Compare only reports new specs in the same scope.
+	Namespace    string   `yaml:"namespace,omitempty"`
+	Statuses     []string `yaml:"statuses,omitempty"`
+	Requirements []Entry  `yaml:"requirements"`
+}
+
+// Entry is one requirement of a baseline.
+type Entry struct {
+	ID        string `yaml:"id"`
+	Namespace string `yaml:"namespace,omitempty"`
+	Hash      string `yaml:"hash"`
+	// Examples maps example IDs to their hashes.
+	Examples map[string]string `yaml:"examples,omitempty"`
+	Spec     spec.Spec         `yaml:"spec"`
+}
+
+// ValidateName checks that name can be used as a baseline file name.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return apperrors.New("baseline.ValidateName", apperrors.ErrInvalidInput,
+			fmt.Sprintf("invalid baseline name %q (use letters, digits, '.', '_' and '-', e.g. v1.0)", name))
+	}
+	return nil
+}
+
+// Path returns the file of baseline name in dir.
+func Path(dir, name string) string {
+	return filepath.Join(dir, name+".yml")
+}
+
+// New freezes specs as baseline name. The specs are stored normalized (with
+// every rule, example and question ID filled in).
+func New(name string, specs []*spec.Spec) *Baseline {
+	b := &Baseline{Name: name, CreatedAt: time.Now().UTC(), Requirements: make([]Entry, 0, len(specs))}
+	for _, s := range specs {
+		n := normalized(s)
+		e := Entry{ID: n.ID, Namespace: n.Namespace, Hash: Hash(n), Spec: *n}
+		e.Spec.Version = 0
+		for _, ex := range n.AllExamples() {
+			if e.Examples == nil {
+				e.Examples = make(map[string]string)
+			}
+			e.Examples[ex.ID] = ExampleHash(ex)
+		}
+		b.Requirements = append(b.Requirements, e)
+	}
+	return b
+}
+
+// Hash returns the content hash of a requirement (see spec.ContentHash):
+// moving an approved requirement through its lifecycle or re-importing it
+// unchanged is not a change of its content.
+func Hash(s *spec.Spec) string {
+	return spec.ContentHash(s)
+}
+
+// ExampleHash returns the content hash of an example.
+func ExampleHash(e spec.Example) string {
+	data, _ := json.Marshal([]string{e.Given, e.When, e.Then})
+	return sum(data)
+}
+
+func sum(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func normalized(s *spec.Spec) *spec.Spec {
+	c := s.Clone()
+	c.Normalize()
+	return c
+}
+
+// Save writes b to dir. An existing baseline of the same name is only
+// replaced with overwrite.
+func Save(dir string, b *Baseline, overwrite bool) error {
+	path := Path(dir, b.Name)
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return apperrors.New("baseline.Save", apperrors.ErrConflict,
+			fmt.Sprintf("baseline %s already exists (%s); use --force to replace it", b.Name, path))
+	}
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return apperrors.Wrap("baseline.Save", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0644); err != nil {
+		return apperrors.Wrap("baseline.Save", err)
+	}
+	return nil
+}
+
+// Load reads baseline name from dir.
+func Load(dir, name string) (*Baseline, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	path := Path(dir, name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, apperrors.New("baseline.Load", apperrors.ErrNotFound, fmt.Sprintf("baseline not found: %s", name))
+	}
+	if err != nil {
+		return nil, apperrors.Wrap("baseline.Load", err)
+	}
+	var b Baseline
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return nil, apperrors.Wrapf("baseline.Load", err, "%s", path)
+	}
+	return &b, nil
+}
+
+// List returns the baselines in dir, oldest first.
+func List(dir string) ([]*Baseline, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap("baseline.List", err)
+	}
+	var out []*Baseline
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".yml")
+		if !ok || e.IsDir() || ValidateName(name) != nil {
+			continue
+		}
+		b, err := Load(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Report lists the requirements that changed since a baseline.
+type Report struct {
+	Baseline  string    `json:"baseline"`
+	CreatedAt time.Time `json:"createdAt"`
+	Added     []Item    `json:"added"`
+	Removed   []Item    `json:"removed"`
+	Changed   []Item    `json:"changed"`
+}
+
+// Item is an added, removed or changed requirement. For changed
+// requirements, the example lists name the examples that differ.
+type Item struct {
+	ID              string   `json:"id"`
+	Title           string   `json:"title"`
+	ExamplesAdded   []string `json:"examplesAdded,omitempty"`
+	ExamplesRemoved []string `json:"examplesRemoved,omitempty"`
+	ExamplesChanged []string `json:"examplesChanged,omitempty"`
+}
+
+// Examples returns the IDs of every added, removed or changed example.
+func (it Item) Examples() []string {
+	out := slices.Concat(it.ExamplesAdded, it.ExamplesChanged, it.ExamplesRemoved)
+	sort.Strings(out)
+	return out
+}
+
+// Empty reports whether nothing changed.
+func (r Report) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// Compare compares the current specs with b. Requirements are matched by ID;
+// a spec not in b is reported as added when inScope accepts it.
+func Compare(b *Baseline, specs []*spec.Spec, inScope func(*spec.Spec) bool) Report {
+	r := Report{Baseline: b.Name, CreatedAt: b.CreatedAt, Added: []Item{}, Removed: []Item{}, Changed: []Item{}}
+	current := make(map[string]*spec.Spec, len(specs))
+	for _, s := range specs {
+		current[s.ID] = s
+	}
+
+	frozen := make(map[string]bool, len(b.Requirements))
+	for _, e := range b.Requirements {
+		frozen[e.ID] = true
+		s, ok := current[e.ID]
+		if !ok {
+			r.Removed = append(r.Removed, Item{ID: e.ID, Title: e.Spec.Title})
+			continue
+		}
+		if Hash(s) == e.Hash {
+			continue
+		}
+		it := Item{ID: s.ID, Title: s.Title}
+		now := make(map[string]bool)
+		for _, ex := range normalized(s).AllExamples() {
+			now[ex.ID] = true
+			was, ok := e.Examples[ex.ID]
+			switch {
+			case !ok:
+				it.ExamplesAdded = append(it.ExamplesAdded, ex.ID)
+			case was != ExampleHash(ex):
+				it.ExamplesChanged = append(it.ExamplesChanged, ex.ID)
+			}
+		}
+		for _, id := range sortedKeys(e.Examples) {
+			if !now[id] {
+				it.ExamplesRemoved = append(it.ExamplesRemoved, id)
+			}
+		}
+		r.Changed = append(r.Changed, it)
+	}
+
+	for _, s := range specs {
+		if !frozen[s.ID] && (inScope == nil || inScope(s)) {
+			r.Added = append(r.Added, Item{ID: s.ID, Title: s.Title})
+		}
+	}
+	return r
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,101 @@
+package baseline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func testSpecs() []*spec.Spec {
+	return []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Status: "ready", Examples: []spec.Example{
+			{Given: "a user", When: "logging in", Then: "a token"},
+			{Given: "a wrong password", When: "logging in", Then: "401"},
+		}},
+		{ID: "REQ-002", Title: "Logout", Status: "ready"},
+		{ID: "REQ-003", Title: "Reset", Status: "ready"},
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	b := New("v1.0", testSpecs())
+	if err := Save(dir, b, false); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if err := Save(dir, b, false); !apperrors.IsConflict(err) {
+		t.Errorf("second Save error = %v, want conflict", err)
+	}
+	if err := Save(dir, b, true); err != nil {
+		t.Errorf("Save with overwrite error: %v", err)
+	}
+
+	got, err := Load(dir, "v1.0")
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(got.Requirements) != 3 || got.Requirements[0].Spec.Examples[1].ID != "E2" || len(got.Requirements[0].Examples) != 2 {
+		t.Errorf("unexpected baseline: %+v", got.Requirements[0])
+	}
+	if got.Requirements[0].Hash != Hash(testSpecs()[0]) {
+		t.Error("stored hash differs from the hash of the same spec")
+	}
+
+	if _, err := Load(dir, "v2.0"); !apperrors.IsNotFound(err) {
+		t.Errorf("Load of a missing baseline = %v, want not found", err)
+	}
+	list, err := List(dir)
+	if err != nil || len(list) != 1 || list[0].Name != "v1.0" {
+		t.Errorf("List = %v, %v", list, err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"v1.0", "release_2026-10", "RC1"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".hidden", "../v1", "a/b", "v 1"} {
+		if err := ValidateName(name); !apperrors.IsInvalidInput(err) {
+			t.Errorf("ValidateName(%q) = %v, want invalid input", name, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	b := New("v1.0", testSpecs())
+
+	current := testSpecs()
+	// Saved specs carry the IDs the baseline filled in
+	current[0].Examples[0].ID, current[0].Examples[1].ID = "E1", "E2"
+	current[0].Status = "implemented" // status alone is not a change
+	current[0].Examples[1].Then = "401 and a retry-after header"
+	current[0].Examples = append(current[0].Examples, spec.Example{ID: "E3", Given: "g", When: "w", Then: "t"})
+	current[1].Status = "implemented"
+	current = append(current[:2],
+		&spec.Spec{ID: "REQ-004", Title: "Signup"},
+		&spec.Spec{ID: "REQ-005", Title: "Out of scope"},
+	)
+
+	r := Compare(b, current, func(s *spec.Spec) bool { return s.ID != "REQ-005" })
+
+	if len(r.Changed) != 1 || r.Changed[0].ID != "REQ-001" {
+		t.Fatalf("Changed = %+v, want only REQ-001", r.Changed)
+	}
+	if got := strings.Join(r.Changed[0].Examples(), ","); got != "E2,E3" {
+		t.Errorf("changed examples = %s, want E2,E3", got)
+	}
+	if len(r.Added) != 1 || r.Added[0].ID != "REQ-004" {
+		t.Errorf("Added = %+v, want only REQ-004", r.Added)
+	}
+	if len(r.Removed) != 1 || r.Removed[0].ID != "REQ-003" || r.Removed[0].Title != "Reset" {
+		t.Errorf("Removed = %+v, want REQ-003", r.Removed)
+	}
+
+	if r := Compare(b, testSpecs(), nil); !r.Empty() {
+		t.Errorf("unchanged specs reported: %+v", r)
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+)
+
+// DefaultGitignorePath keeps the local state of the workspace (index, lock
+// and journal) out of version control.
+const DefaultGitignorePath = ".tdd/.gitignore"
+
+// GitignoreEntries returns the lines DefaultGitignorePath must contain.
+func GitignoreEntries() []string {
+	return []string{
+		filepath.Base(DefaultIndexPath),
+		filepath.Base(DefaultLockPath),
+		filepath.Base(DefaultJournalDir) + "/",
+	}
+}
+
+// EnsureGitignore adds the missing GitignoreEntries to path, creating the
+// file if needed. Other lines are kept. Nothing is done when the directory
+// of path does not exist, so that commands run outside a workspace do not
+// create one.
+func EnsureGitignore(path string) error {
+	if _, err := os.Stat(filepath.Dir(path)); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return apperrors.Wrap("config.EnsureGitignore", err)
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, entry := range GitignoreEntries() {
+		if !present[entry] {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += strings.Join(missing, "\n") + "\n"
+	if err := atomicfile.WriteFile(path, []byte(content), 0644); err != nil {
+		return apperrors.Wrap("config.EnsureGitignore", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnsureGitignore(t *testing.T) {
+	t.Run("creates the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".gitignore")
+		if err := EnsureGitignore(path); err != nil {
+			t.Fatalf("EnsureGitignore error: %v", err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "index.json\nlock\njournal/\n" {
+			t.Errorf(".gitignore = %q", data)
+		}
+	})
+
+	t.Run("appends missing entries", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".gitignore")
+		if err := os.WriteFile(path, []byte("# local\nindex.json\nreports/"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for range 2 {
+			if err := EnsureGitignore(path); err != nil {
+				t.Fatalf("EnsureGitignore error: %v", err)
+			}
+		}
+		if data, _ := os.ReadFile(path); string(data) != "# local\nindex.json\nreports/\nlock\njournal/\n" {
+			t.Errorf(".gitignore = %q", data)
+		}
+	})
+
+	t.Run("outside a workspace", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), ".tdd")
+		if err := EnsureGitignore(filepath.Join(dir, ".gitignore")); err != nil {
+			t.Fatalf("EnsureGitignore error: %v", err)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("directory should not be created, got %v", err)
+		}
+	})
+}
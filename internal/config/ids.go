@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var idPrefixPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// IDConfig configures the requirement ID scheme, e.g. AUTH-012 and BILL-003.
+// The zero value means REQ-### (prefix REQ, three digits).
+type IDConfig struct {
+	// Prefixes lists the allowed ID prefixes. The first one is used when a
+	// command creates an ID without an explicit prefix (default [REQ]).
+	Prefixes []string `yaml:"prefixes,omitempty"`
+	// Padding is the minimum number of digits of new IDs (default 3).
+	Padding int `yaml:"padding,omitempty"`
+	// Numbering is per-prefix (AUTH-001, BILL-001) or global
+	// (AUTH-001, BILL-002). Default per-prefix.
+	Numbering string `yaml:"numbering,omitempty"`
+	// Provisional makes `req add` create provisional IDs that are replaced
+	// by `req finalize`: off (default), branch or ulid.
+	Provisional string `yaml:"provisional,omitempty"`
+}
+
+func (c IDConfig) validate(v *Validator) {
+	seen := make(map[string]bool, len(c.Prefixes))
+	for _, p := range c.Prefixes {
+		if !idPrefixPattern.MatchString(p) {
+			v.AddError("ids.prefixes", fmt.Sprintf("%q must start with a letter and contain only letters, digits and _", p))
+			continue
+		}
+		if seen[p] {
+			v.AddError("ids.prefixes", fmt.Sprintf("duplicate prefix %q", p))
+		}
+		seen[p] = true
+	}
+	if c.Padding < 0 {
+		v.AddError("ids.padding", fmt.Sprintf("must not be negative (got %d)", c.Padding))
+	}
+	switch c.Numbering {
+	case "", "per-prefix", "global":
+	default:
+		v.AddError("ids.numbering", "must be per-prefix or global")
+	}
+	switch c.Provisional {
+	case "", "off", "branch", "ulid":
+	default:
+		v.AddError("ids.provisional", "must be off, branch or ulid")
+	}
+}
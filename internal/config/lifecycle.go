@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Default lifecycle statuses.
+const (
+	StatusDraft       = "draft"
+	StatusReady       = "ready"
+	StatusInProgress  = "in-progress"
+	StatusImplemented = "implemented"
+	StatusDeprecated  = "deprecated"
+)
+
+// LifecycleConfig is the requirement status state machine.
+// Transitions maps each status to the statuses it may move to; every status
+// must appear as a key. Excluded statuses are left out of coverage reports.
+type LifecycleConfig struct {
+	Initial     string              `yaml:"initial,omitempty"`
+	Transitions map[string][]string `yaml:"transitions,omitempty"`
+	Excluded    []string            `yaml:"excluded,omitempty"`
+}
+
+// DefaultLifecycle returns the built-in draft → ready → in-progress →
+// implemented flow, where any status can be deprecated.
+func DefaultLifecycle() LifecycleConfig {
+	return LifecycleConfig{
+		Initial: StatusDraft,
+		Transitions: map[string][]string{
+			StatusDraft:       {StatusReady, StatusDeprecated},
+			StatusReady:       {StatusInProgress, StatusDraft, StatusDeprecated},
+			StatusInProgress:  {StatusImplemented, StatusReady, StatusDeprecated},
+			StatusImplemented: {StatusInProgress, StatusDeprecated},
+			StatusDeprecated:  {StatusDraft},
+		},
+		Excluded: []string{StatusDeprecated},
+	}
+}
+
+// EffectiveLifecycle returns the configured lifecycle, or the default when
+// none is set.
+func (c SpecConfig) EffectiveLifecycle() LifecycleConfig {
+	if len(c.Lifecycle.Transitions) == 0 {
+		return DefaultLifecycle()
+	}
+	return c.Lifecycle
+}
+
+// States returns all statuses in sorted order.
+func (l LifecycleConfig) States() []string {
+	states := make([]string, 0, len(l.Transitions))
+	for s := range l.Transitions {
+		states = append(states, s)
+	}
+	sort.Strings(states)
+	return states
+}
+
+// IsState reports whether status is defined.
+func (l LifecycleConfig) IsState(status string) bool {
+	_, ok := l.Transitions[status]
+	return ok
+}
+
+// Effective returns status, or the initial status for specs without one.
+func (l LifecycleConfig) Effective(status string) string {
+	if strings.TrimSpace(status) == "" {
+		return l.Initial
+	}
+	return status
+}
+
+// CanTransition reports whether a spec may move from one status to another.
+func (l LifecycleConfig) CanTransition(from, to string) bool {
+	for _, next := range l.Transitions[l.Effective(from)] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExcluded reports whether specs in status are excluded from coverage.
+func (l LifecycleConfig) IsExcluded(status string) bool {
+	status = l.Effective(status)
+	for _, s := range l.Excluded {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (l LifecycleConfig) validate(v *Validator) {
+	if len(l.Transitions) == 0 {
+		if l.Initial != "" || len(l.Excluded) > 0 {
+			v.AddError("lifecycle.transitions", "is required when lifecycle is set")
+		}
+		return
+	}
+
+	if strings.TrimSpace(l.Initial) == "" {
+		v.AddError("lifecycle.initial", "is required")
+	} else if !l.IsState(l.Initial) {
+		v.AddError("lifecycle.initial", fmt.Sprintf("unknown status %q", l.Initial))
+	}
+	for _, from := range l.States() {
+		for _, to := range l.Transitions[from] {
+			if !l.IsState(to) {
+				v.AddError("lifecycle.transitions."+from, fmt.Sprintf("unknown status %q (declare it as a key)", to))
+			}
+		}
+	}
+	for _, s := range l.Excluded {
+		if !l.IsState(s) {
+			v.AddError("lifecycle.excluded", fmt.Sprintf("unknown status %q", s))
+		}
+	}
+}
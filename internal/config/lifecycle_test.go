@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultLifecycle(t *testing.T) {
+	lc := DefaultSpecConfig().EffectiveLifecycle()
+
+	if lc.Effective("") != StatusDraft {
+		t.Errorf("Effective(\"\") = %q, want draft", lc.Effective(""))
+	}
+	if !lc.CanTransition("", StatusReady) {
+		t.Error("expected draft -> ready to be allowed for specs without status")
+	}
+	if lc.CanTransition(StatusDraft, StatusImplemented) {
+		t.Error("expected draft -> implemented to be rejected")
+	}
+	if !lc.IsExcluded(StatusDeprecated) || lc.IsExcluded(StatusReady) {
+		t.Error("expected only deprecated to be excluded")
+	}
+
+	v := NewValidator()
+	lc.validate(v)
+	if v.HasErrors() {
+		t.Errorf("default lifecycle should be valid: %v", v.Error())
+	}
+}
+
+func TestLifecycleFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	data := `specDir: .tdd/specs
+testDir: tests
+runner: vitest
+fileNamePattern: "req-{{id}}-{{slug}}.test.ts"
+lifecycle:
+  initial: proposed
+  transitions:
+    proposed: [accepted]
+    accepted: [done]
+    done: []
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	cfg, _, err := LoadSpecConfig(path)
+	if err != nil {
+		t.Fatalf("LoadSpecConfig error: %v", err)
+	}
+	lc := cfg.EffectiveLifecycle()
+	if got := lc.States(); len(got) != 3 {
+		t.Errorf("States = %v, want only the configured statuses", got)
+	}
+	if lc.IsState(StatusDraft) || !lc.CanTransition("", "accepted") {
+		t.Errorf("custom lifecycle not applied: %+v", lc)
+	}
+
+	t.Run("unknown statuses are rejected", func(t *testing.T) {
+		bad := DefaultSpecConfig()
+		bad.Lifecycle = LifecycleConfig{
+			Initial:     "new",
+			Transitions: map[string][]string{"open": {"closed"}},
+			Excluded:    []string{"gone"},
+		}
+		err := bad.Validate()
+		if err == nil {
+			t.Fatal("expected validation error")
+		}
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) || len(verrs) != 3 {
+			t.Errorf("expected 3 validation errors, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package config
+
+import "fmt"
+
+// LintConfig configures `spec-tdd lint`.
+type LintConfig struct {
+	// Disable lists rule IDs that are not run.
+	Disable []string `yaml:"disable,omitempty"`
+	// Severity overrides the default severity per rule ID (error, warning, info).
+	Severity map[string]string `yaml:"severity,omitempty"`
+	// MaxTitleLength is the title-length limit in characters (default 80).
+	MaxTitleLength int `yaml:"maxTitleLength,omitempty"`
+	// VagueWords are added to the built-in vague word list.
+	VagueWords []string `yaml:"vagueWords,omitempty"`
+}
+
+func (l LintConfig) validate(v *Validator) {
+	for _, id := range sortedKeys(l.Severity) {
+		switch l.Severity[id] {
+		case "error", "warning", "info":
+		default:
+			v.AddError("lint.severity."+id, "must be error, warning or info")
+		}
+	}
+	if l.MaxTitleLength < 0 {
+		v.AddError("lint.maxTitleLength", fmt.Sprintf("must not be negative (got %d)", l.MaxTitleLength))
+	}
+}
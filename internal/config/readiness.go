@@ -0,0 +1,88 @@
+package config
+
+import "fmt"
+
+// Readiness rule IDs.
+const (
+	ReadyHasExamples      = "has-examples"
+	ReadyNoOpenQuestions  = "no-open-questions"
+	ReadyDependsExist     = "depends-exist"
+	ReadyNoPlaceholders   = "no-placeholders"
+	ReadyNoStaleApprovals = "no-stale-approvals"
+)
+
+// ReadyRules lists all readiness rules in evaluation order.
+var ReadyRules = []string{ReadyHasExamples, ReadyNoOpenQuestions, ReadyDependsExist, ReadyNoPlaceholders, ReadyNoStaleApprovals}
+
+// Scaffold enforcement modes for readiness.
+const (
+	EnforceOff   = "off"
+	EnforceWarn  = "warn"
+	EnforceError = "error"
+)
+
+// ReadinessConfig is the Definition of Ready.
+// Empty fields fall back to the defaults; use EffectiveReadiness.
+type ReadinessConfig struct {
+	// Rules are the checks a spec must pass to be ready.
+	Rules []string `yaml:"rules,omitempty"`
+	// Placeholders are texts that mark unfinished content (case-sensitive).
+	Placeholders []string `yaml:"placeholders,omitempty"`
+	// Scaffold controls whether scaffold checks readiness: off, warn or error.
+	Scaffold string `yaml:"scaffold,omitempty"`
+}
+
+// DefaultReadiness enables every rule and leaves scaffold unchanged.
+func DefaultReadiness() ReadinessConfig {
+	return ReadinessConfig{
+		Rules:        append([]string(nil), ReadyRules...),
+		Placeholders: []string{"TODO", "TBD", "FIXME", "XXX", "???"},
+		Scaffold:     EnforceOff,
+	}
+}
+
+// EffectiveReadiness returns the readiness config with defaults applied.
+func (c SpecConfig) EffectiveReadiness() ReadinessConfig {
+	r := c.Readiness
+	def := DefaultReadiness()
+	if r.Rules == nil {
+		r.Rules = def.Rules
+	}
+	if r.Placeholders == nil {
+		r.Placeholders = def.Placeholders
+	}
+	if r.Scaffold == "" {
+		r.Scaffold = def.Scaffold
+	}
+	return r
+}
+
+// Enabled reports whether rule is part of the Definition of Ready.
+func (r ReadinessConfig) Enabled(rule string) bool {
+	for _, id := range r.Rules {
+		if id == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnforceMode reports whether mode is a valid scaffold enforcement mode.
+func IsEnforceMode(mode string) bool {
+	return mode == EnforceOff || mode == EnforceWarn || mode == EnforceError
+}
+
+func (r ReadinessConfig) validate(v *Validator) {
+	known := make(map[string]bool, len(ReadyRules))
+	for _, id := range ReadyRules {
+		known[id] = true
+	}
+	for _, id := range r.Rules {
+		if !known[id] {
+			v.AddError("readiness.rules", fmt.Sprintf("unknown rule %q", id))
+		}
+	}
+	if r.Scaffold != "" && !IsEnforceMode(r.Scaffold) {
+		v.AddError("readiness.scaffold", "must be off, warn or error")
+	}
+}
@@ -3,26 +3,74 @@ package config
 import (
 	"errors"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/migrate"
 	"go.yaml.in/yaml/v3"
 )
 
 const DefaultSpecConfigPath = ".tdd/config.yml"
 
+// DefaultIndexPath is the spec index cache. It is derived data and belongs
+// in .gitignore.
+const DefaultIndexPath = ".tdd/index.json"
+
+// DefaultLockPath is the advisory lock taken by commands that modify the
+// workspace.
+const DefaultLockPath = ".tdd/lock"
+
+// DefaultJournalDir holds the journal of workspace changes used by `log` and
+// `undo`. It is local history and belongs in .gitignore.
+const DefaultJournalDir = ".tdd/journal"
+
+// DefaultBaselineDir holds the baselines created by `baseline create`. They
+// record sign-offs and are meant to be committed.
+const DefaultBaselineDir = ".tdd/baselines"
+
 // SpecConfig represents spec-driven TDD configuration stored in .tdd/config.yml
 // Fields are flat to match the YAML DSL structure.
 type SpecConfig struct {
+	// Version is the format version of the file (see ConfigVersion).
+	Version int `yaml:"version,omitempty"`
+
 	SpecDir         string `yaml:"specDir"`
 	TestDir         string `yaml:"testDir"`
 	Runner          string `yaml:"runner"`
 	FileNamePattern string `yaml:"fileNamePattern"`
+
+	// IDs configures requirement ID prefixes, padding and numbering.
+	IDs IDConfig `yaml:"ids,omitempty"`
+
+	// CSVColumns maps spec fields (id, title, description, tags, depends,
+	// exampleId, given, when, then) to column headers for CSV/TSV import/export.
+	CSVColumns map[string]string `yaml:"csvColumns,omitempty"`
+
+	// Lifecycle overrides the requirement status state machine.
+	// Use EffectiveLifecycle to read it with defaults applied.
+	Lifecycle LifecycleConfig `yaml:"lifecycle,omitempty"`
+
+	// Readiness is the Definition of Ready checked by `ready` and `scaffold`.
+	// Use EffectiveReadiness to read it with defaults applied.
+	Readiness ReadinessConfig `yaml:"readiness,omitempty"`
+
+	// Lint configures rule selection and severities for `lint`.
+	Lint LintConfig `yaml:"lint,omitempty"`
 }
 
+// ConfigVersion is the .tdd/config.yml format written by this version of
+// spec-tdd. LoadSpecConfig refuses files with a newer version.
+const ConfigVersion = 1
+
+// Migrations upgrade .tdd/config.yml to ConfigVersion, oldest first.
+var Migrations []migrate.Step
+
 // DefaultSpecConfig returns the default spec configuration.
 func DefaultSpecConfig() SpecConfig {
 	return SpecConfig{
+		Version:         ConfigVersion,
 		SpecDir:         ".tdd/specs",
 		TestDir:         "tests",
 		Runner:          "vitest",
@@ -45,6 +93,9 @@ func LoadSpecConfig(path string) (SpecConfig, bool, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return SpecConfig{}, true, apperrors.Wrap("config.LoadSpecConfig", err)
 	}
+	if err := migrate.CheckVersion(cfg.Version, ConfigVersion); err != nil {
+		return SpecConfig{}, true, apperrors.Wrapf("config.LoadSpecConfig", err, "%s", path)
+	}
 
 	if err := cfg.Validate(); err != nil {
 		return SpecConfig{}, true, err
@@ -64,7 +115,7 @@ func SaveSpecConfig(path string, cfg SpecConfig) error {
 		return apperrors.Wrap("config.SaveSpecConfig", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := atomicfile.WriteFile(path, data, 0644); err != nil {
 		return apperrors.Wrap("config.SaveSpecConfig", err)
 	}
 
@@ -92,9 +143,29 @@ func (c SpecConfig) Validate() error {
 		v.AddError("fileNamePattern", "must include {{id}}")
 	}
 
+	for _, field := range sortedKeys(c.CSVColumns) {
+		if strings.TrimSpace(c.CSVColumns[field]) == "" {
+			v.AddError("csvColumns."+field, "must not be empty")
+		}
+	}
+
+	c.IDs.validate(v)
+	c.Lifecycle.validate(v)
+	c.Readiness.validate(v)
+	c.Lint.validate(v)
+
 	if v.HasErrors() {
 		return apperrors.Wrap("config.ValidateSpecConfig", v.Error())
 	}
 
 	return nil
 }
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
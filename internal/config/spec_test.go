@@ -24,6 +24,42 @@ func TestSpecConfigValidate(t *testing.T) {
 	if err := invalid.Validate(); err == nil {
 		t.Error("expected error for invalid runner")
 	}
+
+	emptyColumn := valid
+	emptyColumn.CSVColumns = map[string]string{"id": " "}
+	if err := emptyColumn.Validate(); err == nil {
+		t.Error("expected error for empty csv column header")
+	}
+
+	unknownRule := valid
+	unknownRule.Readiness = ReadinessConfig{Rules: []string{"has-tests"}}
+	if err := unknownRule.Validate(); err == nil {
+		t.Error("expected error for unknown readiness rule")
+	}
+
+	badMode := valid
+	badMode.Readiness = ReadinessConfig{Scaffold: "strict"}
+	if err := badMode.Validate(); err == nil {
+		t.Error("expected error for invalid readiness scaffold mode")
+	}
+
+	badPrefix := valid
+	badPrefix.IDs = IDConfig{Prefixes: []string{"AUTH", "BI-LL"}}
+	if err := badPrefix.Validate(); err == nil {
+		t.Error("expected error for prefix containing a hyphen")
+	}
+
+	badNumbering := valid
+	badNumbering.IDs = IDConfig{Prefixes: []string{"AUTH"}, Numbering: "shared"}
+	if err := badNumbering.Validate(); err == nil {
+		t.Error("expected error for invalid ids numbering")
+	}
+
+	badProvisional := valid
+	badProvisional.IDs = IDConfig{Provisional: "uuid"}
+	if err := badProvisional.Validate(); err == nil {
+		t.Error("expected error for invalid ids provisional mode")
+	}
 }
 
 func TestLoadSpecConfigMissingFile(t *testing.T) {
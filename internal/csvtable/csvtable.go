@@ -0,0 +1,349 @@
+package csvtable
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Field names that can be mapped to table columns.
+const (
+	FieldID          = "id"
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldTags        = "tags"
+	FieldDepends     = "depends"
+	FieldExampleID   = "exampleId"
+	FieldGiven       = "given"
+	FieldWhen        = "when"
+	FieldThen        = "then"
+)
+
+// Fields lists all mappable fields in default column order.
+var Fields = []string{
+	FieldID, FieldTitle, FieldDescription, FieldTags, FieldDepends,
+	FieldExampleID, FieldGiven, FieldWhen, FieldThen,
+}
+
+// listSeparator joins multi-value cells (tags, depends) on export.
+const listSeparator = ";"
+
+// Columns maps field names to column header names.
+type Columns map[string]string
+
+// DefaultColumns returns a mapping where each header equals its field name.
+func DefaultColumns() Columns {
+	cols := make(Columns, len(Fields))
+	for _, f := range Fields {
+		cols[f] = f
+	}
+	return cols
+}
+
+// NewColumns returns the default mapping overridden by overrides.
+// Unknown field names and duplicate header names are rejected.
+func NewColumns(overrides map[string]string) (Columns, error) {
+	cols := DefaultColumns()
+	for field, header := range overrides {
+		if _, ok := cols[field]; !ok {
+			return nil, apperrors.New("csvtable.NewColumns", apperrors.ErrInvalidInput,
+				fmt.Sprintf("unknown column field %q (allowed: %s)", field, strings.Join(Fields, ", ")))
+		}
+		if strings.TrimSpace(header) == "" {
+			return nil, apperrors.New("csvtable.NewColumns", apperrors.ErrInvalidInput,
+				fmt.Sprintf("column for %q must not be empty", field))
+		}
+		cols[field] = strings.TrimSpace(header)
+	}
+
+	seen := make(map[string]string, len(cols))
+	for _, f := range Fields {
+		h := cols[f]
+		if prev, dup := seen[h]; dup {
+			return nil, apperrors.New("csvtable.NewColumns", apperrors.ErrInvalidInput,
+				fmt.Sprintf("fields %q and %q map to the same column %q", prev, f, h))
+		}
+		seen[h] = f
+	}
+	return cols, nil
+}
+
+// RowError is a problem found in a specific table row.
+// Row numbers are 1-based and count the header as row 1, as spreadsheets do.
+type RowError struct {
+	Row     int
+	Message string
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+}
+
+// RowErrors is a collection of row errors ordered by row.
+type RowErrors []RowError
+
+func (e RowErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var sb strings.Builder
+	sb.WriteString("row errors:\n")
+	for _, err := range e {
+		sb.WriteString("  - ")
+		sb.WriteString(err.Error())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Record is an imported spec together with the row it starts on.
+type Record struct {
+	Spec *spec.Spec
+	Row  int
+}
+
+// Read parses a requirements table. Each row holds at most one example.
+// A row with an ID, or with a title but no ID, starts a new requirement;
+// a row without both continues the previous requirement with another example.
+// Requirements without an ID get an empty Spec.ID for the caller to assign.
+//
+// All row problems are collected and returned together as RowErrors,
+// alongside the records that were parsed.
+func Read(r io.Reader, cols Columns, delim rune) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = delim
+	cr.FieldsPerRecord = -1
+	// Leading-space trimming would swallow empty cells in TSV
+	cr.TrimLeadingSpace = delim != '\t'
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, apperrors.New("csvtable.Read", apperrors.ErrInvalidInput, "table is empty")
+		}
+		return nil, apperrors.Wrap("csvtable.Read", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+	pos := make(map[string]int, len(cols))
+	for field, h := range cols {
+		if i, ok := index[h]; ok {
+			pos[field] = i
+		}
+	}
+	if _, ok := pos[FieldTitle]; !ok {
+		return nil, apperrors.New("csvtable.Read", apperrors.ErrInvalidInput,
+			fmt.Sprintf("missing required column %q", cols[FieldTitle]))
+	}
+
+	var records []Record
+	var rowErrs RowErrors
+	var current *spec.Spec
+	firstRow := make(map[string]int)
+	row := 1
+
+	for {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row++
+		if err != nil {
+			return nil, apperrors.Wrap("csvtable.Read", RowError{Row: row, Message: err.Error()})
+		}
+
+		cell := func(field string) string {
+			i, ok := pos[field]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+
+		if isBlank(fields) {
+			continue
+		}
+
+		id, title := cell(FieldID), cell(FieldTitle)
+		startsNew := current == nil || (id != "" && id != current.ID) || (id == "" && title != "")
+		if startsNew {
+			current = &spec.Spec{ID: id, Title: title}
+			records = append(records, Record{Spec: current, Row: row})
+			if id != "" {
+				if prev, dup := firstRow[id]; dup {
+					rowErrs = append(rowErrs, RowError{Row: row,
+						Message: fmt.Sprintf("%s already defined at row %d; rows of one requirement must be adjacent", id, prev)})
+				} else {
+					firstRow[id] = row
+				}
+			}
+		}
+
+		s := current
+		if s.Title == "" {
+			s.Title = title
+		}
+		if s.Description == "" {
+			s.Description = cell(FieldDescription)
+		}
+		s.Tags = appendUnique(s.Tags, splitList(cell(FieldTags)))
+		s.Depends = appendUnique(s.Depends, splitList(cell(FieldDepends)))
+
+		ex := spec.Example{
+			ID:    cell(FieldExampleID),
+			Given: cell(FieldGiven),
+			When:  cell(FieldWhen),
+			Then:  cell(FieldThen),
+		}
+		if ex.ID == "" && ex.Given == "" && ex.When == "" && ex.Then == "" {
+			continue
+		}
+
+		// Validate the example on its own so the error points at this row.
+		// ID and title problems are reported once for the requirement below.
+		ids := spec.CurrentIDScheme()
+		probe := spec.Spec{ID: ids.Format(ids.DefaultPrefix(), 1), Title: "example", Examples: []spec.Example{ex}}
+		if err := probe.Validate(); err != nil {
+			rowErrs = append(rowErrs, RowError{Row: row, Message: "example must include given/when/then"})
+			continue
+		}
+		if ex.ID != "" {
+			for _, other := range s.Examples {
+				if other.ID == ex.ID {
+					rowErrs = append(rowErrs, RowError{Row: row,
+						Message: fmt.Sprintf("duplicate example id %s in %s", ex.ID, s.ID)})
+				}
+			}
+		}
+		s.Examples = append(s.Examples, ex)
+	}
+
+	for _, rec := range records {
+		if rec.Spec.ID == "" {
+			if strings.TrimSpace(rec.Spec.Title) == "" {
+				rowErrs = append(rowErrs, RowError{Row: rec.Row, Message: "title is required"})
+			}
+			continue
+		}
+		if err := rec.Spec.Validate(); err != nil {
+			rowErrs = append(rowErrs, RowError{Row: rec.Row, Message: apperrors.Message(err)})
+		}
+	}
+
+	if len(rowErrs) > 0 {
+		sort.SliceStable(rowErrs, func(i, j int) bool {
+			return rowErrs[i].Row < rowErrs[j].Row
+		})
+		return records, apperrors.Wrap("csvtable.Read", rowErrs)
+	}
+	return records, nil
+}
+
+// Write renders specs as a table with one row per example. Requirement
+// columns are repeated on every row so the table can be filtered and sorted.
+// A spec without examples produces a single row with empty example columns.
+func Write(w io.Writer, specs []*spec.Spec, cols Columns, delim rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = delim
+
+	header := make([]string, len(Fields))
+	for i, f := range Fields {
+		header[i] = cols[f]
+	}
+	if err := cw.Write(header); err != nil {
+		return apperrors.Wrap("csvtable.Write", err)
+	}
+
+	for _, s := range specs {
+		base := map[string]string{
+			FieldID:          s.ID,
+			FieldTitle:       s.Title,
+			FieldDescription: s.Description,
+			FieldTags:        strings.Join(s.Tags, listSeparator),
+			FieldDepends:     strings.Join(s.Depends, listSeparator),
+		}
+		examples := s.Examples
+		if len(examples) == 0 {
+			examples = []spec.Example{{}}
+		}
+		for _, ex := range examples {
+			values := map[string]string{
+				FieldExampleID: ex.ID,
+				FieldGiven:     ex.Given,
+				FieldWhen:      ex.When,
+				FieldThen:      ex.Then,
+			}
+			for k, v := range base {
+				values[k] = v
+			}
+			row := make([]string, len(Fields))
+			for i, f := range Fields {
+				row[i] = values[f]
+			}
+			if err := cw.Write(row); err != nil {
+				return apperrors.Wrap("csvtable.Write", err)
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return apperrors.Wrap("csvtable.Write", err)
+	}
+	return nil
+}
+
+// DelimiterFor returns the delimiter implied by a file name (.tsv → tab).
+func DelimiterFor(path string) rune {
+	if strings.HasSuffix(strings.ToLower(path), ".tsv") {
+		return '\t'
+	}
+	return ','
+}
+
+func splitList(v string) []string {
+	parts := strings.FieldsFunc(v, func(r rune) bool {
+		return r == ';' || r == ',' || r == '\n'
+	})
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func appendUnique(dst, items []string) []string {
+	for _, it := range items {
+		found := false
+		for _, d := range dst {
+			if d == it {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, it)
+		}
+	}
+	return dst
+}
+
+func isBlank(fields []string) bool {
+	for _, f := range fields {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
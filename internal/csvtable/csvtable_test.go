@@ -0,0 +1,173 @@
+package csvtable
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestNewColumns(t *testing.T) {
+	t.Run("overrides defaults", func(t *testing.T) {
+		cols, err := NewColumns(map[string]string{"id": "REQ ID", "given": "前提"})
+		if err != nil {
+			t.Fatalf("NewColumns error: %v", err)
+		}
+		if cols[FieldID] != "REQ ID" || cols[FieldGiven] != "前提" || cols[FieldTitle] != "title" {
+			t.Errorf("unexpected columns: %v", cols)
+		}
+	})
+
+	t.Run("rejects unknown field", func(t *testing.T) {
+		if _, err := NewColumns(map[string]string{"owner": "Owner"}); !apperrors.IsInvalidInput(err) {
+			t.Errorf("expected invalid input, got %v", err)
+		}
+	})
+
+	t.Run("rejects duplicate headers", func(t *testing.T) {
+		if _, err := NewColumns(map[string]string{"title": "id"}); err == nil {
+			t.Error("expected error for duplicate header")
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("groups example rows by requirement", func(t *testing.T) {
+		table := "id,title,description,tags,depends,exampleId,given,when,then\n" +
+			"REQ-001,Login,desc,auth;web,,E1,g1,w1,t1\n" +
+			",,,,,E2,g2,w2,t2\n" +
+			"REQ-002,Logout,,auth,REQ-001,,g3,w3,t3\n" +
+			",New requirement,,,,,,,\n"
+
+		records, err := Read(strings.NewReader(table), DefaultColumns(), ',')
+		if err != nil {
+			t.Fatalf("Read error: %v", err)
+		}
+		if len(records) != 3 {
+			t.Fatalf("expected 3 records, got %d", len(records))
+		}
+
+		s1 := records[0].Spec
+		if s1.ID != "REQ-001" || len(s1.Examples) != 2 || s1.Examples[1].ID != "E2" {
+			t.Errorf("unexpected REQ-001: %+v", s1)
+		}
+		if strings.Join(s1.Tags, ",") != "auth,web" {
+			t.Errorf("Tags = %v", s1.Tags)
+		}
+		if records[1].Row != 4 || records[1].Spec.Depends[0] != "REQ-001" {
+			t.Errorf("unexpected REQ-002 record: %+v", records[1])
+		}
+		if records[2].Spec.ID != "" || records[2].Spec.Title != "New requirement" {
+			t.Errorf("expected new requirement without ID, got %+v", records[2].Spec)
+		}
+	})
+
+	t.Run("uses custom column mapping and tab delimiter", func(t *testing.T) {
+		cols, err := NewColumns(map[string]string{"id": "要件ID", "title": "タイトル"})
+		if err != nil {
+			t.Fatalf("NewColumns error: %v", err)
+		}
+		table := "要件ID\tタイトル\tgiven\twhen\tthen\nREQ-003\tSearch\ta\tb\tc\n"
+
+		records, err := Read(strings.NewReader(table), cols, '\t')
+		if err != nil {
+			t.Fatalf("Read error: %v", err)
+		}
+		if len(records) != 1 || records[0].Spec.ID != "REQ-003" || len(records[0].Spec.Examples) != 1 {
+			t.Errorf("unexpected records: %+v", records)
+		}
+	})
+
+	t.Run("reports all invalid rows with row numbers", func(t *testing.T) {
+		table := "id,title,given,when,then\n" +
+			"REQ-001,Login,g,,t\n" +
+			"BAD-1,Broken,,,\n" +
+			"REQ-003,,g,w,t\n"
+
+		_, err := Read(strings.NewReader(table), DefaultColumns(), ',')
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		var rowErrs RowErrors
+		if !errors.As(err, &rowErrs) {
+			t.Fatalf("expected RowErrors, got %T: %v", err, err)
+		}
+		if len(rowErrs) != 3 {
+			t.Fatalf("expected 3 row errors, got %d: %v", len(rowErrs), rowErrs)
+		}
+		if rowErrs[0].Row != 2 || rowErrs[1].Row != 3 || rowErrs[2].Row != 4 {
+			t.Errorf("unexpected rows: %v", rowErrs)
+		}
+		if !strings.Contains(err.Error(), "row 3: id must match REQ-###") {
+			t.Errorf("expected spec.Validate message, got: %v", err)
+		}
+	})
+
+	t.Run("non-adjacent rows of one requirement are rejected", func(t *testing.T) {
+		table := "id,title\nREQ-001,A\nREQ-002,B\nREQ-001,A\n"
+		_, err := Read(strings.NewReader(table), DefaultColumns(), ',')
+		if err == nil || !strings.Contains(err.Error(), "row 4") {
+			t.Errorf("expected row 4 error, got %v", err)
+		}
+	})
+
+	t.Run("missing title column", func(t *testing.T) {
+		_, err := Read(strings.NewReader("id\nREQ-001\n"), DefaultColumns(), ',')
+		if !apperrors.IsInvalidInput(err) {
+			t.Errorf("expected invalid input, got %v", err)
+		}
+	})
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	specs := []*spec.Spec{
+		{
+			ID:          "REQ-001",
+			Title:       "Login, with comma",
+			Description: "multi\nline",
+			Tags:        []string{"auth", "web"},
+			Examples: []spec.Example{
+				{ID: "E1", Given: "g1", When: "w1", Then: "t1"},
+				{ID: "E5", Given: "g5", When: "w5", Then: "t5"},
+			},
+		},
+		{ID: "REQ-002", Title: "Logout", Depends: []string{"REQ-001"}},
+	}
+
+	for _, delim := range []rune{',', '\t'} {
+		var buf bytes.Buffer
+		if err := Write(&buf, specs, DefaultColumns(), delim); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+
+		records, err := Read(&buf, DefaultColumns(), delim)
+		if err != nil {
+			t.Fatalf("Read error: %v", err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("expected 2 records, got %d", len(records))
+		}
+		got := records[0].Spec
+		if got.Title != specs[0].Title || got.Description != specs[0].Description {
+			t.Errorf("requirement fields not preserved: %+v", got)
+		}
+		if len(got.Examples) != 2 || got.Examples[1].ID != "E5" {
+			t.Errorf("example IDs not preserved: %+v", got.Examples)
+		}
+		if len(records[1].Spec.Examples) != 0 || records[1].Spec.Depends[0] != "REQ-001" {
+			t.Errorf("unexpected REQ-002: %+v", records[1].Spec)
+		}
+	}
+}
+
+func TestDelimiterFor(t *testing.T) {
+	if DelimiterFor("reqs.TSV") != '\t' {
+		t.Error("expected tab for .tsv")
+	}
+	if DelimiterFor("reqs.csv") != ',' || DelimiterFor("") != ',' {
+		t.Error("expected comma by default")
+	}
+}
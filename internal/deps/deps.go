@@ -37,7 +37,10 @@ func (m *MockDetector) Detect(_ context.Context, _ []*spec.Spec) ([]DepsResult,
 	return m.Results, nil
 }
 
-var reqRefPattern = regexp.MustCompile(`REQ-\d+`)
+// reqRefPattern matches requirement IDs of the current ID scheme in text.
+func reqRefPattern() *regexp.Regexp {
+	return regexp.MustCompile(`\b` + spec.CurrentIDScheme().Pattern() + `\b`)
+}
 
 // japaneseDepKeywords are Japanese dependency indicator words.
 var japaneseDepKeywords = []string{"参照", "前提", "依存"}
@@ -59,6 +62,7 @@ func (h *HeuristicDetector) Detect(_ context.Context, specs []*spec.Spec) ([]Dep
 	}
 
 	var results []DepsResult
+	refPattern := reqRefPattern()
 
 	for _, s := range specs {
 		text := collectText(s)
@@ -66,7 +70,7 @@ func (h *HeuristicDetector) Detect(_ context.Context, specs []*spec.Spec) ([]Dep
 		var reasons []string
 
 		// 1. REQ-ID pattern references
-		for _, ref := range reqRefPattern.FindAllString(text, -1) {
+		for _, ref := range refPattern.FindAllString(text, -1) {
 			if ref != s.ID && idSet[ref] && !depSet[ref] {
 				depSet[ref] = true
 				reasons = append(reasons, "REQ-ID reference: "+ref)
@@ -135,7 +139,11 @@ func collectText(s *spec.Spec) string {
 	b.WriteString(s.Title)
 	b.WriteString(" ")
 	b.WriteString(s.Description)
-	for _, ex := range s.Examples {
+	for _, r := range s.Rules {
+		b.WriteString(" ")
+		b.WriteString(r.Text)
+	}
+	for _, ex := range s.AllExamples() {
 		b.WriteString(" ")
 		b.WriteString(ex.Given)
 		b.WriteString(" ")
@@ -170,27 +178,7 @@ func extractKeywords(text string) []string {
 	return out
 }
 
-// sortReqIDs sorts REQ-IDs numerically.
+// sortReqIDs sorts requirement IDs by prefix and number.
 func sortReqIDs(ids []string) {
-	reqIDPat := regexp.MustCompile(`^REQ-(\d+)$`)
-	for i := 1; i < len(ids); i++ {
-		for j := i; j > 0; j-- {
-			am := reqIDPat.FindStringSubmatch(ids[j-1])
-			bm := reqIDPat.FindStringSubmatch(ids[j])
-			if len(am) == 2 && len(bm) == 2 {
-				var ai, bi int
-				for _, c := range am[1] {
-					ai = ai*10 + int(c-'0')
-				}
-				for _, c := range bm[1] {
-					bi = bi*10 + int(c-'0')
-				}
-				if ai > bi {
-					ids[j-1], ids[j] = ids[j], ids[j-1]
-				}
-			} else if ids[j-1] > ids[j] {
-				ids[j-1], ids[j] = ids[j], ids[j-1]
-			}
-		}
-	}
+	spec.CurrentIDScheme().Sort(ids)
 }
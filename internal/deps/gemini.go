@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 
@@ -58,7 +57,7 @@ func NewGeminiDetector(cfg GeminiDetectorConfig) (Detector, error) {
 	gen := func(ctx context.Context, prompt string) (string, error) {
 		result, err := client.Models.GenerateContent(ctx, cfg.Model, genai.Text(prompt), &genai.GenerateContentConfig{
 			ResponseMIMEType: "application/json",
-			ResponseSchema:   depsResponseSchema,
+			ResponseSchema:   depsResponseSchema(),
 		})
 		if err != nil {
 			return "", err
@@ -95,7 +94,7 @@ func (d *GeminiDetector) Detect(ctx context.Context, specs []*spec.Spec) ([]Deps
 		return nil, nil
 	}
 
-	prompt := fmt.Sprintf(depsDetectPrompt, formatSpecsForDeps(specs))
+	prompt := fmt.Sprintf(depsDetectPrompt, spec.CurrentIDScheme().Example(), formatSpecsForDeps(specs))
 
 	var responseText string
 	var lastErr error
@@ -130,7 +129,7 @@ func (d *GeminiDetector) Detect(ctx context.Context, specs []*spec.Spec) ([]Deps
 		idSet[s.ID] = true
 	}
 
-	reqIDPat := regexp.MustCompile(`^REQ-\d+$`)
+	ids := spec.CurrentIDScheme()
 
 	// Filter and validate results
 	var results []DepsResult
@@ -147,7 +146,7 @@ func (d *GeminiDetector) Detect(ctx context.Context, specs []*spec.Spec) ([]Deps
 			if !idSet[dep] {
 				continue // non-existent
 			}
-			if !reqIDPat.MatchString(dep) {
+			if !ids.Valid(dep) {
 				continue // invalid format
 			}
 			validDeps = append(validDeps, dep)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/thirdlf03/spec-tdd/internal/spec"
@@ -35,6 +36,28 @@ func TestGeminiDetector_Success(t *testing.T) {
 	}
 }
 
+func TestGeminiDetector_IDScheme(t *testing.T) {
+	spec.SetIDScheme(spec.NewIDScheme([]string{"AUTH"}, 4, ""))
+	t.Cleanup(func() { spec.SetIDScheme(spec.DefaultIDScheme()) })
+
+	var captured string
+	gen := func(_ context.Context, prompt string) (string, error) {
+		captured = prompt
+		return "[]", nil
+	}
+
+	d := newGeminiDetectorWithFunc(GeminiDetectorConfig{}, gen)
+	if _, err := d.Detect(context.Background(), []*spec.Spec{{ID: "AUTH-0001", Title: "A"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(captured, "「AUTH-0001 を参照」") || strings.Contains(captured, "REQ-") || strings.Contains(captured, "%!") {
+		t.Errorf("prompt should use the configured ID scheme:\n%s", captured)
+	}
+	if desc := depsResponseSchema().Items.Properties["id"].Description; desc != "要件ID (例: AUTH-0001)" {
+		t.Errorf("id description = %q", desc)
+	}
+}
+
 func TestGeminiDetector_FilterSelfReference(t *testing.T) {
 	response := []DepsResult{
 		{ID: "REQ-001", Depends: []string{"REQ-001", "REQ-002"}, Reason: "test"},
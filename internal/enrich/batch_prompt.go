@@ -9,7 +9,9 @@ import (
 )
 
 // batchClassifyPrompt は全セグメントのバッチ分類用プロンプト。
-// %s にセグメント連結テキストが挿入される。
+// 第1 %s: 要件IDの形式（spec.IDScheme.Describe）
+// 第2 %s: 要件IDの例（spec.IDScheme.Example）
+// 第3 %s: セグメント連結テキスト
 const batchClassifyPrompt = `あなたはソフトウェア仕様書の分析エキスパートです。
 以下の複数セグメントをそれぞれ分析し、JSON配列で結果を返してください。
 
@@ -41,9 +43,9 @@ const batchClassifyPrompt = `あなたはソフトウェア仕様書の分析エ
 - データ正規化・変換ルール（Unicode、全角半角、空白トリム等）がある → functional_requirement
 - overviewは「機能仕様を一切含まない」セグメントにのみ使う
 
-### 2. REQ-ID 抽出
+### 2. 要件ID 抽出
 
-セグメント内に「### REQ-XXX: タイトル」パターンがあれば、REQ-ID（例: "REQ-001"）を抽出してください。なければ空文字列を返してください。
+セグメント内に「### <要件ID>: タイトル」パターン（要件IDの形式: %s）があれば、要件ID（例: "%s"）を抽出してください。なければ空文字列を返してください。
 
 ### 3. タイトル抽出
 
@@ -171,32 +173,34 @@ const batchExamplesPrompt = `あなたはソフトウェア仕様書の分析エ
 
 各セグメントの結果をJSON配列で返してください。segment_id を必ず含めてください。`
 
-// batchClassifySchema はバッチ分類のレスポンススキーマ。
-var batchClassifySchema = &genai.Schema{
-	Type: genai.TypeArray,
-	Items: &genai.Schema{
-		Type: genai.TypeObject,
-		Properties: map[string]*genai.Schema{
-			"segment_id": {
-				Type:        genai.TypeString,
-				Description: "セグメントID",
-			},
-			"category": {
-				Type:        genai.TypeString,
-				Enum:        []string{"functional_requirement", "non_functional_requirement", "overview", "other"},
-				Description: "セグメントの種別分類",
-			},
-			"title": {
-				Type:        genai.TypeString,
-				Description: "要件の正確なタイトル",
-			},
-			"req_id": {
-				Type:        genai.TypeString,
-				Description: "セグメント内の REQ-XXX パターン。存在しない場合は空文字列",
+// batchClassifySchema はバッチ分類のレスポンススキーマを返す。
+func batchClassifySchema() *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeArray,
+		Items: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"segment_id": {
+					Type:        genai.TypeString,
+					Description: "セグメントID",
+				},
+				"category": {
+					Type:        genai.TypeString,
+					Enum:        []string{"functional_requirement", "non_functional_requirement", "overview", "other"},
+					Description: "セグメントの種別分類",
+				},
+				"title": {
+					Type:        genai.TypeString,
+					Description: "要件の正確なタイトル",
+				},
+				"req_id": {
+					Type:        genai.TypeString,
+					Description: reqIDDescription(),
+				},
 			},
+			Required: []string{"segment_id", "category", "title"},
 		},
-		Required: []string{"segment_id", "category", "title"},
-	},
+	}
 }
 
 // batchExamplesSchema はバッチ Example 生成のレスポンススキーマ。
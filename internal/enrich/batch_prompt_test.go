@@ -35,14 +35,15 @@ func TestBatchExamplesPrompt(t *testing.T) {
 }
 
 func TestBatchClassifySchema(t *testing.T) {
-	if batchClassifySchema == nil {
+	schema := batchClassifySchema()
+	if schema == nil {
 		t.Fatal("batchClassifySchema should not be nil")
 	}
-	if batchClassifySchema.Items == nil {
+	if schema.Items == nil {
 		t.Fatal("batchClassifySchema should have Items (array type)")
 	}
 
-	item := batchClassifySchema.Items
+	item := schema.Items
 	for _, key := range []string{"segment_id", "category", "title", "req_id"} {
 		if _, ok := item.Properties[key]; !ok {
 			t.Errorf("batchClassifySchema item should have %q property", key)
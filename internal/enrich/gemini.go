@@ -62,7 +62,7 @@ func NewGeminiEnricher(cfg GeminiEnricherConfig) (Enricher, error) {
 	gen := func(ctx context.Context, prompt string) (string, error) {
 		result, err := client.Models.GenerateContent(ctx, cfg.Model, genai.Text(prompt), &genai.GenerateContentConfig{
 			ResponseMIMEType: "application/json",
-			ResponseSchema:   enrichResponseSchema,
+			ResponseSchema:   enrichResponseSchema(),
 			Temperature:      &temp,
 		})
 		if err != nil {
@@ -95,7 +95,8 @@ type enrichResponse struct {
 // Enrich はセグメントを解析し、分類・メタデータ抽出・GWT 生成を行う。
 func (e *GeminiEnricher) Enrich(ctx context.Context, segment *kire.Segment, contextSegments []*kire.Segment) (*EnrichResult, error) {
 	contextSection := formatContextSection(contextSegments)
-	prompt := fmt.Sprintf(classifyAndEnrichPrompt, contextSection, segment.Content)
+	scheme := spec.CurrentIDScheme()
+	prompt := fmt.Sprintf(classifyAndEnrichPrompt, contextSection, scheme.Describe(), scheme.Example(), segment.Content)
 
 	var responseText string
 	var lastErr error
@@ -93,7 +93,8 @@ func (e *GeminiBatchEnricher) BatchClassify(ctx context.Context, segments []*kir
 		return nil, nil
 	}
 
-	prompt := fmt.Sprintf(batchClassifyPrompt, formatSegmentsForClassify(segments))
+	scheme := spec.CurrentIDScheme()
+	prompt := fmt.Sprintf(batchClassifyPrompt, scheme.Describe(), scheme.Example(), formatSegmentsForClassify(segments))
 
 	var responseText string
 	var resp *genai.GenerateContentResponse
@@ -101,7 +102,7 @@ func (e *GeminiBatchEnricher) BatchClassify(ctx context.Context, segments []*kir
 
 	for attempt := 0; attempt <= e.cfg.MaxRetries; attempt++ {
 		callCtx, cancel := context.WithTimeout(ctx, e.cfg.ClassifyTimeout)
-		text, r, err := e.generate(callCtx, e.cfg.ClassifyModel, prompt, batchClassifySchema)
+		text, r, err := e.generate(callCtx, e.cfg.ClassifyModel, prompt, batchClassifySchema())
 		cancel()
 
 		if err == nil {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/thirdlf03/spec-tdd/internal/kire"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
 	"google.golang.org/genai"
 )
 
@@ -109,6 +110,31 @@ func TestGeminiBatchEnricher_BatchClassify(t *testing.T) {
 		}
 	})
 
+	t.Run("prompt and schema use the configured ID scheme", func(t *testing.T) {
+		spec.SetIDScheme(spec.NewIDScheme([]string{"AUTH"}, 3, ""))
+		t.Cleanup(func() { spec.SetIDScheme(spec.DefaultIDScheme()) })
+		e := newTestBatchEnricher(func(_ context.Context, _, prompt string, schema *genai.Schema) (string, *genai.GenerateContentResponse, error) {
+			if !strings.Contains(prompt, "要件IDの形式: AUTH-###") || !strings.Contains(prompt, `例: "AUTH-001"`) || strings.Contains(prompt, "%!") {
+				t.Errorf("prompt should describe the configured ID scheme:\n%s", prompt)
+			}
+			if desc := schema.Items.Properties["req_id"].Description; !strings.Contains(desc, "AUTH-###") {
+				t.Errorf("req_id description = %q", desc)
+			}
+			results := make([]BatchClassifyResult, len(segments))
+			for i, seg := range segments {
+				results[i] = BatchClassifyResult{SegmentID: seg.Meta.SegmentID, Category: CategoryOther, Title: "t"}
+			}
+			b, _ := json.Marshal(results)
+			return string(b), &genai.GenerateContentResponse{
+				Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonStop}},
+			}, nil
+		})
+
+		if _, err := e.BatchClassify(context.Background(), segments); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
 	t.Run("empty segments returns nil", func(t *testing.T) {
 		e := newTestBatchEnricher(nil)
 		results, err := e.BatchClassify(context.Background(), nil)
@@ -228,6 +228,26 @@ func TestGeminiEnricher_Enrich(t *testing.T) {
 		}
 	})
 
+	t.Run("prompt describes the configured ID scheme", func(t *testing.T) {
+		spec.SetIDScheme(spec.NewIDScheme([]string{"AUTH", "BILL"}, 4, ""))
+		t.Cleanup(func() { spec.SetIDScheme(spec.DefaultIDScheme()) })
+		var capturedPrompt string
+		e := newTestEnricher(func(_ context.Context, prompt string) (string, error) {
+			capturedPrompt = prompt
+			return `{"category":"functional_requirement","req_id":"","title":"テスト","examples":[]}`, nil
+		})
+
+		if _, err := e.Enrich(context.Background(), seg, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(capturedPrompt, "要件IDの形式: AUTH-#### or BILL-####") || !strings.Contains(capturedPrompt, `例: "AUTH-0001"`) {
+			t.Error("prompt should describe the configured ID scheme")
+		}
+		if strings.Contains(capturedPrompt, "REQ-XXX") || strings.Contains(capturedPrompt, "%!") {
+			t.Errorf("prompt should not contain hard-coded IDs or format errors:\n%s", capturedPrompt)
+		}
+	})
+
 	t.Run("nil context produces no context section in prompt", func(t *testing.T) {
 		var capturedPrompt string
 		e := newTestEnricher(func(_ context.Context, prompt string) (string, error) {
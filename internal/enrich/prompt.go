@@ -1,12 +1,17 @@
 package enrich
 
 import (
+	"fmt"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
 	"google.golang.org/genai"
 )
 
 // classifyAndEnrichPrompt はセグメント分類 + GWT 生成用のプロンプトテンプレート。
 // 第1 %s: 共通仕様コンテキスト（空文字列の場合もある）
-// 第2 %s: セグメント content
+// 第2 %s: 要件IDの形式（spec.IDScheme.Describe）
+// 第3 %s: 要件IDの例（spec.IDScheme.Example）
+// 第4 %s: セグメント content
 const classifyAndEnrichPrompt = `あなたはソフトウェア仕様書の分析エキスパートです。
 以下のセグメントを分析し、JSON形式で結果を返してください。
 %s
@@ -36,9 +41,9 @@ const classifyAndEnrichPrompt = `あなたはソフトウェア仕様書の分
 - データ正規化・変換ルール（Unicode、全角半角、空白トリム等）がある → functional_requirement
 - overviewは「機能仕様を一切含まない」セグメントにのみ使う
 
-### 2. REQ-ID 抽出
+### 2. 要件ID 抽出
 
-セグメント内に「### REQ-XXX: タイトル」パターンがあれば、REQ-ID（例: "REQ-001"）を抽出してください。なければ空文字列を返してください。
+セグメント内に「### <要件ID>: タイトル」パターン（要件IDの形式: %s）があれば、要件ID（例: "%s"）を抽出してください。なければ空文字列を返してください。
 
 ### 3. タイトル抽出
 
@@ -94,36 +99,44 @@ Unicode正規化 / 空白正規化 / null変換 / 配列正規化 / 状態遷移
 
 JSONオブジェクトで返してください。`
 
-// enrichResponseSchema は Gemini API の構造化出力用スキーマ。
-var enrichResponseSchema = &genai.Schema{
-	Type: genai.TypeObject,
-	Properties: map[string]*genai.Schema{
-		"category": {
-			Type:        genai.TypeString,
-			Enum:        []string{"functional_requirement", "non_functional_requirement", "overview", "other"},
-			Description: "セグメントの種別分類",
-		},
-		"req_id": {
-			Type:        genai.TypeString,
-			Description: "セグメント内の REQ-XXX パターン。存在しない場合は空文字列",
-		},
-		"title": {
-			Type:        genai.TypeString,
-			Description: "要件の正確なタイトル",
-		},
-		"examples": {
-			Type: genai.TypeArray,
-			Items: &genai.Schema{
-				Type: genai.TypeObject,
-				Properties: map[string]*genai.Schema{
-					"given": {Type: genai.TypeString, Description: "テストの前提条件。何が存在しどういう状態かを具体的に記述"},
-					"when":  {Type: genai.TypeString, Description: "実行する操作。HTTPメソッド+パスを明記"},
-					"then":  {Type: genai.TypeString, Description: "期待される結果。HTTPステータスコードとレスポンス内容を含む"},
+// enrichResponseSchema は Gemini API の構造化出力用スキーマを返す。
+// req_id の説明には現在の ID スキームを使う。
+func enrichResponseSchema() *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"category": {
+				Type:        genai.TypeString,
+				Enum:        []string{"functional_requirement", "non_functional_requirement", "overview", "other"},
+				Description: "セグメントの種別分類",
+			},
+			"req_id": {
+				Type:        genai.TypeString,
+				Description: reqIDDescription(),
+			},
+			"title": {
+				Type:        genai.TypeString,
+				Description: "要件の正確なタイトル",
+			},
+			"examples": {
+				Type: genai.TypeArray,
+				Items: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"given": {Type: genai.TypeString, Description: "テストの前提条件。何が存在しどういう状態かを具体的に記述"},
+						"when":  {Type: genai.TypeString, Description: "実行する操作。HTTPメソッド+パスを明記"},
+						"then":  {Type: genai.TypeString, Description: "期待される結果。HTTPステータスコードとレスポンス内容を含む"},
+					},
+					Required: []string{"given", "when", "then"},
 				},
-				Required: []string{"given", "when", "then"},
+				Description: "Given/When/Then 形式の Examples",
 			},
-			Description: "Given/When/Then 形式の Examples",
 		},
-	},
-	Required: []string{"category", "title"},
+		Required: []string{"category", "title"},
+	}
+}
+
+// reqIDDescription は req_id プロパティの説明。
+func reqIDDescription() string {
+	return fmt.Sprintf("セグメント内の要件ID（%s）。存在しない場合は空文字列", spec.CurrentIDScheme().Describe())
 }
@@ -3,6 +3,8 @@ package enrich
 import (
 	"strings"
 	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
 func TestClassifyAndEnrichPrompt(t *testing.T) {
@@ -21,27 +23,38 @@ func TestClassifyAndEnrichPrompt(t *testing.T) {
 }
 
 func TestEnrichResponseSchema(t *testing.T) {
-	if enrichResponseSchema == nil {
+	schema := enrichResponseSchema()
+	if schema == nil {
 		t.Fatal("enrichResponseSchema should not be nil")
 	}
 
-	props, ok := enrichResponseSchema.Properties["category"]
+	props, ok := schema.Properties["category"]
 	if !ok || props == nil {
 		t.Error("enrichResponseSchema should have 'category' property")
 	}
 
-	props, ok = enrichResponseSchema.Properties["title"]
+	props, ok = schema.Properties["title"]
 	if !ok || props == nil {
 		t.Error("enrichResponseSchema should have 'title' property")
 	}
 
-	props, ok = enrichResponseSchema.Properties["examples"]
+	props, ok = schema.Properties["examples"]
 	if !ok || props == nil {
 		t.Error("enrichResponseSchema should have 'examples' property")
 	}
 
-	props, ok = enrichResponseSchema.Properties["req_id"]
+	props, ok = schema.Properties["req_id"]
 	if !ok || props == nil {
 		t.Error("enrichResponseSchema should have 'req_id' property")
 	}
 }
+
+func TestEnrichResponseSchema_IDScheme(t *testing.T) {
+	spec.SetIDScheme(spec.NewIDScheme([]string{"AUTH", "BILL"}, 4, ""))
+	t.Cleanup(func() { spec.SetIDScheme(spec.DefaultIDScheme()) })
+
+	desc := enrichResponseSchema().Properties["req_id"].Description
+	if !strings.Contains(desc, "AUTH-#### or BILL-####") || strings.Contains(desc, "REQ") {
+		t.Errorf("req_id description = %q, want the configured scheme", desc)
+	}
+}
@@ -0,0 +1,105 @@
+// Package gitrev reads files as they were at a revision of the local git
+// repository, straight from the object store, without touching the working
+// tree.
+package gitrev
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+// ReadDir returns the content of every file below dir (relative to the
+// current directory) at rev, keyed by path relative to the current
+// directory. A dir that does not exist at rev yields no files.
+func ReadDir(rev, dir string) (map[string][]byte, error) {
+	if err := Verify(rev); err != nil {
+		return nil, err
+	}
+
+	out, err := git(nil, "ls-tree", "-r", "-z", rev, "--", dir)
+	if err != nil {
+		return nil, apperrors.Wrap("gitrev.ReadDir", err)
+	}
+	var paths, objects []string
+	for _, entry := range strings.Split(string(out), "\x00") {
+		// "<mode> SP <type> SP <object> TAB <path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		paths = append(paths, path)
+		objects = append(objects, fields[2])
+	}
+	if len(objects) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	out, err = git(strings.NewReader(strings.Join(objects, "\n")+"\n"), "cat-file", "--batch")
+	if err != nil {
+		return nil, apperrors.Wrap("gitrev.ReadDir", err)
+	}
+	files := make(map[string][]byte, len(paths))
+	r := bufio.NewReader(bytes.NewReader(out))
+	for _, path := range paths {
+		// "<object> SP <type> SP <size> LF <content> LF"
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, apperrors.Wrap("gitrev.ReadDir", err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, apperrors.New("gitrev.ReadDir", apperrors.ErrInvalidInput, fmt.Sprintf("unexpected git cat-file output %q", header))
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, apperrors.Wrap("gitrev.ReadDir", err)
+		}
+		data := make([]byte, size+1)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, apperrors.Wrap("gitrev.ReadDir", err)
+		}
+		files[path] = data[:size]
+	}
+	return files, nil
+}
+
+// Verify checks that rev names a commit.
+func Verify(rev string) error {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return apperrors.New("gitrev.Verify", apperrors.ErrInvalidInput, fmt.Sprintf("invalid revision %q", rev))
+	}
+	if _, err := git(nil, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return apperrors.New("gitrev.Verify", apperrors.ErrInvalidInput, fmt.Sprintf("unknown revision %q", rev))
+		}
+		return apperrors.Wrap("gitrev.Verify", err)
+	}
+	return nil
+}
+
+// git runs a git command and returns its stdout. Errors include git's
+// stderr.
+func git(stdin io.Reader, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package gitrev
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+// setupRepo creates a git repository with two commits in a temporary
+// directory and changes into it.
+func setupRepo(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmpDir := t.TempDir()
+	oldWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(oldWD)
+	})
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir error: %v", err)
+	}
+
+	runGit(t, "init", "-q")
+	writeFile(t, "specs/a.yml", "id: A\n")
+	writeFile(t, "specs/sub/b.yml", "id: B\n")
+	writeFile(t, "other.txt", "x\n")
+	runGit(t, "add", "-A")
+	runGit(t, "commit", "-q", "-m", "first")
+	writeFile(t, "specs/a.yml", "id: A\ntitle: changed\n")
+	runGit(t, "commit", "-q", "-am", "second")
+}
+
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	setupRepo(t)
+	// The working tree must not be read
+	writeFile(t, "specs/a.yml", "id: A\ntitle: uncommitted\n")
+
+	tests := []struct {
+		rev  string
+		want map[string]string
+	}{
+		{"HEAD~1", map[string]string{"specs/a.yml": "id: A\n", "specs/sub/b.yml": "id: B\n"}},
+		{"HEAD", map[string]string{"specs/a.yml": "id: A\ntitle: changed\n", "specs/sub/b.yml": "id: B\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rev, func(t *testing.T) {
+			files, err := ReadDir(tt.rev, "specs")
+			if err != nil {
+				t.Fatalf("ReadDir error: %v", err)
+			}
+			if len(files) != len(tt.want) {
+				t.Errorf("files = %v, want %v", files, tt.want)
+			}
+			for path, content := range tt.want {
+				if string(files[path]) != content {
+					t.Errorf("%s = %q, want %q", path, files[path], content)
+				}
+			}
+		})
+	}
+
+	t.Run("missing directory", func(t *testing.T) {
+		files, err := ReadDir("HEAD", "nope")
+		if err != nil || len(files) != 0 {
+			t.Errorf("ReadDir = %v, %v; want no files", files, err)
+		}
+	})
+}
+
+func TestVerify(t *testing.T) {
+	setupRepo(t)
+
+	for _, rev := range []string{"no-such-branch", "--all", ""} {
+		t.Run(rev, func(t *testing.T) {
+			if err := Verify(rev); !apperrors.IsInvalidInput(err) {
+				t.Errorf("Verify(%q) = %v, want invalid input", rev, err)
+			}
+		})
+	}
+	if err := Verify("HEAD~1"); err != nil {
+		t.Errorf("Verify(HEAD~1) = %v", err)
+	}
+}
@@ -131,6 +131,22 @@ func RenderGuide(data GuideData, testDir, fileNamePattern string) string {
 		sb.WriteString("\n")
 	}
 
+	// Open questions
+	var openCount int
+	for _, s := range data.Order {
+		openCount += len(s.OpenQuestions())
+	}
+	if openCount > 0 {
+		sb.WriteString(fmt.Sprintf("## Open Questions (%d)\n\n", openCount))
+		sb.WriteString("Resolve these before implementing the affected requirements.\n\n")
+		for _, s := range data.Order {
+			for _, q := range s.OpenQuestions() {
+				sb.WriteString(fmt.Sprintf("- **%s** %s: %s%s\n", s.ID, q.ID, q.Text, ownerNote(q)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	// Prerequisites summary
 	sb.WriteString("## Prerequisites\n\n")
 	hasPrereqs := false
@@ -165,7 +181,11 @@ func RenderGuide(data GuideData, testDir, fileNamePattern string) string {
 	// Implementation Order
 	sb.WriteString("## Implementation Order\n\n")
 	for i, s := range data.Order {
-		sb.WriteString(fmt.Sprintf("%d. **%s**: %s\n", i+1, s.ID, s.Title))
+		line := fmt.Sprintf("%d. **%s**: %s", i+1, s.ID, s.Title)
+		if s.Status != "" {
+			line += fmt.Sprintf(" (%s)", s.Status)
+		}
+		sb.WriteString(line + "\n")
 	}
 	sb.WriteString("\n")
 
@@ -174,6 +194,14 @@ func RenderGuide(data GuideData, testDir, fileNamePattern string) string {
 	for _, s := range data.Order {
 		sb.WriteString(fmt.Sprintf("### %s: %s\n\n", s.ID, s.Title))
 
+		if open := s.OpenQuestions(); len(open) > 0 {
+			sb.WriteString(fmt.Sprintf("> **Open questions (%d):**\n", len(open)))
+			for _, q := range open {
+				sb.WriteString(fmt.Sprintf("> - %s: %s%s\n", q.ID, q.Text, ownerNote(q)))
+			}
+			sb.WriteString("\n")
+		}
+
 		if len(s.Depends) > 0 {
 			sb.WriteString(fmt.Sprintf("**Depends on:** %s\n\n", strings.Join(s.Depends, ", ")))
 		}
@@ -191,11 +219,15 @@ func RenderGuide(data GuideData, testDir, fileNamePattern string) string {
 		if len(s.Examples) > 0 {
 			sb.WriteString("**Examples:**\n")
 			for _, ex := range s.Examples {
-				id := ex.ID
-				if id == "" {
-					id = "E?"
-				}
-				sb.WriteString(fmt.Sprintf("- %s: Given %s / When %s / Then %s\n", id, ex.Given, ex.When, ex.Then))
+				sb.WriteString(exampleLine(ex))
+			}
+			sb.WriteString("\n")
+		}
+
+		for _, r := range s.Rules {
+			sb.WriteString(fmt.Sprintf("**Rule %s:** %s\n", r.ID, r.Text))
+			for _, ex := range r.Examples {
+				sb.WriteString(exampleLine(ex))
 			}
 			sb.WriteString("\n")
 		}
@@ -204,6 +236,23 @@ func RenderGuide(data GuideData, testDir, fileNamePattern string) string {
 	return sb.String()
 }
 
+// exampleLine formats an example as a list item.
+func exampleLine(ex spec.Example) string {
+	id := ex.ID
+	if id == "" {
+		id = "E?"
+	}
+	return fmt.Sprintf("- %s: Given %s / When %s / Then %s\n", id, ex.Given, ex.When, ex.Then)
+}
+
+// ownerNote formats the question owner for display.
+func ownerNote(q spec.Question) string {
+	if q.Owner == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (owner: %s)", q.Owner)
+}
+
 // findRoots returns specs that have no dependencies (root nodes).
 func findRoots(specs []*spec.Spec) []*spec.Spec {
 	var roots []*spec.Spec
@@ -255,25 +304,7 @@ func renderTree(sb *strings.Builder, id string, dependedBy map[string][]string,
 	}
 }
 
-// sortQueue sorts REQ-IDs in numeric order.
+// sortQueue sorts requirement IDs by prefix and number.
 func sortQueue(ids []string) {
-	for i := 1; i < len(ids); i++ {
-		for j := i; j > 0 && reqIDNum(ids[j-1]) > reqIDNum(ids[j]); j-- {
-			ids[j-1], ids[j] = ids[j], ids[j-1]
-		}
-	}
-}
-
-// reqIDNum extracts the numeric part of a REQ-### ID for sorting.
-func reqIDNum(id string) int {
-	if len(id) > 4 && id[:4] == "REQ-" {
-		n := 0
-		for _, c := range id[4:] {
-			if c >= '0' && c <= '9' {
-				n = n*10 + int(c-'0')
-			}
-		}
-		return n
-	}
-	return 999999
+	spec.CurrentIDScheme().Sort(ids)
 }
@@ -245,3 +245,29 @@ func TestRenderGuide_WithWarnings(t *testing.T) {
 		t.Errorf("expected cycle info in warnings, got:\n%s", guide)
 	}
 }
+
+func TestRenderGuide_OpenQuestions(t *testing.T) {
+	specs := []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Questions: []spec.Question{
+			{ID: "Q1", Text: "Lockout threshold?", Owner: "po", Status: spec.QuestionOpen},
+			{ID: "Q2", Text: "Remember me?", Status: spec.QuestionResolved, Answer: "no"},
+		}},
+	}
+	result := TopologicalSort(specs)
+	data := GuideData{Specs: specs, Order: result.Order, DependedBy: BuildDependedByMap(specs)}
+
+	guide := RenderGuide(data, "tests", "req-{{id}}-{{slug}}.test.ts")
+
+	for _, want := range []string{
+		"## Open Questions (1)",
+		"- **REQ-001** Q1: Lockout threshold? (owner: po)",
+		"> **Open questions (1):**",
+	} {
+		if !strings.Contains(guide, want) {
+			t.Errorf("expected %q in guide, got:\n%s", want, guide)
+		}
+	}
+	if strings.Contains(guide, "Remember me?") {
+		t.Errorf("resolved questions should not be listed in the guide:\n%s", guide)
+	}
+}
@@ -0,0 +1,243 @@
+// Package journal records the file changes made by each spec-tdd command so
+// they can be listed (log) and reverted (undo). Every operation is stored as
+// one JSON file in the journal directory, holding the content of each file
+// before the change.
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+)
+
+// MaxOperations is how many operations the journal keeps; older ones are
+// dropped when a new one is saved.
+const MaxOperations = 100
+
+// Operation is one command and the files it changed.
+type Operation struct {
+	ID      int          `json:"id"`
+	Command string       `json:"command"`
+	Time    time.Time    `json:"time"`
+	Undoes  int          `json:"undoes,omitempty"`
+	Changes []FileChange `json:"changes"`
+}
+
+// FileChange is the change to one file. An empty hash means the file did not
+// exist: BeforeHash is empty for created files, AfterHash for removed ones.
+type FileChange struct {
+	Path       string `json:"path"`
+	BeforeHash string `json:"beforeHash,omitempty"`
+	AfterHash  string `json:"afterHash,omitempty"`
+	Before     string `json:"before,omitempty"`
+	// Mode holds the permission bits of the file before the change.
+	// Operations recorded without it restore files as 0644.
+	Mode os.FileMode `json:"mode,omitempty"`
+}
+
+// Kind describes the change as created, modified or removed.
+func (c FileChange) Kind() string {
+	switch {
+	case c.BeforeHash == "":
+		return "created"
+	case c.AfterHash == "":
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+// Hash returns the hash recorded for content.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// New starts an operation for command.
+func New(command string) *Operation {
+	return &Operation{Command: command, Time: time.Now().UTC()}
+}
+
+// Record adds a change to the operation. Several changes to the same file
+// are merged, keeping the first before state and the last after state.
+func (op *Operation) Record(c atomicfile.Change) {
+	after := ""
+	if !c.Removed {
+		after = Hash(c.After)
+	}
+	for i := range op.Changes {
+		if op.Changes[i].Path == c.Path {
+			op.Changes[i].AfterHash = after
+			return
+		}
+	}
+	fc := FileChange{Path: c.Path, AfterHash: after}
+	if c.Existed {
+		fc.BeforeHash = Hash(c.Before)
+		fc.Before = string(c.Before)
+		fc.Mode = c.Mode.Perm()
+	}
+	op.Changes = append(op.Changes, fc)
+}
+
+// Save stores op in dir under the next ID and drops the oldest operations
+// beyond MaxOperations. Operations whose changes cancel out are not stored.
+func Save(dir string, op *Operation) error {
+	changes := op.Changes[:0]
+	for _, c := range op.Changes {
+		if c.BeforeHash != c.AfterHash {
+			changes = append(changes, c)
+		}
+	}
+	op.Changes = changes
+	if len(op.Changes) == 0 {
+		return nil
+	}
+
+	ids, err := list(dir)
+	if err != nil {
+		return err
+	}
+	op.ID = 1
+	if len(ids) > 0 {
+		op.ID = ids[len(ids)-1] + 1
+	}
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return apperrors.Wrap("journal.Save", err)
+	}
+	if err := atomicfile.WriteFile(filepath.Join(dir, fileName(op.ID)), append(data, '\n'), 0644); err != nil {
+		return apperrors.Wrap("journal.Save", err)
+	}
+
+	ids = append(ids, op.ID)
+	for len(ids) > MaxOperations {
+		if err := os.Remove(filepath.Join(dir, fileName(ids[0]))); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return apperrors.Wrap("journal.Save", err)
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// Load returns the operations in dir, oldest first. A missing directory is
+// an empty journal.
+func Load(dir string) ([]*Operation, error) {
+	ids, err := list(dir)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]*Operation, 0, len(ids))
+	for _, id := range ids {
+		path := filepath.Join(dir, fileName(id))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, apperrors.Wrap("journal.Load", err)
+		}
+		var op Operation
+		if err := json.Unmarshal(data, &op); err != nil {
+			return nil, apperrors.New("journal.Load", apperrors.ErrInvalidInput, fmt.Sprintf("%s: %v", path, err))
+		}
+		op.ID = id
+		ops = append(ops, &op)
+	}
+	return ops, nil
+}
+
+// Undone returns the IDs of the operations reverted by a later undo.
+func Undone(ops []*Operation) map[int]bool {
+	undone := make(map[int]bool)
+	for _, op := range ops {
+		if op.Undoes > 0 {
+			undone[op.Undoes] = true
+		}
+	}
+	return undone
+}
+
+// LastUndoable returns the newest operation that is neither an undo nor
+// already undone.
+func LastUndoable(ops []*Operation) (*Operation, error) {
+	undone := Undone(ops)
+	for i := len(ops) - 1; i >= 0; i-- {
+		if ops[i].Undoes == 0 && !undone[ops[i].ID] {
+			return ops[i], nil
+		}
+	}
+	return nil, apperrors.New("journal.LastUndoable", apperrors.ErrNotFound, "nothing to undo")
+}
+
+// Modified returns the files of op whose current content is not what op left
+// behind, i.e. files changed since.
+func Modified(op *Operation) ([]string, error) {
+	var paths []string
+	for _, c := range op.Changes {
+		current := ""
+		data, err := os.ReadFile(c.Path)
+		switch {
+		case err == nil:
+			current = Hash(data)
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, apperrors.Wrap("journal.Modified", err)
+		}
+		if current != c.AfterHash {
+			paths = append(paths, c.Path)
+		}
+	}
+	return paths, nil
+}
+
+// Revert stages in tx the changes that put every file of op back into its
+// state before op.
+func Revert(tx *atomicfile.Tx, op *Operation) {
+	for i := len(op.Changes) - 1; i >= 0; i-- {
+		c := op.Changes[i]
+		if c.BeforeHash == "" {
+			tx.Remove(c.Path)
+		} else {
+			mode := c.Mode.Perm()
+			if mode == 0 {
+				mode = 0644
+			}
+			tx.Write(c.Path, []byte(c.Before), mode)
+		}
+	}
+}
+
+func fileName(id int) string {
+	return fmt.Sprintf("%06d.json", id)
+}
+
+// list returns the operation IDs in dir in ascending order.
+func list(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap("journal", err)
+	}
+	var ids []int
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if id, err := strconv.Atoi(name); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
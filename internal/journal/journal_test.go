@@ -0,0 +1,167 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+)
+
+func TestRecord(t *testing.T) {
+	op := New("spec-tdd test")
+	op.Record(atomicfile.Change{Path: "a.yml", Before: []byte("a1"), Existed: true, After: []byte("a2")})
+	op.Record(atomicfile.Change{Path: "b.yml", After: []byte("b")})
+	op.Record(atomicfile.Change{Path: "a.yml", Before: []byte("a2"), Existed: true, After: []byte("a3")})
+	op.Record(atomicfile.Change{Path: "c.yml", Before: []byte("c"), Existed: true, Removed: true})
+
+	tests := []struct {
+		path, kind, before, afterHash string
+	}{
+		{"a.yml", "modified", "a1", Hash([]byte("a3"))},
+		{"b.yml", "created", "", Hash([]byte("b"))},
+		{"c.yml", "removed", "c", ""},
+	}
+	if len(op.Changes) != len(tests) {
+		t.Fatalf("changes = %+v, want %d", op.Changes, len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			c := op.Changes[i]
+			if c.Path != tt.path || c.Kind() != tt.kind || c.Before != tt.before || c.AfterHash != tt.afterHash {
+				t.Errorf("change = %+v (%s), want %s %s before %q", c, c.Kind(), tt.path, tt.kind, tt.before)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+
+	ops, err := Load(dir)
+	if err != nil || len(ops) != 0 {
+		t.Fatalf("Load of a missing journal = %v, %v", ops, err)
+	}
+
+	// Changes that cancel out are not stored
+	noop := New("noop")
+	noop.Record(atomicfile.Change{Path: "a.yml", Before: []byte("a"), Existed: true, After: []byte("a")})
+	if err := Save(dir, noop); err != nil {
+		t.Fatal(err)
+	}
+	if ops, _ := Load(dir); len(ops) != 0 {
+		t.Fatalf("no-op operation stored: %+v", ops[0])
+	}
+
+	for i := 0; i < MaxOperations+2; i++ {
+		op := New(fmt.Sprintf("cmd %d", i))
+		op.Record(atomicfile.Change{Path: "a.yml", After: []byte{byte(i)}})
+		if err := Save(dir, op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ops, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != MaxOperations {
+		t.Fatalf("len(ops) = %d, want %d", len(ops), MaxOperations)
+	}
+	if ops[0].ID != 3 || ops[len(ops)-1].ID != MaxOperations+2 || ops[len(ops)-1].Command != fmt.Sprintf("cmd %d", MaxOperations+1) {
+		t.Errorf("kept #%d..#%d (%s), want the newest", ops[0].ID, ops[len(ops)-1].ID, ops[len(ops)-1].Command)
+	}
+}
+
+func TestLastUndoable(t *testing.T) {
+	ops := []*Operation{{ID: 1}, {ID: 2}, {ID: 3, Undoes: 2}}
+	op, err := LastUndoable(ops)
+	if err != nil || op.ID != 1 {
+		t.Fatalf("LastUndoable = %+v, %v; want #1", op, err)
+	}
+
+	ops = append(ops, &Operation{ID: 4, Undoes: 1})
+	if _, err := LastUndoable(ops); !apperrors.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.yml"), filepath.Join(dir, "b.yml")
+	writeFile(t, a, "a1")
+
+	op := New("spec-tdd test")
+	atomicfile.SetObserver(op.Record)
+	tx := atomicfile.NewTx()
+	tx.Write(a, []byte("a2"), 0644)
+	tx.Write(b, []byte("b"), 0644)
+	err := tx.Commit()
+	atomicfile.SetObserver(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if modified, err := Modified(op); err != nil || len(modified) != 0 {
+		t.Fatalf("Modified = %v, %v; want none", modified, err)
+	}
+	writeFile(t, b, "edited")
+	if modified, _ := Modified(op); len(modified) != 1 || modified[0] != b {
+		t.Errorf("Modified = %v, want [%s]", modified, b)
+	}
+
+	tx = atomicfile.NewTx()
+	Revert(tx, op)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(a); string(got) != "a1" {
+		t.Errorf("a.yml = %q, want a1", got)
+	}
+	if _, err := os.Stat(b); !os.IsNotExist(err) {
+		t.Errorf("b.yml should be removed, got %v", err)
+	}
+}
+
+func TestRevertRestoresMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "private.yml")
+	writeFile(t, path, "secret")
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	op := New("spec-tdd test")
+	atomicfile.SetObserver(op.Record)
+	tx := atomicfile.NewTx()
+	tx.Remove(path)
+	err := tx.Commit()
+	atomicfile.SetObserver(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Changes[0].Mode != 0600 {
+		t.Errorf("recorded mode = %o, want 600", op.Changes[0].Mode)
+	}
+
+	tx = atomicfile.NewTx()
+	Revert(tx, op)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("file not restored: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("mode = %o, want 600", perm)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
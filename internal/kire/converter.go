@@ -9,19 +9,18 @@ import (
 )
 
 var (
-	reqIDExtractPattern = regexp.MustCompile(`REQ-(\d{3})`)
-	gwtGivenPattern     = regexp.MustCompile(`(?i)^[-*]?\s*given:\s*(.+)`)
-	gwtWhenPattern      = regexp.MustCompile(`(?i)^[-*]?\s*when:\s*(.+)`)
-	gwtThenPattern      = regexp.MustCompile(`(?i)^[-*]?\s*then:\s*(.+)`)
-	questionsSectionRe  = regexp.MustCompile(`(?i)^#{2,3}\s+questions`)
-	headingRe           = regexp.MustCompile(`^#+\s+`)
+	gwtGivenPattern    = regexp.MustCompile(`(?i)^[-*]?\s*given:\s*(.+)`)
+	gwtWhenPattern     = regexp.MustCompile(`(?i)^[-*]?\s*when:\s*(.+)`)
+	gwtThenPattern     = regexp.MustCompile(`(?i)^[-*]?\s*then:\s*(.+)`)
+	questionsSectionRe = regexp.MustCompile(`(?i)^#{2,3}\s+questions`)
+	headingRe          = regexp.MustCompile(`^#+\s+`)
 )
 
-// ExtractReqID extracts the first REQ-### pattern from content.
+// ExtractReqID extracts the first requirement ID of the current ID scheme
+// (REQ-### by default) from content.
 // Returns empty string if not found.
 func ExtractReqID(content string) string {
-	matches := reqIDExtractPattern.FindString(content)
-	return matches
+	return regexp.MustCompile(`\b` + spec.CurrentIDScheme().Pattern() + `\b`).FindString(content)
 }
 
 // ExtractExamples extracts Given/When/Then example sets from content.
@@ -155,7 +154,7 @@ func ConvertToSpec(seg *Segment, specDir string) (*spec.Spec, error) {
 		ID:        id,
 		Title:     title,
 		Examples:  examples,
-		Questions: questions,
+		Questions: spec.QuestionsFromText(questions),
 		Source: spec.SourceInfo{
 			SegmentID:   seg.Meta.SegmentID,
 			HeadingPath: headingPath,
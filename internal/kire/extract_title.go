@@ -4,18 +4,24 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
-var reqIDWithTitlePattern = regexp.MustCompile(`###\s+REQ-(\d{3}):\s*(.+)`)
+// reqIDWithTitlePattern は現在の ID 体系の `### <ID>: タイトル` にマッチする。
+func reqIDWithTitlePattern() *regexp.Regexp {
+	return regexp.MustCompile(`###\s+(` + spec.CurrentIDScheme().Pattern() + `):\s*(.+)`)
+}
 
 // ExtractReqIDWithTitle は content 内の `### REQ-XXX: タイトル` パターンから
-// REQ-ID とタイトルを同時抽出する。パターンが見つからない場合は空文字列を返す。
+// REQ-ID とタイトルを同時抽出する。ID の接頭辞は設定された ID 体系に従う
+// (例: `### AUTH-012: ログイン`)。パターンが見つからない場合は空文字列を返す。
 func ExtractReqIDWithTitle(content string) (string, string) {
-	matches := reqIDWithTitlePattern.FindStringSubmatch(content)
+	matches := reqIDWithTitlePattern().FindStringSubmatch(content)
 	if len(matches) < 3 {
 		return "", ""
 	}
-	id := fmt.Sprintf("REQ-%s", matches[1])
+	id := matches[1]
 	title := strings.TrimSpace(matches[2])
 	return id, title
 }
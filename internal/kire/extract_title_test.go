@@ -2,6 +2,8 @@ package kire
 
 import (
 	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
 func TestExtractReqIDWithTitle(t *testing.T) {
@@ -120,3 +122,19 @@ func containsCheck(s, sub string) bool {
 	}
 	return false
 }
+
+func TestExtractReqID_CustomScheme(t *testing.T) {
+	spec.SetIDScheme(spec.NewIDScheme([]string{"AUTH", "BILL"}, 3, ""))
+	t.Cleanup(func() { spec.SetIDScheme(spec.DefaultIDScheme()) })
+
+	id, title := ExtractReqIDWithTitle("### BILL-003: 請求書発行\n")
+	if id != "BILL-003" || title != "請求書発行" {
+		t.Errorf("ExtractReqIDWithTitle() = %q, %q", id, title)
+	}
+	if id, _ := ExtractReqIDWithTitle("### REQ-001: ログイン\n"); id != "" {
+		t.Errorf("REQ-001 is outside the scheme, got %q", id)
+	}
+	if got := ExtractReqID("AUTH-012 を参照"); got != "AUTH-012" {
+		t.Errorf("ExtractReqID() = %q", got)
+	}
+}
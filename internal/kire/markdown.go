@@ -0,0 +1,133 @@
+package kire
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+var (
+	atxHeadingPattern  = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	setextUnderlineH1  = regexp.MustCompile(`^\s{0,3}=+\s*$`)
+	setextUnderlineH2  = regexp.MustCompile(`^\s{0,3}-+\s*$`)
+	codeFenceDelimiter = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+)
+
+// ReadMarkdown reads a single Markdown document and splits it into segments.
+// It is the built-in alternative to kire's JSONL + segment files.
+func ReadMarkdown(path string) ([]*Segment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, apperrors.Wrap("kire.ReadMarkdown", fmt.Errorf("%s: %w", path, err))
+	}
+	return SegmentMarkdown(filepath.Base(path), string(data)), nil
+}
+
+// SegmentMarkdown splits Markdown content at ATX (`#`) and setext headings.
+// Each segment holds its heading line plus the body up to the next heading,
+// with HeadingPath set to the chain of enclosing headings. Headings inside
+// fenced code blocks are ignored, and segments without body text are dropped.
+//
+// Context is taken from a `<!-- context: ... -->` comment when present,
+// otherwise from the parent headings joined with " > ".
+// Content before the first heading is titled after the document name.
+func SegmentMarkdown(name, content string) []*Segment {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	docTitle := strings.TrimSuffix(name, filepath.Ext(name))
+
+	type section struct {
+		headingPath []string
+		heading     []string
+		body        []string
+	}
+
+	var sections []section
+	var stack []string // heading text per level (index 0 = level 1)
+	current := section{headingPath: []string{docTitle}}
+	inFence := false
+
+	startSection := func(level int, text string, headingLines []string) {
+		sections = append(sections, current)
+		if len(stack) >= level {
+			stack = stack[:level-1]
+		}
+		for len(stack) < level-1 {
+			stack = append(stack, "")
+		}
+		stack = append(stack, text)
+
+		path := make([]string, 0, len(stack))
+		for _, h := range stack {
+			if h != "" {
+				path = append(path, h)
+			}
+		}
+		current = section{headingPath: path, heading: headingLines}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if codeFenceDelimiter.MatchString(line) {
+			inFence = !inFence
+			current.body = append(current.body, line)
+			continue
+		}
+		if inFence {
+			current.body = append(current.body, line)
+			continue
+		}
+
+		if m := atxHeadingPattern.FindStringSubmatch(line); m != nil && strings.TrimSpace(m[2]) != "" {
+			startSection(len(m[1]), strings.TrimSpace(m[2]), []string{line})
+			continue
+		}
+
+		// Setext heading: non-blank paragraph line followed by === or ---
+		if strings.TrimSpace(line) != "" && i+1 < len(lines) && !strings.HasPrefix(strings.TrimSpace(line), "-") {
+			next := lines[i+1]
+			level := 0
+			if setextUnderlineH1.MatchString(next) {
+				level = 1
+			} else if setextUnderlineH2.MatchString(next) {
+				level = 2
+			}
+			if level > 0 {
+				startSection(level, strings.TrimSpace(line), []string{line, next})
+				i++
+				continue
+			}
+		}
+
+		current.body = append(current.body, line)
+	}
+	sections = append(sections, current)
+
+	var segments []*Segment
+	for _, sec := range sections {
+		if strings.TrimSpace(strings.Join(sec.body, "\n")) == "" {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.Join(append(sec.heading, sec.body...), "\n")) + "\n"
+		ctx := extractContext(text)
+		if ctx == "" && len(sec.headingPath) > 1 {
+			ctx = strings.Join(sec.headingPath[:len(sec.headingPath)-1], " > ")
+		}
+
+		segments = append(segments, &Segment{
+			Meta: SegmentMeta{
+				SegmentID:   fmt.Sprintf("seg-%04d", len(segments)),
+				HeadingPath: sec.headingPath,
+				FilePath:    name,
+			},
+			Content: text,
+			Context: ctx,
+		})
+	}
+
+	return segments
+}
@@ -0,0 +1,128 @@
+package kire
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSegmentMarkdown(t *testing.T) {
+	t.Run("splits at headings and builds heading paths", func(t *testing.T) {
+		content := "# Task API\n\n## Auth\n\n### REQ-001: Login\n\n- Given: user exists\n- When: login\n- Then: token\n\n### Logout\n\nLogout body.\n\n## Tasks\n\nTask body.\n"
+
+		segs := SegmentMarkdown("spec.md", content)
+
+		if len(segs) != 3 {
+			t.Fatalf("expected 3 segments, got %d", len(segs))
+		}
+
+		want := [][]string{
+			{"Task API", "Auth", "REQ-001: Login"},
+			{"Task API", "Auth", "Logout"},
+			{"Task API", "Tasks"},
+		}
+		for i, w := range want {
+			if strings.Join(segs[i].Meta.HeadingPath, "|") != strings.Join(w, "|") {
+				t.Errorf("segs[%d].HeadingPath = %v, want %v", i, segs[i].Meta.HeadingPath, w)
+			}
+			if segs[i].Meta.FilePath != "spec.md" {
+				t.Errorf("segs[%d].FilePath = %q, want %q", i, segs[i].Meta.FilePath, "spec.md")
+			}
+		}
+
+		if segs[0].Meta.SegmentID != "seg-0000" || segs[2].Meta.SegmentID != "seg-0002" {
+			t.Errorf("unexpected segment IDs: %q, %q", segs[0].Meta.SegmentID, segs[2].Meta.SegmentID)
+		}
+		if segs[0].Context != "Task API > Auth" {
+			t.Errorf("segs[0].Context = %q, want %q", segs[0].Context, "Task API > Auth")
+		}
+		if !strings.HasPrefix(segs[0].Content, "### REQ-001: Login") {
+			t.Errorf("segment content should start with heading, got %q", segs[0].Content)
+		}
+		if len(ExtractExamples(segs[0].Content)) != 1 {
+			t.Errorf("expected GWT example to stay in segment content")
+		}
+	})
+
+	t.Run("headings without body are dropped", func(t *testing.T) {
+		segs := SegmentMarkdown("doc.md", "# Doc\n## Empty\n\n## Full\n\nbody\n")
+		if len(segs) != 1 {
+			t.Fatalf("expected 1 segment, got %d", len(segs))
+		}
+		if segs[0].Meta.HeadingPath[len(segs[0].Meta.HeadingPath)-1] != "Full" {
+			t.Errorf("unexpected heading path: %v", segs[0].Meta.HeadingPath)
+		}
+	})
+
+	t.Run("preamble uses document name as heading", func(t *testing.T) {
+		segs := SegmentMarkdown("tasks-spec.md", "背景\nタスク管理APIを定義する。\n")
+		if len(segs) != 1 {
+			t.Fatalf("expected 1 segment, got %d", len(segs))
+		}
+		if len(segs[0].Meta.HeadingPath) != 1 || segs[0].Meta.HeadingPath[0] != "tasks-spec" {
+			t.Errorf("HeadingPath = %v, want [tasks-spec]", segs[0].Meta.HeadingPath)
+		}
+	})
+
+	t.Run("ignores headings inside code fences", func(t *testing.T) {
+		content := "# Doc\n\n```sh\n# not a heading\n```\n"
+		segs := SegmentMarkdown("doc.md", content)
+		if len(segs) != 1 {
+			t.Fatalf("expected 1 segment, got %d", len(segs))
+		}
+		if !strings.Contains(segs[0].Content, "# not a heading") {
+			t.Errorf("code fence content missing: %q", segs[0].Content)
+		}
+	})
+
+	t.Run("supports setext headings", func(t *testing.T) {
+		content := "Title\n=====\n\nSection\n-------\n\nbody\n"
+		segs := SegmentMarkdown("doc.md", content)
+		if len(segs) != 1 {
+			t.Fatalf("expected 1 segment, got %d", len(segs))
+		}
+		if strings.Join(segs[0].Meta.HeadingPath, "|") != "Title|Section" {
+			t.Errorf("HeadingPath = %v, want [Title Section]", segs[0].Meta.HeadingPath)
+		}
+	})
+
+	t.Run("context comment takes precedence", func(t *testing.T) {
+		content := "# Doc\n\n## Login\n\n<!-- context: 認証 -->\nbody\n"
+		segs := SegmentMarkdown("doc.md", content)
+		if len(segs) != 1 {
+			t.Fatalf("expected 1 segment, got %d", len(segs))
+		}
+		if segs[0].Context != "認証" {
+			t.Errorf("Context = %q, want %q", segs[0].Context, "認証")
+		}
+	})
+}
+
+func TestReadMarkdown(t *testing.T) {
+	t.Run("reads file and uses base name", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		path := filepath.Join(tmpDir, "spec.md")
+		if err := os.WriteFile(path, []byte("# A\n\nbody\n"), 0644); err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+
+		segs, err := ReadMarkdown(path)
+		if err != nil {
+			t.Fatalf("ReadMarkdown error: %v", err)
+		}
+		if len(segs) != 1 || segs[0].Meta.FilePath != "spec.md" {
+			t.Errorf("unexpected segments: %+v", segs)
+		}
+	})
+
+	t.Run("missing file returns error with path", func(t *testing.T) {
+		_, err := ReadMarkdown("/nonexistent/spec.md")
+		if err == nil {
+			t.Fatal("expected error for nonexistent file")
+		}
+		if !strings.Contains(err.Error(), "/nonexistent/spec.md") {
+			t.Errorf("error should contain file path, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Severity of a finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// rank orders severities from most to least severe.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// AtLeast reports whether s is as severe as other or more.
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() <= other.rank()
+}
+
+// ParseSeverity converts a config or flag value to a Severity.
+func ParseSeverity(v string) (Severity, error) {
+	switch Severity(v) {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return Severity(v), nil
+	}
+	return "", apperrors.New("lint.ParseSeverity", apperrors.ErrInvalidInput,
+		fmt.Sprintf("unknown severity %q (use error, warning or info)", v))
+}
+
+// Finding is a single lint problem.
+type Finding struct {
+	RuleID   string   `json:"ruleId"`
+	Severity Severity `json:"severity"`
+	SpecID   string   `json:"specId"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	// Field is the path of the offending field, e.g. "examples[E1].then".
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Rule is a lint check. Check returns findings with Field and Message set;
+// the linter fills in the rest.
+type Rule interface {
+	ID() string
+	Description() string
+	DefaultSeverity() Severity
+	Check(s *spec.Spec) []Finding
+}
+
+// RuleInfo describes an active rule and its effective severity.
+type RuleInfo struct {
+	ID          string
+	Description string
+	Severity    Severity
+}
+
+// Linter runs a set of rules over specs.
+type Linter struct {
+	rules    []Rule
+	severity map[string]Severity
+}
+
+// New builds a linter from the built-in rules and cfg.
+// Unknown rule IDs in cfg are rejected so typos do not silently disable nothing.
+func New(cfg config.LintConfig) (*Linter, error) {
+	return NewWithRules(Builtin(cfg), cfg)
+}
+
+// NewWithRules builds a linter from the given rules, applying cfg's disable
+// list and severity overrides.
+func NewWithRules(rules []Rule, cfg config.LintConfig) (*Linter, error) {
+	known := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		known[r.ID()] = true
+	}
+
+	disabled := make(map[string]bool, len(cfg.Disable))
+	for _, id := range cfg.Disable {
+		if !known[id] {
+			return nil, apperrors.New("lint.New", apperrors.ErrInvalidInput, fmt.Sprintf("unknown rule %q in lint.disable", id))
+		}
+		disabled[id] = true
+	}
+
+	l := &Linter{severity: make(map[string]Severity, len(rules))}
+	for _, r := range rules {
+		if disabled[r.ID()] {
+			continue
+		}
+		l.rules = append(l.rules, r)
+		l.severity[r.ID()] = r.DefaultSeverity()
+	}
+
+	ids := make([]string, 0, len(cfg.Severity))
+	for id := range cfg.Severity {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !known[id] {
+			return nil, apperrors.New("lint.New", apperrors.ErrInvalidInput, fmt.Sprintf("unknown rule %q in lint.severity", id))
+		}
+		sev, err := ParseSeverity(cfg.Severity[id])
+		if err != nil {
+			return nil, err
+		}
+		if _, active := l.severity[id]; active {
+			l.severity[id] = sev
+		}
+	}
+	return l, nil
+}
+
+// Rules returns the active rules with their effective severity.
+func (l *Linter) Rules() []RuleInfo {
+	out := make([]RuleInfo, 0, len(l.rules))
+	for _, r := range l.rules {
+		out = append(out, RuleInfo{ID: r.ID(), Description: r.Description(), Severity: l.severity[r.ID()]})
+	}
+	return out
+}
+
+// Lint runs all active rules over specs. Findings are ordered by spec,
+// then by rule order.
+func (l *Linter) Lint(specs []*spec.Spec) []Finding {
+	var out []Finding
+	for _, s := range specs {
+		for _, r := range l.rules {
+			for _, f := range r.Check(s) {
+				f.RuleID = r.ID()
+				f.Severity = l.severity[r.ID()]
+				f.SpecID = s.ID
+				out = append(out, f)
+			}
+		}
+	}
+	return out
+}
+
+// Count returns the number of findings at or above min severity.
+func Count(findings []Finding, min Severity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity.AtLeast(min) {
+			n++
+		}
+	}
+	return n
+}
+
+// field is a named text field of a spec.
+type field struct {
+	path string
+	text string
+}
+
+// textFields returns every free-text field of a spec with its field path.
+func textFields(s *spec.Spec) []field {
+	fields := []field{{"title", s.Title}}
+	if strings.TrimSpace(s.Description) != "" {
+		fields = append(fields, field{"description", s.Description})
+	}
+	for _, r := range s.Rules {
+		fields = append(fields, field{fmt.Sprintf("rules[%s].text", r.ID), r.Text})
+	}
+	for _, e := range examplesWithPath(s) {
+		fields = append(fields,
+			field{e.path + ".given", e.ex.Given},
+			field{e.path + ".when", e.ex.When},
+			field{e.path + ".then", e.ex.Then},
+		)
+	}
+	return fields
+}
+
+type pathedExample struct {
+	path string
+	ex   spec.Example
+}
+
+// examplesWithPath returns top-level and rule examples with their field path.
+func examplesWithPath(s *spec.Spec) []pathedExample {
+	var out []pathedExample
+	for i, ex := range s.Examples {
+		out = append(out, pathedExample{fmt.Sprintf("examples[%s]", selector(ex.ID, i)), ex})
+	}
+	for _, r := range s.Rules {
+		for i, ex := range r.Examples {
+			out = append(out, pathedExample{fmt.Sprintf("rules[%s].examples[%s]", r.ID, selector(ex.ID, i)), ex})
+		}
+	}
+	return out
+}
+
+// selector is the ID used in a field path, falling back to the index.
+func selector(id string, index int) string {
+	if id != "" {
+		return id
+	}
+	return fmt.Sprintf("%d", index)
+}
@@ -0,0 +1,232 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func findingsFor(t *testing.T, cfg config.LintConfig, s *spec.Spec) []Finding {
+	t.Helper()
+	l, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return l.Lint([]*spec.Spec{s})
+}
+
+func ruleFields(findings []Finding) []string {
+	out := make([]string, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, f.RuleID+" "+f.Field)
+	}
+	return out
+}
+
+func TestBuiltinRules(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *spec.Spec
+		want []string
+	}{
+		{
+			name: "clean spec",
+			spec: &spec.Spec{ID: "REQ-001", Title: "Login lockout", Examples: []spec.Example{
+				{ID: "E1", Given: "user with 4 failed logins", When: "login fails again", Then: "account locked for 30 minutes"},
+			}},
+			want: nil,
+		},
+		{
+			name: "vague words in English and Japanese",
+			spec: &spec.Spec{ID: "REQ-001", Title: "Search is fast", Description: "結果を適切に表示する"},
+			want: []string{"vague-words title", "vague-words description"},
+		},
+		{
+			name: "vague word inside another word is ignored",
+			spec: &spec.Spec{ID: "REQ-001", Title: "Breakfast menu"},
+			want: nil,
+		},
+		{
+			name: "unobservable then",
+			spec: &spec.Spec{ID: "REQ-001", Title: "Import", Examples: []spec.Example{
+				{ID: "E1", Given: "valid CSV file", When: "import runs", Then: "It works."},
+				{ID: "E2", Given: "空のCSVファイル", When: "取り込む", Then: "正常に処理される"},
+			}},
+			want: []string{"unobservable-then examples[E1].then", "unobservable-then examples[E2].then"},
+		},
+		{
+			name: "exact and near duplicates across rules",
+			spec: &spec.Spec{ID: "REQ-001", Title: "Withdraw",
+				Examples: []spec.Example{{ID: "E1", Given: "balance of 100", When: "withdraw 50", Then: "balance is 50"}},
+				Rules: []spec.Rule{{ID: "R1", Text: "Balance check", Examples: []spec.Example{
+					{ID: "E2", Given: "Balance of 100.", When: "withdraw 50", Then: "balance is 50"},
+					{ID: "E3", Given: "balance of 100", When: "withdraw 50", Then: "balance is 50!"},
+				}}},
+			},
+			want: []string{"duplicate-example rules[R1].examples[E2]", "duplicate-example rules[R1].examples[E3]"},
+		},
+		{
+			name: "long title and vague given",
+			spec: &spec.Spec{ID: "REQ-001", Title: strings.Repeat("x", 81), Examples: []spec.Example{
+				{ID: "E1", Given: "a user", When: "opens the page", Then: "title shows 'Home'"},
+			}},
+			want: []string{"title-length title", "vague-given examples[E1].given"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ruleFields(findingsFor(t, config.LintConfig{}, tt.spec))
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestNearDuplicateMessage(t *testing.T) {
+	s := &spec.Spec{ID: "REQ-001", Title: "Login", Examples: []spec.Example{
+		{ID: "E1", Given: "user has failed login 4 times", When: "user fails login again", Then: "account is locked for 30 minutes"},
+		{ID: "E2", Given: "user has failed login 4 times", When: "user fails login again", Then: "account is locked for 30 minute"},
+	}}
+	findings := findingsFor(t, config.LintConfig{}, s)
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, "nearly duplicates examples[E1]") {
+		t.Errorf("expected near-duplicate finding, got %+v", findings)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	s := &spec.Spec{ID: "REQ-001", Title: "Search is fast and snappy"}
+
+	t.Run("disable and severity override", func(t *testing.T) {
+		cfg := config.LintConfig{
+			Disable:        []string{RuleVagueWords},
+			Severity:       map[string]string{RuleTitleLength: "error"},
+			MaxTitleLength: 10,
+		}
+		findings := findingsFor(t, cfg, s)
+		if len(findings) != 1 || findings[0].RuleID != RuleTitleLength || findings[0].Severity != SeverityError {
+			t.Errorf("unexpected findings: %+v", findings)
+		}
+	})
+
+	t.Run("extra vague words", func(t *testing.T) {
+		findings := findingsFor(t, config.LintConfig{VagueWords: []string{"snappy"}}, s)
+		if len(findings) != 2 {
+			t.Errorf("expected fast and snappy, got %+v", findings)
+		}
+	})
+
+	t.Run("unknown rule IDs are rejected", func(t *testing.T) {
+		if _, err := New(config.LintConfig{Disable: []string{"no-such-rule"}}); err == nil {
+			t.Error("expected error for unknown rule in disable")
+		}
+		if _, err := New(config.LintConfig{Severity: map[string]string{"no-such-rule": "error"}}); err == nil {
+			t.Error("expected error for unknown rule in severity")
+		}
+	})
+}
+
+func TestLocate(t *testing.T) {
+	data := []byte(`id: REQ-001
+title: Withdraw
+examples:
+  - id: E1
+    given: a
+    when: b
+    then: c
+rules:
+  - id: R1
+    text: Balance check
+    examples:
+      - id: E2
+        given: a
+        when: b
+        then: c
+`)
+	tests := map[string]int{
+		"title":                       2,
+		"examples[E1].then":           7,
+		"rules[R1].text":              10,
+		"rules[R1].examples[E2]":      12,
+		"rules[R1].examples[E2].then": 15,
+		"examples[0].when":            6,
+		"description":                 0,
+	}
+	for path, want := range tests {
+		if got := Locate(data, path); got != want {
+			t.Errorf("Locate(%q) = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	l, err := New(config.LintConfig{})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	findings := []Finding{{RuleID: RuleVagueWords, Severity: SeverityWarning, SpecID: "REQ-001",
+		File: ".tdd/specs/REQ-001.yml", Line: 2, Field: "title", Message: `vague word "fast"`}}
+
+	var text bytes.Buffer
+	if err := Write(&text, FormatText, findings, l.Rules()); err != nil {
+		t.Fatalf("text error: %v", err)
+	}
+	if !strings.Contains(text.String(), `.tdd/specs/REQ-001.yml:2: warning [vague-words] title: vague word "fast"`) {
+		t.Errorf("unexpected text output: %s", text.String())
+	}
+
+	var js bytes.Buffer
+	if err := Write(&js, FormatJSON, findings, l.Rules()); err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+	var decoded []Finding
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil || len(decoded) != 1 || decoded[0].Line != 2 {
+		t.Errorf("unexpected json output: %s (%v)", js.String(), err)
+	}
+
+	var sarif bytes.Buffer
+	if err := Write(&sarif, FormatSARIF, findings, l.Rules()); err != nil {
+		t.Fatalf("sarif error: %v", err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil {
+		t.Fatalf("sarif is not valid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs[0].Tool.Driver.Rules) != 5 {
+		t.Errorf("unexpected sarif header: %s", sarif.String())
+	}
+	res := log.Runs[0].Results[0]
+	if res.RuleID != RuleVagueWords || res.Level != "warning" || res.Locations[0].PhysicalLocation.Region.StartLine != 2 {
+		t.Errorf("unexpected sarif result: %+v", res)
+	}
+
+	if err := Write(&text, "xml", findings, nil); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,245 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"go.yaml.in/yaml/v3"
+)
+
+// Output formats.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "spec-tdd"
+	toolURI      = "https://github.com/thirdlf03/spec-tdd"
+)
+
+// Write renders findings in the given format.
+func Write(w io.Writer, format string, findings []Finding, rules []RuleInfo) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, findings)
+	case FormatJSON:
+		return WriteJSON(w, findings)
+	case FormatSARIF:
+		return WriteSARIF(w, findings, rules)
+	}
+	return apperrors.New("lint.Write", apperrors.ErrInvalidInput,
+		fmt.Sprintf("unknown format %q (use text, json or sarif)", format))
+}
+
+// WriteText renders findings one per line, followed by a summary.
+func WriteText(w io.Writer, findings []Finding) error {
+	for _, f := range findings {
+		loc := f.File
+		if loc == "" {
+			loc = f.SpecID
+		}
+		if f.Line > 0 {
+			loc += ":" + strconv.Itoa(f.Line)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s [%s] %s: %s\n", loc, f.Severity, f.RuleID, f.Field, f.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d error(s), %d warning(s), %d info\n",
+		countExactly(findings, SeverityError), countExactly(findings, SeverityWarning), countExactly(findings, SeverityInfo))
+	return err
+}
+
+// WriteJSON renders findings as a JSON array.
+func WriteJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	data, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return apperrors.Wrap("lint.WriteJSON", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF renders findings as a SARIF 2.1.0 log for code scanning tools.
+func WriteSARIF(w io.Writer, findings []Finding, rules []RuleInfo) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolURI,
+			Rules:          make([]sarifRule, 0, len(rules)),
+		}},
+		Results: make([]sarifResult, 0, len(findings)),
+	}
+	for _, r := range rules {
+		sr := sarifRule{ID: r.ID, ShortDescription: sarifMessage{Text: r.Description}}
+		sr.DefaultConfiguration.Level = sarifLevel(r.Severity)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sr)
+	}
+	for _, f := range findings {
+		res := sarifResult{
+			RuleID:  f.RuleID,
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: fmt.Sprintf("%s %s: %s", f.SpecID, f.Field, f.Message)},
+		}
+		if f.File != "" {
+			var loc sarifLocation
+			loc.PhysicalLocation.ArtifactLocation.URI = strings.ReplaceAll(f.File, "\\", "/")
+			if f.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+			}
+			res.Locations = []sarifLocation{loc}
+		}
+		run.Results = append(run.Results, res)
+	}
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return apperrors.Wrap("lint.WriteSARIF", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func sarifLevel(s Severity) string {
+	if s == SeverityInfo {
+		return "note"
+	}
+	return string(s)
+}
+
+func countExactly(findings []Finding, sev Severity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+var segmentPattern = regexp.MustCompile(`^([A-Za-z_]+)(?:\[([^\]]+)\])?$`)
+
+// Locate returns the 1-based line of a field path such as
+// "rules[R1].examples[E2].then" in spec YAML, or 0 if it cannot be found.
+// A selector matches a sequence item by its id, or by index.
+func Locate(data []byte, fieldPath string) int {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0
+	}
+
+	node := doc.Content[0]
+	line := 0
+	for _, seg := range strings.Split(fieldPath, ".") {
+		m := segmentPattern.FindStringSubmatch(seg)
+		if m == nil || node.Kind != yaml.MappingNode {
+			return line
+		}
+		key, value := mappingEntry(node, m[1])
+		if value == nil {
+			return line
+		}
+		node, line = value, key.Line
+		if m[2] == "" {
+			continue
+		}
+		item := sequenceItem(node, m[2])
+		if item == nil {
+			return line
+		}
+		node, line = item, item.Line
+	}
+	return line
+}
+
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func sequenceItem(node *yaml.Node, sel string) *yaml.Node {
+	if node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		if _, id := mappingEntry(item, "id"); id != nil && id.Value == sel {
+			return item
+		}
+	}
+	if i, err := strconv.Atoi(sel); err == nil && i >= 0 && i < len(node.Content) {
+		return node.Content[i]
+	}
+	return nil
+}
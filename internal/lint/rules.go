@@ -0,0 +1,263 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Built-in rule IDs.
+const (
+	RuleVagueWords       = "vague-words"
+	RuleUnobservableThen = "unobservable-then"
+	RuleDuplicateExample = "duplicate-example"
+	RuleTitleLength      = "title-length"
+	RuleVagueGiven       = "vague-given"
+)
+
+// DefaultMaxTitleLength is the title-length limit when none is configured.
+const DefaultMaxTitleLength = 80
+
+// nearDuplicateThreshold is the bigram similarity above which two examples
+// are reported as near-duplicates.
+const nearDuplicateThreshold = 0.85
+
+var defaultVagueWords = []string{
+	"fast", "quickly", "appropriate", "appropriately", "properly", "user-friendly",
+	"easy", "easily", "efficient", "efficiently", "reasonable", "sufficient",
+	"robust", "flexible", "intuitive", "seamless", "as needed", "etc",
+	"適切", "迅速", "速やかに", "高速", "十分", "なるべく", "柔軟", "簡単",
+	"使いやすい", "直感的", "必要に応じて", "など",
+}
+
+var unobservableThen = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(it |the system |system )?(works|succeeds|is successful|is handled|is processed|is correct|is ok|behaves correctly|works correctly|works as expected)\.?$`),
+	regexp.MustCompile(`正しく動作|正常に動作|正常に処理|問題なく|適切に処理|うまくいく|期待通り`),
+}
+
+var genericGivens = map[string]bool{
+	"user": true, "a user": true, "the user": true, "system": true, "the system": true,
+	"data": true, "some data": true, "none": true, "n/a": true, "-": true,
+	"default": true, "initial state": true, "nothing": true,
+	"ユーザー": true, "ユーザ": true, "システム": true, "データ": true,
+	"何らかのデータ": true, "なし": true, "特になし": true, "初期状態": true,
+}
+
+// Builtin returns the built-in rules configured by cfg.
+func Builtin(cfg config.LintConfig) []Rule {
+	maxTitle := cfg.MaxTitleLength
+	if maxTitle == 0 {
+		maxTitle = DefaultMaxTitleLength
+	}
+	words := append(append([]string(nil), defaultVagueWords...), cfg.VagueWords...)
+	return []Rule{
+		newVagueWordsRule(words),
+		unobservableThenRule{},
+		duplicateExampleRule{},
+		titleLengthRule{max: maxTitle},
+		vagueGivenRule{},
+	}
+}
+
+// vagueWordsRule flags words that cannot be tested, such as "fast" or 「適切に」.
+type vagueWordsRule struct {
+	words    []string
+	patterns []*regexp.Regexp
+}
+
+func newVagueWordsRule(words []string) vagueWordsRule {
+	r := vagueWordsRule{}
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		var p *regexp.Regexp
+		if isASCII(w) {
+			p = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(w) + `\b`)
+		} else {
+			p = regexp.MustCompile(regexp.QuoteMeta(w))
+		}
+		r.words = append(r.words, w)
+		r.patterns = append(r.patterns, p)
+	}
+	return r
+}
+
+func (vagueWordsRule) ID() string                { return RuleVagueWords }
+func (vagueWordsRule) DefaultSeverity() Severity { return SeverityWarning }
+func (vagueWordsRule) Description() string {
+	return "Requirement and example text must not use vague, untestable words"
+}
+
+func (r vagueWordsRule) Check(s *spec.Spec) []Finding {
+	var out []Finding
+	for _, f := range textFields(s) {
+		for i, p := range r.patterns {
+			if p.MatchString(f.text) {
+				out = append(out, Finding{Field: f.path,
+					Message: fmt.Sprintf("vague word %q; replace it with a measurable condition", r.words[i])})
+			}
+		}
+	}
+	return out
+}
+
+// unobservableThenRule flags Then clauses that do not describe a visible outcome.
+type unobservableThenRule struct{}
+
+func (unobservableThenRule) ID() string                { return RuleUnobservableThen }
+func (unobservableThenRule) DefaultSeverity() Severity { return SeverityWarning }
+func (unobservableThenRule) Description() string {
+	return "Then clauses must describe an observable outcome"
+}
+
+func (unobservableThenRule) Check(s *spec.Spec) []Finding {
+	var out []Finding
+	for _, e := range examplesWithPath(s) {
+		then := strings.TrimSpace(e.ex.Then)
+		for _, p := range unobservableThen {
+			if p.MatchString(then) {
+				out = append(out, Finding{Field: e.path + ".then",
+					Message: fmt.Sprintf("%q is not observable; state the response, message or stored data", then)})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// duplicateExampleRule flags examples that repeat another example of the
+// same spec, exactly or nearly.
+type duplicateExampleRule struct{}
+
+func (duplicateExampleRule) ID() string                { return RuleDuplicateExample }
+func (duplicateExampleRule) DefaultSeverity() Severity { return SeverityWarning }
+func (duplicateExampleRule) Description() string {
+	return "Examples of a requirement must not duplicate each other"
+}
+
+func (duplicateExampleRule) Check(s *spec.Spec) []Finding {
+	examples := examplesWithPath(s)
+	keys := make([]string, len(examples))
+	grams := make([]map[string]bool, len(examples))
+	for i, e := range examples {
+		keys[i] = normalizeText(e.ex.Given + " | " + e.ex.When + " | " + e.ex.Then)
+		grams[i] = bigrams(keys[i])
+	}
+
+	var out []Finding
+	for j := range examples {
+		for i := 0; i < j; i++ {
+			if keys[i] == keys[j] {
+				out = append(out, Finding{Field: examples[j].path,
+					Message: fmt.Sprintf("duplicates %s", examples[i].path)})
+				break
+			}
+			if sim := jaccard(grams[i], grams[j]); sim >= nearDuplicateThreshold {
+				out = append(out, Finding{Field: examples[j].path,
+					Message: fmt.Sprintf("nearly duplicates %s (%.0f%% similar)", examples[i].path, sim*100)})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// titleLengthRule flags titles longer than the configured limit.
+type titleLengthRule struct {
+	max int
+}
+
+func (titleLengthRule) ID() string                { return RuleTitleLength }
+func (titleLengthRule) DefaultSeverity() Severity { return SeverityInfo }
+func (r titleLengthRule) Description() string {
+	return fmt.Sprintf("Titles must be at most %d characters", r.max)
+}
+
+func (r titleLengthRule) Check(s *spec.Spec) []Finding {
+	if n := utf8.RuneCountInString(s.Title); n > r.max {
+		return []Finding{{Field: "title",
+			Message: fmt.Sprintf("title is %d characters (max %d); move details to description", n, r.max)}}
+	}
+	return nil
+}
+
+// vagueGivenRule flags Given clauses that do not name a concrete state.
+type vagueGivenRule struct{}
+
+func (vagueGivenRule) ID() string                { return RuleVagueGiven }
+func (vagueGivenRule) DefaultSeverity() Severity { return SeverityWarning }
+func (vagueGivenRule) Description() string {
+	return "Given clauses must describe a concrete starting state"
+}
+
+func (vagueGivenRule) Check(s *spec.Spec) []Finding {
+	var out []Finding
+	for _, e := range examplesWithPath(s) {
+		given := strings.TrimSpace(e.ex.Given)
+		key := strings.TrimRight(strings.ToLower(given), ".。")
+		if genericGivens[key] || utf8.RuneCountInString(given) < 3 {
+			out = append(out, Finding{Field: e.path + ".given",
+				Message: fmt.Sprintf("%q has no concrete state; name the data, role or setting involved", given)})
+		}
+	}
+	return out
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeText lowercases and strips punctuation and spacing differences.
+func normalizeText(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '|':
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// bigrams returns the character bigrams of s, which works for text with and
+// without word separators.
+func bigrams(s string) map[string]bool {
+	runes := []rune(s)
+	out := make(map[string]bool, len(runes))
+	for i := 0; i+1 < len(runes); i++ {
+		out[string(runes[i:i+2])] = true
+	}
+	return out
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for k := range a {
+		if b[k] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
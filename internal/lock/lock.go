@@ -0,0 +1,83 @@
+// Package lock provides an advisory, inter-process lock on a file, used to
+// serialize commands that modify the workspace. The operating system drops
+// the lock when the process exits, so a crashed command never leaves the
+// workspace locked.
+package lock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+// pollInterval is how often Acquire retries a held lock.
+const pollInterval = 50 * time.Millisecond
+
+// Lock is a held lock.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire takes the lock at path, waiting up to timeout for another process
+// to release it. The lock file records the holder's PID for error messages.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, apperrors.Wrap("lock.Acquire", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, apperrors.Wrap("lock.Acquire", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, apperrors.Wrap("lock.Acquire", err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			holder := readHolder(path)
+			f.Close()
+			return nil, apperrors.New("lock.Acquire", apperrors.ErrConflict,
+				fmt.Sprintf("%s is held by another spec-tdd process%s; retry when it has finished", path, holder))
+		}
+		time.Sleep(pollInterval)
+	}
+
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release releases the lock. It is safe to call on a nil Lock and more than
+// once.
+func (l *Lock) Release() error {
+	if l == nil || l.f == nil {
+		return nil
+	}
+	_ = unlock(l.f)
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+func readHolder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if pid := strings.TrimSpace(string(data)); pid != "" {
+		return " (pid " + pid + ")"
+	}
+	return ""
+}
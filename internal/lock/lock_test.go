@@ -0,0 +1,42 @@
+//go:build unix || windows
+
+package lock
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+func TestAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".tdd", "lock")
+
+	l, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire error: %v", err)
+	}
+
+	_, err = Acquire(path, 100*time.Millisecond)
+	if !apperrors.IsConflict(err) {
+		t.Fatalf("second Acquire error = %v, want a conflict", err)
+	}
+	if !strings.Contains(err.Error(), "pid ") {
+		t.Errorf("error should name the holder: %v", err)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release error: %v", err)
+	}
+	if err := l.Release(); err != nil {
+		t.Errorf("second Release error: %v", err)
+	}
+
+	l2, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire after Release error: %v", err)
+	}
+	_ = l2.Release()
+}
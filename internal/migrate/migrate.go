@@ -0,0 +1,110 @@
+// Package migrate upgrades versioned YAML files (specs and .tdd/config.yml)
+// with ordered steps over yaml.Node, so comments and formatting survive.
+package migrate
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/yamlnode"
+	"go.yaml.in/yaml/v3"
+)
+
+// VersionKey is the top-level key holding the format version. A file without
+// it is at version 1.
+const VersionKey = "version"
+
+// Step upgrades a document to version To. Apply receives the top-level
+// mapping and reports whether it changed anything. Steps must be idempotent:
+// applying one to an already upgraded document changes nothing.
+type Step struct {
+	To          int
+	Description string
+	Apply       func(m *yaml.Node) (bool, error)
+}
+
+// Result describes the migration of one file.
+type Result struct {
+	From    int
+	To      int
+	Applied []Step
+	Before  []byte
+	After   []byte
+}
+
+// Changed reports whether the file content changes.
+func (r Result) Changed() bool {
+	return string(r.Before) != string(r.After)
+}
+
+// Version returns the format version of data (1 when it has none).
+func Version(data []byte) (int, error) {
+	root := yamlnode.Parse(data)
+	if root == nil {
+		return 0, apperrors.New("migrate.Version", apperrors.ErrInvalidInput, "not a YAML mapping")
+	}
+	return version(root.Content[0])
+}
+
+func version(m *yaml.Node) (int, error) {
+	v := yamlnode.Get(m, VersionKey)
+	if v == nil {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(v.Value)
+	if err != nil || n < 1 {
+		return 0, apperrors.New("migrate.Version", apperrors.ErrInvalidInput, fmt.Sprintf("version %q must be a positive integer", v.Value))
+	}
+	return n, nil
+}
+
+// CheckVersion returns an error when a file at version v is newer than
+// current, the newest version this binary understands.
+func CheckVersion(v, current int) error {
+	if v > current {
+		return apperrors.New("migrate.CheckVersion", apperrors.ErrInvalidInput,
+			fmt.Sprintf("format version %d is newer than this spec-tdd supports (%d); upgrade spec-tdd", v, current))
+	}
+	return nil
+}
+
+// Run applies the steps newer than data's version, in order, and stamps the
+// result with current. Files already at current are returned unchanged.
+func Run(data []byte, steps []Step, current int) (Result, error) {
+	root := yamlnode.Parse(data)
+	if root == nil {
+		return Result{}, apperrors.New("migrate.Run", apperrors.ErrInvalidInput, "not a YAML mapping")
+	}
+	m := root.Content[0]
+	from, err := version(m)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := CheckVersion(from, current); err != nil {
+		return Result{}, err
+	}
+	res := Result{From: from, To: from, Before: data, After: data}
+	if from == current {
+		return res, nil
+	}
+
+	for _, step := range steps {
+		if step.To <= from || step.To > current {
+			continue
+		}
+		if _, err := step.Apply(m); err != nil {
+			return Result{}, apperrors.Wrapf("migrate.Run", err, "step to version %d", step.To)
+		}
+		res.Applied = append(res.Applied, step)
+	}
+	yamlnode.SetFirst(m, VersionKey, yamlnode.Scalar("!!int", strconv.Itoa(current)))
+	res.To = current
+
+	out, err := yamlnode.Encode(root, yamlnode.DetectIndent(data))
+	if err != nil {
+		return Result{}, apperrors.Wrap("migrate.Run", err)
+	}
+	res.After = out
+	return res, nil
+}
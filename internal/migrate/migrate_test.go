@@ -0,0 +1,127 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/yamlnode"
+	"go.yaml.in/yaml/v3"
+)
+
+func TestRun(t *testing.T) {
+	var calls []int
+	step := func(to int, key string) Step {
+		return Step{To: to, Description: "add " + key, Apply: func(m *yaml.Node) (bool, error) {
+			calls = append(calls, to)
+			if yamlnode.Get(m, key) != nil {
+				return false, nil
+			}
+			m.Content = append(m.Content, yamlnode.Scalar("!!str", key), yamlnode.Scalar("!!bool", "true"))
+			return true, nil
+		}}
+	}
+	steps := []Step{step(2, "two"), step(3, "three")}
+
+	tests := []struct {
+		name      string
+		input     string
+		current   int
+		wantFrom  int
+		wantCalls []int
+		want      string
+	}{
+		{
+			name:      "unversioned file runs every step",
+			input:     "# Login\ntitle: x # short\n",
+			current:   3,
+			wantFrom:  1,
+			wantCalls: []int{2, 3},
+			want:      "# Login\nversion: 3\ntitle: x # short\ntwo: true\nthree: true\n",
+		},
+		{
+			name:      "only newer steps run",
+			input:     "version: 2\ntitle: x\n",
+			current:   3,
+			wantFrom:  2,
+			wantCalls: []int{3},
+			want:      "version: 3\ntitle: x\nthree: true\n",
+		},
+		{
+			name:      "steps beyond current are skipped",
+			input:     "title: x\n",
+			current:   2,
+			wantFrom:  1,
+			wantCalls: []int{2},
+			want:      "version: 2\ntitle: x\ntwo: true\n",
+		},
+		{
+			name:     "current file is unchanged",
+			input:    "version: 3\ntitle:   x\n",
+			current:  3,
+			wantFrom: 3,
+			want:     "version: 3\ntitle:   x\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			res, err := Run([]byte(tt.input), steps, tt.current)
+			if err != nil {
+				t.Fatalf("Run error: %v", err)
+			}
+			if res.From != tt.wantFrom || res.To != tt.current {
+				t.Errorf("versions = %d -> %d, want %d -> %d", res.From, res.To, tt.wantFrom, tt.current)
+			}
+			if len(res.Applied) != len(tt.wantCalls) || len(calls) != len(tt.wantCalls) {
+				t.Errorf("applied %d step(s), called %v, want %v", len(res.Applied), calls, tt.wantCalls)
+			}
+			if string(res.After) != tt.want {
+				t.Errorf("output = %q, want %q", res.After, tt.want)
+			}
+			if res.Changed() != (tt.input != tt.want) {
+				t.Errorf("Changed() = %v", res.Changed())
+			}
+
+			again, err := Run(res.After, steps, tt.current)
+			if err != nil {
+				t.Fatalf("second Run error: %v", err)
+			}
+			if again.Changed() {
+				t.Errorf("second Run changed the file:\n%s", again.After)
+			}
+		})
+	}
+}
+
+func TestRunRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"newer version", "version: 4\n", "upgrade spec-tdd"},
+		{"bad version", "version: two\n", "positive integer"},
+		{"not a mapping", "- a\n", "not a YAML mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Run([]byte(tt.input), nil, 3)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+			if !apperrors.IsInvalidInput(err) {
+				t.Errorf("expected invalid input error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	for input, want := range map[string]int{"title: x\n": 1, "version: 2\n": 2} {
+		got, err := Version([]byte(input))
+		if err != nil || got != want {
+			t.Errorf("Version(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Endpoint returns the "METHOD /path" form of the operation.
+func (op Operation) Endpoint() string {
+	return fmt.Sprintf("%s %s", op.Method, op.Path)
+}
+
+// Title returns the summary, falling back to operationId and the endpoint.
+func (op Operation) Title() string {
+	if s := strings.TrimSpace(op.Summary); s != "" {
+		return s
+	}
+	if s := strings.TrimSpace(op.OperationID); s != "" {
+		return s
+	}
+	return op.Endpoint()
+}
+
+// SpecTags returns the operation's own tags followed by path and method tags.
+func (op Operation) SpecTags() []string {
+	tags := make([]string, 0, len(op.Tags)+2)
+	tags = append(tags, op.Tags...)
+	tags = append(tags, "path:"+op.Path, "method:"+strings.ToLower(op.Method))
+	return tags
+}
+
+// ConvertToSpec converts an operation into a spec with the given ID.
+// sourceFile is recorded in SourceInfo.FilePath.
+func ConvertToSpec(op Operation, id, sourceFile string) *spec.Spec {
+	return &spec.Spec{
+		ID:          id,
+		Title:       op.Title(),
+		Description: strings.TrimSpace(op.Description),
+		Tags:        op.SpecTags(),
+		Examples:    BuildExamples(op),
+		Source: spec.SourceInfo{
+			OperationID: op.OperationID,
+			HeadingPath: []string{op.Endpoint()},
+			FilePath:    sourceFile,
+		},
+	}
+}
+
+// BuildExamples derives Given/When/Then examples from documented responses.
+// Each named response example becomes its own example; a response without
+// examples yields one example describing its status code. A request example
+// with the same name as a response example is used as the Given clause.
+func BuildExamples(op Operation) []spec.Example {
+	requestExamples := map[string]string{}
+	hasBody := op.RequestBody != nil
+	if hasBody {
+		for _, mt := range sortedMedia(op.RequestBody.Content) {
+			for name, ex := range mt.Examples {
+				if _, ok := requestExamples[name]; !ok {
+					requestExamples[name] = compactJSON(ex.Value)
+				}
+			}
+			if mt.Example != nil {
+				if _, ok := requestExamples[""]; !ok {
+					requestExamples[""] = compactJSON(mt.Example)
+				}
+			}
+		}
+	}
+
+	when := op.Endpoint()
+	var examples []spec.Example
+	for _, code := range sortedCodes(op.Responses) {
+		resp := op.Responses[code]
+		status := code
+		if d := strings.TrimSpace(resp.Description); d != "" {
+			status = fmt.Sprintf("%s (%s)", code, d)
+		}
+
+		defaultGiven := givenForStatus(code, resp.Description, hasBody, requestExamples[""])
+		added := false
+		for _, mt := range sortedMedia(resp.Content) {
+			names := make([]string, 0, len(mt.Examples))
+			for name := range mt.Examples {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				ex := mt.Examples[name]
+				given := defaultGiven
+				if body, ok := requestExamples[name]; ok {
+					given = "request body " + body
+				} else if s := strings.TrimSpace(ex.Summary); s != "" {
+					given = s
+				}
+				examples = append(examples, spec.Example{
+					Given: given,
+					When:  when,
+					Then:  fmt.Sprintf("%s with body %s", status, compactJSON(ex.Value)),
+				})
+				added = true
+			}
+			if mt.Example != nil {
+				examples = append(examples, spec.Example{
+					Given: defaultGiven,
+					When:  when,
+					Then:  fmt.Sprintf("%s with body %s", status, compactJSON(mt.Example)),
+				})
+				added = true
+			}
+		}
+
+		if !added {
+			examples = append(examples, spec.Example{
+				Given: defaultGiven,
+				When:  when,
+				Then:  status,
+			})
+		}
+	}
+
+	for i := range examples {
+		examples[i].ID = fmt.Sprintf("E%d", i+1)
+	}
+	return examples
+}
+
+// givenForStatus describes the precondition for a response without a named
+// request example. Successful responses reuse the default request example.
+func givenForStatus(code, description string, hasBody bool, requestExample string) string {
+	if isSuccess(code) {
+		if requestExample != "" {
+			return "valid body " + requestExample
+		}
+		if hasBody {
+			return "valid body"
+		}
+		return "valid request"
+	}
+	if d := strings.TrimSpace(description); d != "" {
+		return d
+	}
+	if hasBody {
+		return "invalid body"
+	}
+	return "invalid request"
+}
+
+func isSuccess(code string) bool {
+	return strings.HasPrefix(code, "2")
+}
+
+// sortedCodes orders status codes numerically with "default" last.
+func sortedCodes(responses map[string]Response) []string {
+	codes := make([]string, 0, len(responses))
+	for c := range responses {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i] == "default" || codes[j] == "default" {
+			return codes[j] == "default" && codes[i] != "default"
+		}
+		return codes[i] < codes[j]
+	})
+	return codes
+}
+
+// sortedMedia returns media types ordered by content type, JSON first.
+func sortedMedia(content map[string]MediaType) []MediaType {
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ji, jj := strings.Contains(keys[i], "json"), strings.Contains(keys[j], "json")
+		if ji != jj {
+			return ji
+		}
+		return keys[i] < keys[j]
+	})
+	out := make([]MediaType, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, content[k])
+	}
+	return out
+}
+
+func compactJSON(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildExamples(t *testing.T) {
+	t.Run("one example per status code without response examples", func(t *testing.T) {
+		op := Operation{
+			Method:      "POST",
+			Path:        "/v1/tasks",
+			RequestBody: &RequestBody{},
+			Responses: map[string]Response{
+				"422":     {Description: "Validation error"},
+				"201":     {Description: "Created"},
+				"default": {},
+			},
+		}
+
+		examples := BuildExamples(op)
+		if len(examples) != 3 {
+			t.Fatalf("expected 3 examples, got %d", len(examples))
+		}
+
+		first := examples[0]
+		if first.ID != "E1" || first.Given != "valid body" || first.When != "POST /v1/tasks" || first.Then != "201 (Created)" {
+			t.Errorf("unexpected first example: %+v", first)
+		}
+		if examples[1].Given != "Validation error" || !strings.HasPrefix(examples[1].Then, "422") {
+			t.Errorf("unexpected second example: %+v", examples[1])
+		}
+		if examples[2].Then != "default" || examples[2].Given != "invalid body" {
+			t.Errorf("default response should be last: %+v", examples[2])
+		}
+	})
+
+	t.Run("named examples pair request and response by name", func(t *testing.T) {
+		op := Operation{
+			Method: "POST",
+			Path:   "/v1/tasks",
+			RequestBody: &RequestBody{Content: map[string]MediaType{
+				"application/json": {Examples: map[string]ExampleObj{
+					"minimal": {Value: map[string]any{"title": "a"}},
+				}},
+			}},
+			Responses: map[string]Response{
+				"201": {Description: "Created", Content: map[string]MediaType{
+					"application/json": {Examples: map[string]ExampleObj{
+						"minimal": {Value: map[string]any{"id": "T1", "title": "a"}},
+						"other":   {Summary: "task with tags", Value: map[string]any{"id": "T2"}},
+					}},
+				}},
+			},
+		}
+
+		examples := BuildExamples(op)
+		if len(examples) != 2 {
+			t.Fatalf("expected 2 examples, got %d", len(examples))
+		}
+		if examples[0].Given != `request body {"title":"a"}` {
+			t.Errorf("Given = %q", examples[0].Given)
+		}
+		if examples[0].Then != `201 (Created) with body {"id":"T1","title":"a"}` {
+			t.Errorf("Then = %q", examples[0].Then)
+		}
+		if examples[1].Given != "task with tags" {
+			t.Errorf("Given = %q, want summary", examples[1].Given)
+		}
+	})
+}
+
+func TestConvertToSpec(t *testing.T) {
+	op := Operation{
+		OperationID: "createTask",
+		Summary:     "Create a task",
+		Tags:        []string{"tasks"},
+		Method:      "POST",
+		Path:        "/v1/tasks",
+		Responses:   map[string]Response{"201": {Description: "Created"}},
+	}
+
+	s := ConvertToSpec(op, "REQ-004", "api.yaml")
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if s.Title != "Create a task" {
+		t.Errorf("Title = %q", s.Title)
+	}
+	if s.Source.OperationID != "createTask" || s.Source.FilePath != "api.yaml" {
+		t.Errorf("unexpected source: %+v", s.Source)
+	}
+	if strings.Join(s.Tags, ",") != "tasks,path:/v1/tasks,method:post" {
+		t.Errorf("Tags = %v", s.Tags)
+	}
+
+	op.Summary = ""
+	op.OperationID = ""
+	if got := ConvertToSpec(op, "REQ-004", "api.yaml").Title; got != "POST /v1/tasks" {
+		t.Errorf("fallback Title = %q", got)
+	}
+}
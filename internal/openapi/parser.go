@@ -0,0 +1,200 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"go.yaml.in/yaml/v3"
+)
+
+// methodOrder is the order in which operations of a path item are listed.
+var methodOrder = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Document is the subset of an OpenAPI 3 document used for spec import.
+type Document struct {
+	OpenAPI    string              `yaml:"openapi"`
+	Paths      map[string]PathItem `yaml:"paths"`
+	Components Components          `yaml:"components"`
+}
+
+// Components holds reusable objects referenced via $ref.
+type Components struct {
+	Responses     map[string]Response    `yaml:"responses"`
+	RequestBodies map[string]RequestBody `yaml:"requestBodies"`
+	Examples      map[string]ExampleObj  `yaml:"examples"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]yaml.Node
+
+// Operation describes a single API operation.
+type Operation struct {
+	OperationID string              `yaml:"operationId"`
+	Summary     string              `yaml:"summary"`
+	Description string              `yaml:"description"`
+	Tags        []string            `yaml:"tags"`
+	RequestBody *RequestBody        `yaml:"requestBody"`
+	Responses   map[string]Response `yaml:"responses"`
+	ReqID       string              `yaml:"x-req-id"`
+
+	// Method and Path are filled in by Operations.
+	Method string `yaml:"-"`
+	Path   string `yaml:"-"`
+}
+
+// RequestBody describes an operation's request body.
+type RequestBody struct {
+	Ref         string               `yaml:"$ref"`
+	Description string               `yaml:"description"`
+	Required    bool                 `yaml:"required"`
+	Content     map[string]MediaType `yaml:"content"`
+}
+
+// Response describes a documented response.
+type Response struct {
+	Ref         string               `yaml:"$ref"`
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content"`
+}
+
+// MediaType holds the example payloads for one content type.
+type MediaType struct {
+	Example  any                   `yaml:"example"`
+	Examples map[string]ExampleObj `yaml:"examples"`
+}
+
+// ExampleObj is an OpenAPI Example Object.
+type ExampleObj struct {
+	Ref     string `yaml:"$ref"`
+	Summary string `yaml:"summary"`
+	Value   any    `yaml:"value"`
+}
+
+// Load reads an OpenAPI 3 document in YAML or JSON format.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, apperrors.Wrap("openapi.Load", fmt.Errorf("%s: %w", path, err))
+	}
+	return Parse(data)
+}
+
+// Parse decodes an OpenAPI 3 document. JSON input is accepted because it is
+// valid YAML.
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, apperrors.Wrap("openapi.Parse", err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(doc.OpenAPI), "3.") {
+		return nil, apperrors.New("openapi.Parse", apperrors.ErrInvalidInput,
+			fmt.Sprintf("unsupported openapi version %q (3.x required)", doc.OpenAPI))
+	}
+	return &doc, nil
+}
+
+// Operations returns all operations sorted by path, then by HTTP method.
+// Request bodies and responses given as $ref are resolved from components.
+func (d *Document) Operations() ([]Operation, error) {
+	paths := make([]string, 0, len(d.Paths))
+	for p := range d.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var ops []Operation
+	for _, p := range paths {
+		item := d.Paths[p]
+		for _, method := range methodOrder {
+			node, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op Operation
+			if err := node.Decode(&op); err != nil {
+				return nil, apperrors.Wrap("openapi.Operations", fmt.Errorf("%s %s: %w", strings.ToUpper(method), p, err))
+			}
+			op.Method = strings.ToUpper(method)
+			op.Path = p
+			if err := d.resolve(&op); err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+func (d *Document) resolve(op *Operation) error {
+	if op.RequestBody != nil && op.RequestBody.Ref != "" {
+		name, err := refName(op.RequestBody.Ref, "requestBodies")
+		if err != nil {
+			return err
+		}
+		rb, ok := d.Components.RequestBodies[name]
+		if !ok {
+			return unresolvedRef(op.RequestBody.Ref)
+		}
+		op.RequestBody = &rb
+	}
+	if op.RequestBody != nil {
+		if err := d.resolveExamples(op.RequestBody.Content); err != nil {
+			return err
+		}
+	}
+
+	for code, resp := range op.Responses {
+		if resp.Ref != "" {
+			name, err := refName(resp.Ref, "responses")
+			if err != nil {
+				return err
+			}
+			r, ok := d.Components.Responses[name]
+			if !ok {
+				return unresolvedRef(resp.Ref)
+			}
+			resp = r
+		}
+		if err := d.resolveExamples(resp.Content); err != nil {
+			return err
+		}
+		op.Responses[code] = resp
+	}
+	return nil
+}
+
+func (d *Document) resolveExamples(content map[string]MediaType) error {
+	for _, mt := range content {
+		for name, ex := range mt.Examples {
+			if ex.Ref == "" {
+				continue
+			}
+			refN, err := refName(ex.Ref, "examples")
+			if err != nil {
+				return err
+			}
+			resolved, ok := d.Components.Examples[refN]
+			if !ok {
+				return unresolvedRef(ex.Ref)
+			}
+			mt.Examples[name] = resolved
+		}
+	}
+	return nil
+}
+
+func refName(ref, kind string) (string, error) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", apperrors.New("openapi.resolve", apperrors.ErrInvalidInput,
+			fmt.Sprintf("unsupported $ref %q (only local %s are supported)", ref, prefix))
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+func unresolvedRef(ref string) error {
+	return apperrors.New("openapi.resolve", apperrors.ErrNotFound, fmt.Sprintf("$ref %q", ref))
+}
@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDoc = `openapi: 3.0.3
+info:
+  title: Tasks
+  version: "1"
+paths:
+  /v1/tasks:
+    post:
+      operationId: createTask
+      summary: Create a task
+      tags: [tasks]
+      requestBody:
+        $ref: '#/components/requestBodies/TaskBody'
+      responses:
+        "201":
+          description: Created
+        "422":
+          $ref: '#/components/responses/Invalid'
+    get:
+      operationId: listTasks
+      responses:
+        "200":
+          description: OK
+components:
+  requestBodies:
+    TaskBody:
+      required: true
+      content:
+        application/json:
+          example: {title: "Buy milk"}
+  responses:
+    Invalid:
+      description: Validation error
+`
+
+func TestParse(t *testing.T) {
+	t.Run("parses operations sorted by path and method", func(t *testing.T) {
+		doc, err := Parse([]byte(testDoc))
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		ops, err := doc.Operations()
+		if err != nil {
+			t.Fatalf("Operations error: %v", err)
+		}
+		if len(ops) != 2 {
+			t.Fatalf("expected 2 operations, got %d", len(ops))
+		}
+		if ops[0].Endpoint() != "GET /v1/tasks" || ops[1].Endpoint() != "POST /v1/tasks" {
+			t.Errorf("unexpected order: %s, %s", ops[0].Endpoint(), ops[1].Endpoint())
+		}
+	})
+
+	t.Run("resolves component references", func(t *testing.T) {
+		doc, err := Parse([]byte(testDoc))
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		ops, err := doc.Operations()
+		if err != nil {
+			t.Fatalf("Operations error: %v", err)
+		}
+		post := ops[1]
+		if post.RequestBody == nil || !post.RequestBody.Required {
+			t.Fatalf("request body not resolved: %+v", post.RequestBody)
+		}
+		if post.Responses["422"].Description != "Validation error" {
+			t.Errorf("response not resolved: %+v", post.Responses["422"])
+		}
+	})
+
+	t.Run("accepts JSON documents", func(t *testing.T) {
+		data := `{"openapi":"3.1.0","paths":{"/ping":{"get":{"operationId":"ping","responses":{"204":{"description":"No Content"}}}}}}`
+		doc, err := Parse([]byte(data))
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		ops, err := doc.Operations()
+		if err != nil {
+			t.Fatalf("Operations error: %v", err)
+		}
+		if len(ops) != 1 || ops[0].OperationID != "ping" {
+			t.Errorf("unexpected operations: %+v", ops)
+		}
+	})
+
+	t.Run("rejects non-3.x documents", func(t *testing.T) {
+		_, err := Parse([]byte("swagger: \"2.0\"\npaths: {}\n"))
+		if err == nil {
+			t.Fatal("expected error for swagger 2.0")
+		}
+	})
+
+	t.Run("unresolved reference returns error", func(t *testing.T) {
+		data := `openapi: 3.0.0
+paths:
+  /x:
+    get:
+      responses:
+        "200":
+          $ref: '#/components/responses/Missing'
+`
+		doc, err := Parse([]byte(data))
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		if _, err := doc.Operations(); err == nil || !strings.Contains(err.Error(), "Missing") {
+			t.Errorf("expected unresolved $ref error, got %v", err)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("file not found returns error with path", func(t *testing.T) {
+		_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+		if err == nil || !strings.Contains(err.Error(), "missing.yaml") {
+			t.Errorf("expected error with path, got %v", err)
+		}
+	})
+
+	t.Run("loads from disk", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "api.yaml")
+		if err := os.WriteFile(path, []byte(testDoc), 0644); err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+		if _, err := Load(path); err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,387 @@
+// Package query implements the spec selection language of `spec-tdd query`
+// and the --select flags, e.g.
+//
+//	tag:auth AND status:ready AND NOT has:examples
+//	depends-on:REQ-003 OR (parent:REQ-001 -tag:legacy)
+//
+// A query is a boolean expression of field:value terms combined with AND,
+// OR, NOT (or a leading "-") and parentheses. Adjacent terms are ANDed.
+// Queries are evaluated against loaded specs; they do not read any files.
+package query
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Fields lists the fields a term can test.
+var Fields = []string{"id", "title", "status", "tag", "namespace", "parent", "depends-on", "has"}
+
+// HasValues lists the values of the has: field.
+var HasValues = []string{"examples", "rules", "questions", "open-questions", "depends", "parent", "tags", "description", "approvals", "stale-approvals"}
+
+// Query is a parsed query.
+type Query struct {
+	src  string
+	root node
+}
+
+// Term is a field:value test of a query.
+type Term struct {
+	Field string
+	Value string
+}
+
+// Parse parses src. Field names and the AND / OR / NOT keywords are
+// case-insensitive; values are matched as written.
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, apperrors.New("query.Parse", apperrors.ErrInvalidInput, "empty query")
+	}
+	p := &parser{toks: toks}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// String returns the query as written.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Match reports whether s satisfies the query.
+func (q *Query) Match(s *spec.Spec) bool {
+	return q.root.match(s)
+}
+
+// Filter returns the specs that satisfy the query, in order.
+func (q *Query) Filter(specs []*spec.Spec) []*spec.Spec {
+	out := make([]*spec.Spec, 0, len(specs))
+	for _, s := range specs {
+		if q.Match(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Terms returns the terms of the query in order of appearance, e.g. to
+// check status values against the lifecycle.
+func (q *Query) Terms() []Term {
+	var out []Term
+	var walk func(n node)
+	walk = func(n node) {
+		switch n := n.(type) {
+		case andNode:
+			walk(n.left)
+			walk(n.right)
+		case orNode:
+			walk(n.left)
+			walk(n.right)
+		case notNode:
+			walk(n.operand)
+		case termNode:
+			out = append(out, n.Term)
+		}
+	}
+	walk(q.root)
+	return out
+}
+
+type node interface {
+	match(s *spec.Spec) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(s *spec.Spec) bool { return n.left.match(s) && n.right.match(s) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(s *spec.Spec) bool { return n.left.match(s) || n.right.match(s) }
+
+type notNode struct{ operand node }
+
+func (n notNode) match(s *spec.Spec) bool { return !n.operand.match(s) }
+
+type termNode struct{ Term }
+
+func (n termNode) match(s *spec.Spec) bool {
+	switch n.Field {
+	case "id":
+		return glob(n.Value, s.ID)
+	case "title":
+		return strings.Contains(strings.ToLower(s.Title), strings.ToLower(n.Value))
+	case "status":
+		return s.Status == n.Value
+	case "tag":
+		return slices.ContainsFunc(s.Tags, func(t string) bool { return glob(n.Value, t) })
+	case "namespace":
+		return spec.InNamespace(s.Namespace, n.Value)
+	case "parent":
+		return s.Parent != "" && glob(n.Value, s.Parent)
+	case "depends-on":
+		return slices.ContainsFunc(s.Depends, func(d string) bool { return glob(n.Value, d) })
+	case "has":
+		return has(s, n.Value)
+	}
+	return false
+}
+
+func has(s *spec.Spec, what string) bool {
+	switch what {
+	case "examples":
+		return len(s.AllExamples()) > 0
+	case "rules":
+		return len(s.Rules) > 0
+	case "questions":
+		return len(s.Questions) > 0
+	case "open-questions":
+		return len(s.OpenQuestions()) > 0
+	case "depends":
+		return len(s.Depends) > 0
+	case "parent":
+		return s.Parent != ""
+	case "tags":
+		return len(s.Tags) > 0
+	case "description":
+		return strings.TrimSpace(s.Description) != ""
+	case "approvals":
+		return len(s.Approvals) > 0
+	case "stale-approvals":
+		return len(s.StaleApprovals()) > 0
+	}
+	return false
+}
+
+// glob matches name against pattern, where "*" and "?" are wildcards.
+func glob(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based offset in the query
+	// quoted is set when part of the word was quoted, so that e.g. "AND"
+	// is not taken as a keyword.
+	quoted bool
+}
+
+// keyword returns the upper-cased keyword of t, or "".
+func (t *token) keyword() string {
+	if t.kind != tokWord || t.quoted {
+		return ""
+	}
+	switch kw := strings.ToUpper(t.text); kw {
+	case "AND", "OR", "NOT":
+		return kw
+	}
+	return ""
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		switch c := src[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: i + 1})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: i + 1})
+			i++
+		default:
+			t := token{kind: tokWord, pos: i + 1}
+			var sb strings.Builder
+			for i < len(src) && !strings.ContainsRune(" \t\n\r()", rune(src[i])) {
+				if src[i] != '"' {
+					sb.WriteByte(src[i])
+					i++
+					continue
+				}
+				end := strings.IndexByte(src[i+1:], '"')
+				if end < 0 {
+					return nil, apperrors.New("query.Parse", apperrors.ErrInvalidInput,
+						fmt.Sprintf("unterminated quote at position %d", i+1))
+				}
+				sb.WriteString(src[i+1 : i+1+end])
+				t.quoted = true
+				i += end + 2
+			}
+			t.text = sb.String()
+			toks = append(toks, t)
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() *token {
+	if p.pos >= len(p.toks) {
+		return nil
+	}
+	return &p.toks[p.pos]
+}
+
+func (p *parser) errorf(t *token, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if t == nil {
+		msg += " at end of query"
+	} else {
+		msg += fmt.Sprintf(" at position %d", t.pos)
+	}
+	return apperrors.New("query.Parse", apperrors.ErrInvalidInput, msg)
+}
+
+// or = and { "OR" and }
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != nil && t.keyword() == "OR"; t = p.peek() {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+// and = not { ["AND"] not }
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != nil && t.kind != tokRParen && t.keyword() != "OR"; t = p.peek() {
+		if t.keyword() == "AND" {
+			p.pos++
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+// not = "NOT" not | "-" not | "-" "(" or ")" | primary
+func (p *parser) not() (node, error) {
+	t := p.peek()
+	if t != nil && t.keyword() == "NOT" {
+		p.pos++
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if t != nil && t.kind == tokWord && !t.quoted && t.text == "-" &&
+		p.pos+1 < len(p.toks) && p.toks[p.pos+1].kind == tokLParen {
+		// -(tag:a OR tag:b) is short for NOT (tag:a OR tag:b)
+		p.pos++
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if t != nil && t.kind == tokWord && !t.quoted && strings.HasPrefix(t.text, "-") && len(t.text) > 1 {
+		// -tag:legacy is short for NOT tag:legacy
+		t.text, t.pos = t.text[1:], t.pos+1
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.primary()
+}
+
+// primary = "(" or ")" | field ":" value
+func (p *parser) primary() (node, error) {
+	t := p.peek()
+	switch {
+	case t == nil:
+		return nil, p.errorf(t, "expected a term")
+	case t.kind == tokLParen:
+		p.pos++
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected \")\"")
+		}
+		p.pos++
+		return n, nil
+	case t.kind == tokRParen:
+		return nil, p.errorf(t, "unexpected \")\"")
+	case t.keyword() != "":
+		return nil, p.errorf(t, "expected a term before %s", t.keyword())
+	}
+	p.pos++
+	return parseTerm(p, t)
+}
+
+func parseTerm(p *parser, t *token) (node, error) {
+	field, value, ok := strings.Cut(t.text, ":")
+	if !ok {
+		return nil, p.errorf(t, "expected field:value, got %q (fields: %s)", t.text, strings.Join(Fields, ", "))
+	}
+	field = strings.ToLower(field)
+	if !slices.Contains(Fields, field) {
+		return nil, p.errorf(t, "unknown field %q (fields: %s)", field, strings.Join(Fields, ", "))
+	}
+	if value == "" {
+		return nil, p.errorf(t, "missing value for %s:", field)
+	}
+	switch field {
+	case "has":
+		if !slices.Contains(HasValues, value) {
+			return nil, p.errorf(t, "unknown has: value %q (allowed: %s)", value, strings.Join(HasValues, ", "))
+		}
+	case "id", "tag", "parent", "depends-on":
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, p.errorf(t, "invalid pattern %q", value)
+		}
+	case "namespace":
+		if err := spec.ValidateNamespace(value); err != nil {
+			return nil, p.errorf(t, "invalid namespace %q", value)
+		}
+	}
+	return termNode{Term{Field: field, Value: value}}, nil
+}
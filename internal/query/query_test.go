@@ -0,0 +1,110 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func testSpecs() []*spec.Spec {
+	ex := spec.Example{ID: "E1", Given: "a", When: "b", Then: "c"}
+	return []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Status: "ready", Tags: []string{"auth"}, Examples: []spec.Example{ex}},
+		{ID: "REQ-002", Title: "Reset password", Status: "draft", Tags: []string{"auth", "mail"}, Depends: []string{"REQ-001"}, Namespace: "auth",
+			Questions: []spec.Question{{ID: "Q1", Text: "Token lifetime?", Status: spec.QuestionOpen}}},
+		{ID: "REQ-003", Title: "Invoices", Status: "ready", Parent: "REQ-001", Namespace: "billing/invoices",
+			Rules: []spec.Rule{{ID: "R1", Text: "Monthly", Examples: []spec.Example{ex}}}},
+		{ID: "AUTH-001", Title: "SSO", Status: "implemented", Tags: []string{"legacy"}, Depends: []string{"REQ-003"}},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"tag:auth", "REQ-001 REQ-002"},
+		{"tag:auth AND status:ready", "REQ-001"},
+		{"tag:auth status:ready", "REQ-001"},
+		{"tag:auth and not has:examples", "REQ-002"},
+		{"tag:auth AND status:ready AND NOT has:examples", ""},
+		{"depends-on:REQ-003", "AUTH-001"},
+		{"depends-on:REQ-*", "REQ-002 AUTH-001"},
+		{"status:draft OR status:implemented", "REQ-002 AUTH-001"},
+		{"has:examples -status:draft", "REQ-001 REQ-003"},
+		{"-(tag:auth OR tag:legacy)", "REQ-003"},
+		{"has:examples -(status:draft OR parent:REQ-001)", "REQ-001"},
+		{"(tag:auth OR parent:REQ-001) AND has:examples", "REQ-001 REQ-003"},
+		{"tag:auth OR parent:REQ-001 AND has:examples", "REQ-001 REQ-002 REQ-003"},
+		{"NOT NOT id:AUTH-*", "AUTH-001"},
+		{"id:REQ-00?", "REQ-001 REQ-002 REQ-003"},
+		{`title:"reset pass"`, "REQ-002"},
+		{"title:IN", "REQ-001 REQ-003"},
+		{"namespace:billing", "REQ-003"},
+		{"has:open-questions OR has:rules", "REQ-002 REQ-003"},
+		{"has:parent", "REQ-003"},
+		{"Tag:mail", "REQ-002"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			var ids []string
+			for _, s := range q.Filter(testSpecs()) {
+				ids = append(ids, s.ID)
+			}
+			if got := strings.Join(ids, " "); got != tt.want {
+				t.Errorf("matched %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{"", "empty query"},
+		{"auth", `expected field:value, got "auth"`},
+		{"label:auth", `unknown field "label"`},
+		{"tag:", "missing value for tag:"},
+		{"has:tests", `unknown has: value "tests"`},
+		{"tag:auth AND", "expected a term at end of query"},
+		{"- tag:auth", `expected field:value, got "-"`},
+		{"(tag:auth", `expected ")" at end of query`},
+		{"tag:auth)", `unexpected ")" at position 9`},
+		{"OR tag:auth", "expected a term before OR at position 1"},
+		{`title:"reset`, "unterminated quote at position 7"},
+		{"id:REQ-[", `invalid pattern "REQ-["`},
+		{"namespace:../x", `invalid namespace "../x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	q, err := Parse(`status:ready OR (NOT tag:"a b" AND has:rules)`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	want := []Term{{"status", "ready"}, {"tag", "a b"}, {"has", "rules"}}
+	got := q.Terms()
+	if len(got) != len(want) {
+		t.Fatalf("Terms() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Terms()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
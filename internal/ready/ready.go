@@ -0,0 +1,117 @@
+package ready
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Failure is a Definition-of-Ready rule a spec does not satisfy.
+type Failure struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// Result is the readiness of one spec.
+type Result struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Failures []Failure `json:"failures,omitempty"`
+}
+
+// Ready reports whether the spec passed every enabled rule.
+func (r Result) Ready() bool {
+	return len(r.Failures) == 0
+}
+
+// Check evaluates specs against the Definition of Ready. all is the full set
+// of specs used to resolve depends, which may be larger than specs.
+func Check(specs, all []*spec.Spec, cfg config.ReadinessConfig) []Result {
+	ids := make(map[string]bool, len(all))
+	for _, s := range all {
+		ids[s.ID] = true
+	}
+
+	results := make([]Result, 0, len(specs))
+	for _, s := range specs {
+		res := Result{ID: s.ID, Title: s.Title}
+		fail := func(rule, format string, args ...any) {
+			res.Failures = append(res.Failures, Failure{Rule: rule, Reason: fmt.Sprintf(format, args...)})
+		}
+
+		if cfg.Enabled(config.ReadyHasExamples) {
+			if len(s.AllExamples()) == 0 {
+				fail(config.ReadyHasExamples, "no examples")
+			}
+			for _, r := range s.Rules {
+				if len(r.Examples) == 0 {
+					fail(config.ReadyHasExamples, "rule %s has no examples", r.ID)
+				}
+			}
+		}
+
+		if cfg.Enabled(config.ReadyNoOpenQuestions) {
+			for _, q := range s.OpenQuestions() {
+				label := q.ID
+				if label == "" {
+					label = "question"
+				}
+				fail(config.ReadyNoOpenQuestions, "open %s: %s", label, q.Text)
+			}
+		}
+
+		if cfg.Enabled(config.ReadyDependsExist) {
+			for _, dep := range s.Depends {
+				if !ids[dep] {
+					fail(config.ReadyDependsExist, "depends on missing %s", dep)
+				}
+			}
+		}
+
+		if cfg.Enabled(config.ReadyNoPlaceholders) {
+			for _, f := range placeholderFields(s) {
+				if p := findPlaceholder(f.text, cfg.Placeholders); p != "" {
+					fail(config.ReadyNoPlaceholders, "%s contains placeholder %q", f.name, p)
+				}
+			}
+		}
+
+		if cfg.Enabled(config.ReadyNoStaleApprovals) {
+			for _, a := range s.StaleApprovals() {
+				fail(config.ReadyNoStaleApprovals, "approval by %s on %s is stale (spec changed since)", a.Reviewer, a.ApprovedAt.Format(time.DateOnly))
+			}
+		}
+
+		results = append(results, res)
+	}
+	return results
+}
+
+type field struct {
+	name string
+	text string
+}
+
+func placeholderFields(s *spec.Spec) []field {
+	fields := []field{{"title", s.Title}, {"description", s.Description}}
+	for _, ex := range s.AllExamples() {
+		fields = append(fields,
+			field{ex.ID + " given", ex.Given},
+			field{ex.ID + " when", ex.When},
+			field{ex.ID + " then", ex.Then},
+		)
+	}
+	return fields
+}
+
+func findPlaceholder(text string, placeholders []string) string {
+	for _, p := range placeholders {
+		if p != "" && strings.Contains(text, p) {
+			return p
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package ready
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestCheck(t *testing.T) {
+	ex := spec.Example{ID: "E1", Given: "registered user", When: "login", Then: "dashboard shown"}
+	specs := []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Examples: []spec.Example{ex}},
+		{ID: "REQ-002", Title: "Logout"},
+		{ID: "REQ-003", Title: "Lockout", Depends: []string{"REQ-009"},
+			Examples:  []spec.Example{{ID: "E1", Given: "TODO", When: "login fails", Then: "locked"}},
+			Questions: []spec.Question{{ID: "Q1", Text: "How many attempts?"}, {ID: "Q2", Text: "x", Status: spec.QuestionResolved}},
+			Rules:     []spec.Rule{{ID: "R1", Text: "Admins are never locked"}},
+		},
+	}
+
+	results := Check(specs, specs, config.DefaultSpecConfig().EffectiveReadiness())
+
+	if !results[0].Ready() {
+		t.Errorf("REQ-001 should be ready, got %+v", results[0].Failures)
+	}
+	if results[1].Ready() || results[1].Failures[0].Rule != config.ReadyHasExamples {
+		t.Errorf("REQ-002 should fail has-examples, got %+v", results[1].Failures)
+	}
+
+	var reasons []string
+	for _, f := range results[2].Failures {
+		reasons = append(reasons, f.Rule+": "+f.Reason)
+	}
+	want := []string{
+		"has-examples: rule R1 has no examples",
+		"no-open-questions: open Q1: How many attempts?",
+		"depends-exist: depends on missing REQ-009",
+		`no-placeholders: E1 given contains placeholder "TODO"`,
+	}
+	if strings.Join(reasons, "\n") != strings.Join(want, "\n") {
+		t.Errorf("REQ-003 failures =\n%s\nwant\n%s", strings.Join(reasons, "\n"), strings.Join(want, "\n"))
+	}
+
+	t.Run("stale approvals", func(t *testing.T) {
+		s := &spec.Spec{ID: "REQ-004", Title: "Reset password", Examples: []spec.Example{ex}}
+		s.Approve("alice", "", time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+		if res := Check([]*spec.Spec{s}, specs, config.DefaultReadiness()); !res[0].Ready() {
+			t.Fatalf("freshly approved spec should be ready, got %+v", res[0].Failures)
+		}
+		s.Examples[0].Then = "reset mail sent"
+		res := Check([]*spec.Spec{s}, specs, config.DefaultReadiness())
+		want := []Failure{{Rule: config.ReadyNoStaleApprovals, Reason: "approval by alice on 2026-03-01 is stale (spec changed since)"}}
+		if !slices.Equal(res[0].Failures, want) {
+			t.Errorf("failures = %+v, want %+v", res[0].Failures, want)
+		}
+	})
+
+	t.Run("disabled rules are skipped", func(t *testing.T) {
+		cfg := config.ReadinessConfig{Rules: []string{config.ReadyDependsExist}}
+		results := Check(specs[1:2], specs, cfg)
+		if !results[0].Ready() {
+			t.Errorf("expected REQ-002 to pass with only depends-exist, got %+v", results[0].Failures)
+		}
+	})
+}
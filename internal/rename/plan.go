@@ -0,0 +1,141 @@
+package rename
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"github.com/thirdlf03/spec-tdd/internal/trace"
+)
+
+// Plan returns the spec and test changes for m after checking that the
+// mapping can be applied safely. It refuses a mapping when:
+//   - an old ID has no spec, or a new ID does not match the ID scheme
+//   - two old IDs map to the same new ID
+//   - a new ID already belongs to a spec that is not renamed itself
+//   - a new ID is already mentioned in a spec or test (e.g. a leftover test of
+//     a deleted requirement), which the rename would silently merge
+//   - a renamed file would overwrite a file outside the plan
+func Plan(specDir, testDir string, m Mapping) ([]FileChange, error) {
+	if err := checkMapping(specDir, testDir, m); err != nil {
+		return nil, err
+	}
+	specChanges, err := PlanSpecs(specDir, m)
+	if err != nil {
+		return nil, err
+	}
+	testChanges, err := PlanTests(testDir, m)
+	if err != nil {
+		return nil, err
+	}
+	changes := append(specChanges, testChanges...)
+	if err := checkTargets("rename.Plan", changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func checkMapping(specDir, testDir string, m Mapping) error {
+	const op = "rename.Plan"
+	ids := spec.CurrentIDScheme()
+	known, err := spec.ListIDs(specDir)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(known))
+	for _, id := range known {
+		exists[id] = true
+	}
+
+	olds := make([]string, 0, len(m))
+	for old := range m {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+
+	seen := make(map[string]string, len(m))
+	var fresh []string
+	for _, old := range olds {
+		next := m[old]
+		switch {
+		case !exists[old]:
+			return apperrors.New(op, apperrors.ErrNotFound, "spec not found: "+old)
+		case old == next:
+			return apperrors.New(op, apperrors.ErrInvalidInput, old+" is renamed to itself")
+		case !ids.Valid(next):
+			return apperrors.New(op, apperrors.ErrInvalidInput, "invalid ID "+next+" (must match "+ids.Describe()+")")
+		case seen[next] != "":
+			return apperrors.New(op, apperrors.ErrInvalidInput, seen[next]+" and "+old+" would both become "+next)
+		}
+		seen[next] = old
+		if _, moving := m[next]; moving {
+			continue
+		}
+		if exists[next] {
+			return apperrors.New(op, apperrors.ErrInvalidInput, next+" already exists")
+		}
+		fresh = append(fresh, next)
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	// A fresh ID that is already mentioned somewhere would become
+	// indistinguishable from the renamed requirement's references
+	files, err := spec.ListFiles(specDir)
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(testDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && trace.IsTestFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return apperrors.Wrap(op, err)
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return apperrors.Wrap(op, err)
+		}
+		for _, id := range fresh {
+			if ContainsID(filepath.Base(path), id) || ContainsID(string(data), id) {
+				return apperrors.New(op, apperrors.ErrInvalidInput, id+" is already referenced in "+path+"; renaming to it would merge those references")
+			}
+		}
+	}
+	return nil
+}
+
+// checkTargets rejects plans where a renamed file would land on another
+// file: one that exists outside the plan, or the target of another change.
+func checkTargets(op string, changes []FileChange) error {
+	moving := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		if c.Renamed() {
+			moving[c.Path] = true
+		}
+	}
+	targets := make(map[string]string, len(changes))
+	for _, c := range changes {
+		if prev, ok := targets[c.NewPath]; ok {
+			return apperrors.New(op, apperrors.ErrInvalidInput, prev+" and "+c.Path+" would both be written to "+c.NewPath)
+		}
+		targets[c.NewPath] = c.Path
+		if !c.Renamed() || moving[c.NewPath] {
+			continue
+		}
+		if _, err := os.Stat(c.NewPath); err == nil {
+			return apperrors.New(op, apperrors.ErrInvalidInput, "target already exists: "+c.NewPath)
+		}
+	}
+	return nil
+}
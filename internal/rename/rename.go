@@ -0,0 +1,240 @@
+package rename
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"github.com/thirdlf03/spec-tdd/internal/trace"
+)
+
+// Mapping maps old requirement IDs to new ones.
+type Mapping map[string]string
+
+// ReplaceIDs replaces every whole-ID occurrence of the mapping's keys in
+// text. An occurrence must not be directly preceded or followed by a letter
+// or digit, so REQ-001 does not match inside REQ-0010, while file names such
+// as req-REQ-001-login.test.ts are still rewritten.
+func ReplaceIDs(text string, m Mapping) string {
+	if len(m) == 0 {
+		return text
+	}
+	// Longest IDs first, so REQ-tmp-a.12 wins over REQ-tmp-a.1 at the same offset
+	olds := make([]string, 0, len(m))
+	for old := range m {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool {
+		if len(olds[i]) != len(olds[j]) {
+			return len(olds[i]) > len(olds[j])
+		}
+		return olds[i] < olds[j]
+	})
+
+	var sb strings.Builder
+	i := 0
+	for i < len(text) {
+		matched := matchAt(text, i, olds)
+		if matched == "" {
+			sb.WriteByte(text[i])
+			i++
+			continue
+		}
+		sb.WriteString(m[matched])
+		i += len(matched)
+	}
+	return sb.String()
+}
+
+// ContainsID reports whether text mentions id as a whole ID, using the same
+// boundary rules as ReplaceIDs.
+func ContainsID(text, id string) bool {
+	for i := strings.Index(text, id); i >= 0; {
+		if matchAt(text, i, []string{id}) != "" {
+			return true
+		}
+		next := strings.Index(text[i+1:], id)
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
+
+// matchAt returns the first of ids that occurs as a whole ID at offset i.
+func matchAt(text string, i int, ids []string) string {
+	if i > 0 && isIDChar(text[i-1]) {
+		return ""
+	}
+	for _, id := range ids {
+		end := i + len(id)
+		if strings.HasPrefix(text[i:], id) && (end == len(text) || !isIDChar(text[end])) {
+			return id
+		}
+	}
+	return ""
+}
+
+func isIDChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// RewriteSpec applies the mapping to a spec's ID, parent, depends and text
+// fields. It reports whether anything changed.
+func RewriteSpec(s *spec.Spec, m Mapping) bool {
+	changed := false
+	set := func(p *string) {
+		if next := ReplaceIDs(*p, m); next != *p {
+			*p = next
+			changed = true
+		}
+	}
+	exact := func(p *string) {
+		if next, ok := m[*p]; ok {
+			*p = next
+			changed = true
+		}
+	}
+
+	exact(&s.ID)
+	exact(&s.Parent)
+	for i := range s.Depends {
+		exact(&s.Depends[i])
+	}
+	set(&s.Title)
+	set(&s.Description)
+	rewriteExamples := func(examples []spec.Example) {
+		for i := range examples {
+			set(&examples[i].Given)
+			set(&examples[i].When)
+			set(&examples[i].Then)
+		}
+	}
+	rewriteExamples(s.Examples)
+	for i := range s.Rules {
+		set(&s.Rules[i].Text)
+		rewriteExamples(s.Rules[i].Examples)
+	}
+	for i := range s.Questions {
+		set(&s.Questions[i].Text)
+		set(&s.Questions[i].Answer)
+	}
+	return changed
+}
+
+// FileChange is a planned change to one file. Path is the current path and
+// NewPath the path after renaming (equal to Path when not renamed).
+type FileChange struct {
+	Path    string
+	NewPath string
+	Before  []byte
+	After   []byte
+}
+
+// Renamed reports whether the file moves.
+func (c FileChange) Renamed() bool {
+	return c.Path != c.NewPath
+}
+
+// Modified reports whether the file content changes.
+func (c FileChange) Modified() bool {
+	return string(c.Before) != string(c.After)
+}
+
+// PlanSpecs rewrites the specs in specDir that are renamed or refer to an
+// old ID. A spec file named after its old ID is renamed to the new ID.
+func PlanSpecs(specDir string, m Mapping) ([]FileChange, error) {
+	files, err := spec.ListFiles(specDir)
+	if err != nil {
+		return nil, err
+	}
+	var changes []FileChange
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, apperrors.Wrap("rename.PlanSpecs", err)
+		}
+		s, err := spec.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		oldID := s.ID
+		if !RewriteSpec(s, m) {
+			continue
+		}
+		after, err := spec.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		newPath := path
+		ext := filepath.Ext(path)
+		if strings.TrimSuffix(filepath.Base(path), ext) == oldID {
+			newPath = filepath.Join(filepath.Dir(path), s.ID+ext)
+		}
+		changes = append(changes, FileChange{Path: path, NewPath: newPath, Before: data, After: after})
+	}
+	return changes, nil
+}
+
+// PlanTests finds test files under testDir whose name or content refers to
+// an old ID and returns the rewritten names and contents.
+func PlanTests(testDir string, m Mapping) ([]FileChange, error) {
+	var changes []FileChange
+	err := filepath.WalkDir(testDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !trace.IsTestFile(path) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		c := FileChange{
+			Path:    path,
+			NewPath: filepath.Join(filepath.Dir(path), ReplaceIDs(filepath.Base(path), m)),
+			Before:  data,
+			After:   []byte(ReplaceIDs(string(data), m)),
+		}
+		if c.Renamed() || c.Modified() {
+			changes = append(changes, c)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, apperrors.Wrap("rename.PlanTests", err)
+	}
+	return changes, nil
+}
+
+// Apply writes planned file changes as one unit (see atomicfile.Tx): a
+// failed write leaves every file untouched. Renamed sources are removed
+// before the new files are moved into place, so IDs can be swapped or
+// shifted (REQ-002 → REQ-003, REQ-003 → REQ-004). A rename never overwrites
+// a file outside the plan.
+func Apply(changes []FileChange) error {
+	if err := checkTargets("rename.Apply", changes); err != nil {
+		return err
+	}
+	tx := atomicfile.NewTx()
+	for _, c := range changes {
+		if c.Renamed() {
+			tx.Remove(c.Path)
+		}
+		tx.Write(c.NewPath, c.After, 0644)
+	}
+	if err := tx.Commit(); err != nil {
+		return apperrors.Wrap("rename.Apply", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package rename
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestReplaceIDs(t *testing.T) {
+	m := Mapping{"REQ-001": "REQ-002", "REQ-002": "REQ-003", "REQ-tmp-a.1": "REQ-010"}
+	tests := map[string]string{
+		"REQ-001 depends on REQ-002":       "REQ-002 depends on REQ-003",
+		"REQ-0010 and XREQ-001 unchanged":  "REQ-0010 and XREQ-001 unchanged",
+		"req-REQ-001-login.test.ts":        "req-REQ-002-login.test.ts",
+		"REQ-001,REQ-001":                  "REQ-002,REQ-002",
+		"REQ-tmp-a.1 but not REQ-tmp-a.12": "REQ-010 but not REQ-tmp-a.12",
+		"REQ-001を参照":                       "REQ-002を参照",
+	}
+	for in, want := range tests {
+		if got := ReplaceIDs(in, m); got != want {
+			t.Errorf("ReplaceIDs(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	dir := t.TempDir()
+	specDir := filepath.Join(dir, "specs")
+	testDir := filepath.Join(dir, "tests")
+	for _, s := range []*spec.Spec{
+		{ID: "REQ-001", Title: "Login"},
+		{ID: "REQ-002", Title: "Logout", Depends: []string{"REQ-001"}, Description: "After REQ-001"},
+		{ID: "REQ-003", Title: "Profile", Parent: "REQ-002"},
+	} {
+		if err := spec.Save(filepath.Join(specDir, s.ID+".yml"), s); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	testFile := filepath.Join(testDir, "req-REQ-001-login.test.ts")
+	if err := os.WriteFile(testFile, []byte(`it("REQ-001 E1: ok", () => {})`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Shift REQ-001 and REQ-002 up by one; REQ-002's file is both a source and a target
+	m := Mapping{"REQ-001": "REQ-002", "REQ-002": "REQ-004"}
+	specChanges, err := PlanSpecs(specDir, m)
+	if err != nil {
+		t.Fatalf("PlanSpecs error: %v", err)
+	}
+	if len(specChanges) != 3 {
+		t.Fatalf("expected 3 spec changes, got %d", len(specChanges))
+	}
+	testChanges, err := PlanTests(testDir, m)
+	if err != nil {
+		t.Fatalf("PlanTests error: %v", err)
+	}
+	if len(testChanges) != 1 || !testChanges[0].Renamed() || !testChanges[0].Modified() {
+		t.Fatalf("unexpected test changes: %+v", testChanges)
+	}
+
+	if err := Apply(append(specChanges, testChanges...)); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	specs, err := spec.LoadAll(specDir)
+	if err != nil {
+		t.Fatalf("LoadAll error: %v", err)
+	}
+	var got []string
+	for _, s := range specs {
+		got = append(got, s.ID+":"+s.Title+":"+s.Parent+":"+strings.Join(s.Depends, ","))
+	}
+	if strings.Join(got, " ") != "REQ-002:Login:: REQ-003:Profile:REQ-004: REQ-004:Logout::REQ-002" {
+		t.Errorf("specs after apply = %v", got)
+	}
+	data, err := os.ReadFile(filepath.Join(testDir, "req-REQ-002-login.test.ts"))
+	if err != nil || string(data) != `it("REQ-002 E1: ok", () => {})` {
+		t.Errorf("test file = %q, %v", data, err)
+	}
+	if _, err := os.Stat(testFile); !os.IsNotExist(err) {
+		t.Error("old test file should be removed")
+	}
+}
+
+func TestApplyRefusesToOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	for _, p := range []string{a, b} {
+		if err := os.WriteFile(p, []byte(p), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := Apply([]FileChange{{Path: a, NewPath: b, Before: []byte(a), After: []byte(a)}})
+	if err == nil || !strings.Contains(err.Error(), "target already exists") {
+		t.Errorf("expected overwrite refusal, got %v", err)
+	}
+	if data, _ := os.ReadFile(b); string(data) != b {
+		t.Error("b.txt must be untouched")
+	}
+}
+
+func TestPlanRefusesUnsafeMappings(t *testing.T) {
+	dir := t.TempDir()
+	specDir := filepath.Join(dir, "specs")
+	testDir := filepath.Join(dir, "tests")
+	for _, s := range []*spec.Spec{
+		{ID: "REQ-001", Title: "Login"},
+		{ID: "REQ-002", Title: "Logout"},
+	} {
+		if err := spec.Save(filepath.Join(specDir, s.ID+".yml"), s); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A leftover test of a removed requirement
+	if err := os.WriteFile(filepath.Join(testDir, "old.test.ts"), []byte(`it("REQ-009 E1: gone", () => {})`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		m       Mapping
+		wantErr string
+	}{
+		{"missing spec", Mapping{"REQ-005": "REQ-006"}, "spec not found: REQ-005"},
+		{"invalid target", Mapping{"REQ-001": "login"}, "invalid ID login"},
+		{"existing target", Mapping{"REQ-001": "REQ-002"}, "REQ-002 already exists"},
+		{"same target", Mapping{"REQ-001": "REQ-003", "REQ-002": "REQ-003"}, "would both become REQ-003"},
+		{"target already referenced", Mapping{"REQ-001": "REQ-009"}, "REQ-009 is already referenced in"},
+		{"swap", Mapping{"REQ-001": "REQ-002", "REQ-002": "REQ-001"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Plan(specDir, testDir, tt.m)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := "id: REQ-001\ntitle: Login\na\nb\nc\nd\ne\nf\ng\nh\nlast REQ-001\n"
+	after := "id: REQ-004\ntitle: Login\na\nb\nc\nd\ne\nf\ng\nh\nlast REQ-004\n"
+	got := Diff(FileChange{Path: "specs/REQ-001.yml", NewPath: "specs/REQ-004.yml", Before: []byte(before), After: []byte(after)})
+	want := `--- specs/REQ-001.yml
++++ specs/REQ-004.yml
+@@ -1,4 +1,4 @@
+-id: REQ-001
++id: REQ-004
+ title: Login
+ a
+ b
+@@ -8,4 +8,4 @@
+ f
+ g
+ h
+-last REQ-001
++last REQ-004
+`
+	if got != want {
+		t.Errorf("Diff =\n%s\nwant\n%s", got, want)
+	}
+
+	renameOnly := Diff(FileChange{Path: "a", NewPath: "b", Before: []byte("x\n"), After: []byte("x\n")})
+	if renameOnly != "--- a\n+++ b\n" {
+		t.Errorf("rename-only diff = %q", renameOnly)
+	}
+}
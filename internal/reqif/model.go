@@ -0,0 +1,130 @@
+package reqif
+
+import (
+	"encoding/xml"
+)
+
+// Namespace is the ReqIF 1.0+ XML namespace.
+const Namespace = "http://www.omg.org/spec/ReqIF/20110401/reqif.xsd"
+
+// Document is the subset of the ReqIF schema used for spec interchange.
+type Document struct {
+	XMLName xml.Name `xml:"REQ-IF"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	Header  Header   `xml:"THE-HEADER>REQ-IF-HEADER"`
+	Content Content  `xml:"CORE-CONTENT>REQ-IF-CONTENT"`
+}
+
+// Header is the REQ-IF-HEADER element.
+type Header struct {
+	Identifier   string `xml:"IDENTIFIER,attr"`
+	CreationTime string `xml:"CREATION-TIME,omitempty"`
+	ReqIFToolID  string `xml:"REQ-IF-TOOL-ID,omitempty"`
+	ReqIFVersion string `xml:"REQ-IF-VERSION,omitempty"`
+	SourceToolID string `xml:"SOURCE-TOOL-ID,omitempty"`
+	Title        string `xml:"TITLE,omitempty"`
+}
+
+// Content is the REQ-IF-CONTENT element.
+type Content struct {
+	Datatypes          []Datatype          `xml:"DATATYPES>DATATYPE-DEFINITION-STRING"`
+	ObjectTypes        []SpecObjectType    `xml:"SPEC-TYPES>SPEC-OBJECT-TYPE"`
+	RelationTypes      []SpecRelationType  `xml:"SPEC-TYPES>SPEC-RELATION-TYPE"`
+	SpecificationTypes []SpecificationType `xml:"SPEC-TYPES>SPECIFICATION-TYPE"`
+	Objects            []SpecObject        `xml:"SPEC-OBJECTS>SPEC-OBJECT"`
+	Relations          []SpecRelation      `xml:"SPEC-RELATIONS>SPEC-RELATION"`
+	Specifications     []Specification     `xml:"SPECIFICATIONS>SPECIFICATION"`
+}
+
+// Datatype is a DATATYPE-DEFINITION-STRING.
+type Datatype struct {
+	Identifier string `xml:"IDENTIFIER,attr"`
+	LongName   string `xml:"LONG-NAME,attr,omitempty"`
+	LastChange string `xml:"LAST-CHANGE,attr"`
+	MaxLength  int    `xml:"MAX-LENGTH,attr"`
+}
+
+// SpecObjectType declares the attributes of a kind of SPEC-OBJECT.
+type SpecObjectType struct {
+	Identifier      string                `xml:"IDENTIFIER,attr"`
+	LongName        string                `xml:"LONG-NAME,attr,omitempty"`
+	LastChange      string                `xml:"LAST-CHANGE,attr"`
+	Attributes      []AttributeDefinition `xml:"SPEC-ATTRIBUTES>ATTRIBUTE-DEFINITION-STRING"`
+	XHTMLAttributes []AttributeDefinition `xml:"SPEC-ATTRIBUTES>ATTRIBUTE-DEFINITION-XHTML"`
+}
+
+// AttributeDefinition is an ATTRIBUTE-DEFINITION-STRING or -XHTML.
+type AttributeDefinition struct {
+	Identifier string `xml:"IDENTIFIER,attr"`
+	LongName   string `xml:"LONG-NAME,attr,omitempty"`
+	LastChange string `xml:"LAST-CHANGE,attr"`
+	StringType string `xml:"TYPE>DATATYPE-DEFINITION-STRING-REF,omitempty"`
+	XHTMLType  string `xml:"TYPE>DATATYPE-DEFINITION-XHTML-REF,omitempty"`
+}
+
+// SpecRelationType is a SPEC-RELATION-TYPE.
+type SpecRelationType struct {
+	Identifier string `xml:"IDENTIFIER,attr"`
+	LongName   string `xml:"LONG-NAME,attr,omitempty"`
+	LastChange string `xml:"LAST-CHANGE,attr"`
+}
+
+// SpecificationType is a SPECIFICATION-TYPE.
+type SpecificationType struct {
+	Identifier string `xml:"IDENTIFIER,attr"`
+	LongName   string `xml:"LONG-NAME,attr,omitempty"`
+	LastChange string `xml:"LAST-CHANGE,attr"`
+}
+
+// SpecObject is a requirement or example.
+type SpecObject struct {
+	Identifier   string                `xml:"IDENTIFIER,attr"`
+	LastChange   string                `xml:"LAST-CHANGE,attr"`
+	TypeRef      string                `xml:"TYPE>SPEC-OBJECT-TYPE-REF"`
+	StringValues []AttributeValueStr   `xml:"VALUES>ATTRIBUTE-VALUE-STRING"`
+	XHTMLValues  []AttributeValueXHTML `xml:"VALUES>ATTRIBUTE-VALUE-XHTML"`
+}
+
+// AttributeValueStr is an ATTRIBUTE-VALUE-STRING.
+type AttributeValueStr struct {
+	Value         string `xml:"THE-VALUE,attr"`
+	DefinitionRef string `xml:"DEFINITION>ATTRIBUTE-DEFINITION-STRING-REF"`
+}
+
+// AttributeValueXHTML is an ATTRIBUTE-VALUE-XHTML, as written by most tools
+// for rich text such as ReqIF.Text. Only the text content is imported.
+type AttributeValueXHTML struct {
+	Value         XHTMLContent `xml:"THE-VALUE"`
+	DefinitionRef string       `xml:"DEFINITION>ATTRIBUTE-DEFINITION-XHTML-REF"`
+}
+
+// XHTMLContent holds the raw XHTML of an attribute value.
+type XHTMLContent struct {
+	Inner string `xml:",innerxml"`
+}
+
+// SpecRelation links two SPEC-OBJECTs.
+type SpecRelation struct {
+	Identifier string `xml:"IDENTIFIER,attr"`
+	LastChange string `xml:"LAST-CHANGE,attr"`
+	TypeRef    string `xml:"TYPE>SPEC-RELATION-TYPE-REF"`
+	Source     string `xml:"SOURCE>SPEC-OBJECT-REF"`
+	Target     string `xml:"TARGET>SPEC-OBJECT-REF"`
+}
+
+// Specification is a document-like tree of SPEC-OBJECT references.
+type Specification struct {
+	Identifier string      `xml:"IDENTIFIER,attr"`
+	LongName   string      `xml:"LONG-NAME,attr,omitempty"`
+	LastChange string      `xml:"LAST-CHANGE,attr"`
+	TypeRef    string      `xml:"TYPE>SPECIFICATION-TYPE-REF"`
+	Children   []Hierarchy `xml:"CHILDREN>SPEC-HIERARCHY"`
+}
+
+// Hierarchy is a SPEC-HIERARCHY node.
+type Hierarchy struct {
+	Identifier string      `xml:"IDENTIFIER,attr"`
+	LastChange string      `xml:"LAST-CHANGE,attr"`
+	ObjectRef  string      `xml:"OBJECT>SPEC-OBJECT-REF"`
+	Children   []Hierarchy `xml:"CHILDREN>SPEC-HIERARCHY"`
+}
@@ -0,0 +1,342 @@
+package reqif
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Long names used to recognize types and attributes on import. Tools are
+// free to rewrite identifiers of type definitions, but keep long names.
+const (
+	TypeRequirement = "Requirement"
+	TypeExample     = "Example"
+	RelationDepends = "Depends"
+
+	AttrForeignID = "ReqIF.ForeignID"
+	AttrName      = "ReqIF.Name"
+	AttrText      = "ReqIF.Text"
+	AttrTags      = "Tags"
+	AttrGiven     = "Given"
+	AttrWhen      = "When"
+	AttrThen      = "Then"
+)
+
+const (
+	toolID      = "spec-tdd"
+	idPrefix    = "_spec-tdd_"
+	dtString    = idPrefix + "DT_String"
+	sotReq      = idPrefix + "SOT_Requirement"
+	sotExample  = idPrefix + "SOT_Example"
+	srtDepends  = idPrefix + "SRT_Depends"
+	stSpec      = idPrefix + "ST_Specification"
+	specRootID  = idPrefix + "Specification"
+	tagSep      = ", "
+	maxTextSize = 32000
+)
+
+var (
+	xhtmlTagRegex = regexp.MustCompile(`<[^>]+>`)
+	xhtmlBreak    = regexp.MustCompile(`<br\s*/?>|</(?:\w+:)?(?:p|div|li)>`)
+)
+
+// ObjectIdentifier returns the stable SPEC-OBJECT identifier for a requirement.
+func ObjectIdentifier(reqID string) string {
+	return idPrefix + reqID
+}
+
+// ExampleIdentifier returns the stable SPEC-OBJECT identifier for an example.
+func ExampleIdentifier(reqID, exampleID string) string {
+	return idPrefix + reqID + "_" + exampleID
+}
+
+func attrID(objType, name string) string {
+	return fmt.Sprintf("%sAD_%s_%s", idPrefix, objType, strings.ReplaceAll(name, ".", "_"))
+}
+
+// Export builds a ReqIF document from specs. Requirements and examples become
+// SPEC-OBJECTs, examples are nested under their requirement in the
+// specification hierarchy, and depends entries become SPEC-RELATIONs.
+// Identifiers are derived from REQ and example IDs so they are stable
+// across exports.
+func Export(specs []*spec.Spec, now time.Time) *Document {
+	ts := now.UTC().Format(time.RFC3339)
+
+	def := func(objType, name string) AttributeDefinition {
+		return AttributeDefinition{Identifier: attrID(objType, name), LongName: name, LastChange: ts, StringType: dtString}
+	}
+
+	doc := &Document{
+		XMLNS: Namespace,
+		Header: Header{
+			Identifier:   idPrefix + "Header",
+			CreationTime: ts,
+			ReqIFToolID:  toolID,
+			ReqIFVersion: "1.0",
+			SourceToolID: toolID,
+			Title:        "spec-tdd requirements",
+		},
+	}
+	c := &doc.Content
+	c.Datatypes = []Datatype{{Identifier: dtString, LongName: "String", LastChange: ts, MaxLength: maxTextSize}}
+	c.ObjectTypes = []SpecObjectType{
+		{
+			Identifier: sotReq, LongName: TypeRequirement, LastChange: ts,
+			Attributes: []AttributeDefinition{
+				def(TypeRequirement, AttrForeignID), def(TypeRequirement, AttrName),
+				def(TypeRequirement, AttrText), def(TypeRequirement, AttrTags),
+			},
+		},
+		{
+			Identifier: sotExample, LongName: TypeExample, LastChange: ts,
+			Attributes: []AttributeDefinition{
+				def(TypeExample, AttrForeignID), def(TypeExample, AttrGiven),
+				def(TypeExample, AttrWhen), def(TypeExample, AttrThen),
+			},
+		},
+	}
+	c.RelationTypes = []SpecRelationType{{Identifier: srtDepends, LongName: RelationDepends, LastChange: ts}}
+	c.SpecificationTypes = []SpecificationType{{Identifier: stSpec, LongName: "Specification", LastChange: ts}}
+
+	val := func(objType, name, v string) AttributeValueStr {
+		return AttributeValueStr{Value: v, DefinitionRef: attrID(objType, name)}
+	}
+
+	root := Specification{Identifier: specRootID, LongName: "spec-tdd", LastChange: ts, TypeRef: stSpec}
+	for _, s := range specs {
+		reqObj := SpecObject{
+			Identifier: ObjectIdentifier(s.ID),
+			LastChange: ts,
+			TypeRef:    sotReq,
+			StringValues: []AttributeValueStr{
+				val(TypeRequirement, AttrForeignID, s.ID),
+				val(TypeRequirement, AttrName, s.Title),
+			},
+		}
+		if s.Description != "" {
+			reqObj.StringValues = append(reqObj.StringValues, val(TypeRequirement, AttrText, s.Description))
+		}
+		if len(s.Tags) > 0 {
+			reqObj.StringValues = append(reqObj.StringValues, val(TypeRequirement, AttrTags, strings.Join(s.Tags, tagSep)))
+		}
+		c.Objects = append(c.Objects, reqObj)
+
+		node := Hierarchy{Identifier: idPrefix + "H_" + s.ID, LastChange: ts, ObjectRef: reqObj.Identifier}
+		for _, ex := range s.Examples {
+			exObj := SpecObject{
+				Identifier: ExampleIdentifier(s.ID, ex.ID),
+				LastChange: ts,
+				TypeRef:    sotExample,
+				StringValues: []AttributeValueStr{
+					val(TypeExample, AttrForeignID, ex.ID),
+					val(TypeExample, AttrGiven, ex.Given),
+					val(TypeExample, AttrWhen, ex.When),
+					val(TypeExample, AttrThen, ex.Then),
+				},
+			}
+			c.Objects = append(c.Objects, exObj)
+			node.Children = append(node.Children, Hierarchy{
+				Identifier: idPrefix + "H_" + s.ID + "_" + ex.ID,
+				LastChange: ts,
+				ObjectRef:  exObj.Identifier,
+			})
+		}
+		root.Children = append(root.Children, node)
+
+		for _, dep := range s.Depends {
+			c.Relations = append(c.Relations, SpecRelation{
+				Identifier: fmt.Sprintf("%s%s_depends_%s", idPrefix, s.ID, dep),
+				LastChange: ts,
+				TypeRef:    srtDepends,
+				Source:     ObjectIdentifier(s.ID),
+				Target:     ObjectIdentifier(dep),
+			})
+		}
+	}
+	c.Specifications = []Specification{root}
+
+	return doc
+}
+
+// Marshal encodes a document as indented XML with declaration.
+func Marshal(doc *Document) ([]byte, error) {
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, apperrors.Wrap("reqif.Marshal", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Parse decodes a ReqIF XML document.
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, apperrors.Wrap("reqif.Parse", err)
+	}
+	return &doc, nil
+}
+
+// Import converts a ReqIF document back into specs.
+//
+// Requirement IDs come from ReqIF.ForeignID, then from identifiers written by
+// Export. Requirements created in another tool have neither: they keep the
+// ID recorded for their SPEC-OBJECT IDENTIFIER in identifiers (by an earlier
+// import), or are given the next number after existingIDs and all IDs in the
+// document. Their specs record the IDENTIFIER in Source.ReqIFIdentifier, so
+// that importing the same file again updates them instead of adding copies.
+// Examples are read from the specification hierarchy below their
+// requirement; depends from Depends relations.
+func Import(doc *Document, existingIDs []string, identifiers map[string]string) ([]*spec.Spec, error) {
+	c := doc.Content
+
+	typeNames := make(map[string]string)
+	attrNames := make(map[string]string)
+	for _, t := range c.ObjectTypes {
+		typeNames[t.Identifier] = t.LongName
+		for _, a := range append(append([]AttributeDefinition{}, t.Attributes...), t.XHTMLAttributes...) {
+			attrNames[a.Identifier] = a.LongName
+		}
+	}
+	relNames := make(map[string]string)
+	for _, t := range c.RelationTypes {
+		relNames[t.Identifier] = t.LongName
+	}
+
+	type object struct {
+		kind   string
+		values map[string]string
+	}
+	objects := make(map[string]object, len(c.Objects))
+	var reqOrder []string
+	for _, o := range c.Objects {
+		values := make(map[string]string)
+		for _, v := range o.StringValues {
+			values[attrNames[v.DefinitionRef]] = v.Value
+		}
+		for _, v := range o.XHTMLValues {
+			values[attrNames[v.DefinitionRef]] = xhtmlText(v.Value.Inner)
+		}
+		kind := typeNames[o.TypeRef]
+		objects[o.Identifier] = object{kind: kind, values: values}
+		if kind == TypeRequirement {
+			reqOrder = append(reqOrder, o.Identifier)
+		}
+	}
+
+	// Resolve requirement IDs
+	scheme := spec.CurrentIDScheme()
+	known := append([]string(nil), existingIDs...)
+	reqIDs := make(map[string]string, len(reqOrder))
+	for _, ident := range reqOrder {
+		id := strings.TrimSpace(objects[ident].values[AttrForeignID])
+		if id == "" && strings.HasPrefix(ident, idPrefix) && scheme.Valid(strings.TrimPrefix(ident, idPrefix)) {
+			id = strings.TrimPrefix(ident, idPrefix)
+		}
+		reqIDs[ident] = id
+		known = append(known, id)
+	}
+	seen := make(map[string]string, len(reqOrder))
+	foreign := make(map[string]bool)
+	for _, ident := range reqOrder {
+		if reqIDs[ident] == "" {
+			foreign[ident] = true
+			id, ok := identifiers[ident]
+			if !ok {
+				id = scheme.Next(scheme.DefaultPrefix(), known)
+			}
+			reqIDs[ident] = id
+			known = append(known, id)
+		}
+		id := reqIDs[ident]
+		if prev, dup := seen[id]; dup {
+			return nil, apperrors.New("reqif.Import", apperrors.ErrInvalidInput,
+				fmt.Sprintf("SPEC-OBJECTs %s and %s share requirement ID %s", prev, ident, id))
+		}
+		seen[id] = ident
+	}
+
+	specs := make(map[string]*spec.Spec, len(reqOrder))
+	for _, ident := range reqOrder {
+		v := objects[ident].values
+		s := &spec.Spec{
+			ID:          reqIDs[ident],
+			Title:       strings.TrimSpace(v[AttrName]),
+			Description: strings.TrimSpace(v[AttrText]),
+		}
+		if foreign[ident] {
+			s.Source.ReqIFIdentifier = ident
+		}
+		for _, tag := range strings.Split(v[AttrTags], ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				s.Tags = append(s.Tags, tag)
+			}
+		}
+		specs[ident] = s
+	}
+
+	// Examples from the hierarchy: example objects below a requirement node
+	var walk func(nodes []Hierarchy, owner string)
+	walk = func(nodes []Hierarchy, owner string) {
+		for _, n := range nodes {
+			next := owner
+			obj := objects[n.ObjectRef]
+			switch obj.kind {
+			case TypeRequirement:
+				next = n.ObjectRef
+			case TypeExample:
+				if s, ok := specs[owner]; ok {
+					s.Examples = append(s.Examples, spec.Example{
+						ID:    strings.TrimSpace(obj.values[AttrForeignID]),
+						Given: strings.TrimSpace(obj.values[AttrGiven]),
+						When:  strings.TrimSpace(obj.values[AttrWhen]),
+						Then:  strings.TrimSpace(obj.values[AttrThen]),
+					})
+				}
+			}
+			walk(n.Children, next)
+		}
+	}
+	for _, sp := range c.Specifications {
+		walk(sp.Children, "")
+	}
+
+	for _, r := range c.Relations {
+		if relNames[r.TypeRef] != RelationDepends {
+			continue
+		}
+		src, ok := specs[r.Source]
+		if !ok {
+			continue
+		}
+		target, ok := reqIDs[r.Target]
+		if !ok {
+			return nil, apperrors.New("reqif.Import", apperrors.ErrInvalidInput,
+				fmt.Sprintf("relation %s targets unknown requirement %s", r.Identifier, r.Target))
+		}
+		src.Depends = append(src.Depends, target)
+	}
+
+	out := make([]*spec.Spec, 0, len(reqOrder))
+	for _, ident := range reqOrder {
+		s := specs[ident]
+		s.Normalize()
+		if err := s.Validate(); err != nil {
+			return nil, apperrors.Wrapf("reqif.Import", err, "SPEC-OBJECT %s", ident)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// xhtmlText reduces an XHTML fragment to plain text.
+func xhtmlText(inner string) string {
+	text := xhtmlBreak.ReplaceAllString(inner, "\n")
+	text = xhtmlTagRegex.ReplaceAllString(text, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
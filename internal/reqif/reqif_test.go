@@ -0,0 +1,178 @@
+package reqif
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func sampleSpecs() []*spec.Spec {
+	return []*spec.Spec{
+		{
+			ID:          "REQ-001",
+			Title:       "Login",
+			Description: "User logs in",
+			Tags:        []string{"auth", "web"},
+			Examples: []spec.Example{
+				{ID: "E1", Given: "valid user", When: "login", Then: "success"},
+				{ID: "E2", Given: "bad password", When: "login", Then: "error"},
+			},
+		},
+		{
+			ID:      "REQ-002",
+			Title:   "Logout",
+			Depends: []string{"REQ-001"},
+			Examples: []spec.Example{
+				{ID: "E1", Given: "logged in", When: "logout", Then: "session cleared"},
+			},
+		},
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err := Marshal(Export(sampleSpecs(), now))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "<?xml") {
+		t.Errorf("expected XML declaration, got %q", string(data[:20]))
+	}
+	if !strings.Contains(string(data), `IDENTIFIER="_spec-tdd_REQ-001_E2"`) {
+		t.Errorf("expected stable example identifier in output")
+	}
+
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	got, err := Import(doc, nil, nil)
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+
+	want := sampleSpecs()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d specs, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID || got[i].Title != want[i].Title || got[i].Description != want[i].Description {
+			t.Errorf("spec %d mismatch: got %+v", i, got[i])
+		}
+		if strings.Join(got[i].Tags, ",") != strings.Join(want[i].Tags, ",") {
+			t.Errorf("spec %d tags = %v, want %v", i, got[i].Tags, want[i].Tags)
+		}
+		if strings.Join(got[i].Depends, ",") != strings.Join(want[i].Depends, ",") {
+			t.Errorf("spec %d depends = %v, want %v", i, got[i].Depends, want[i].Depends)
+		}
+		if len(got[i].Examples) != len(want[i].Examples) {
+			t.Fatalf("spec %d examples = %+v", i, got[i].Examples)
+		}
+		for j := range want[i].Examples {
+			if got[i].Examples[j] != want[i].Examples[j] {
+				t.Errorf("spec %d example %d = %+v, want %+v", i, j, got[i].Examples[j], want[i].Examples[j])
+			}
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Run("identifier used when foreign ID is dropped", func(t *testing.T) {
+		doc := Export(sampleSpecs()[:1], time.Now())
+		obj := &doc.Content.Objects[0]
+		obj.StringValues = obj.StringValues[1:]
+
+		got, err := Import(doc, nil, nil)
+		if err != nil {
+			t.Fatalf("Import error: %v", err)
+		}
+		if got[0].ID != "REQ-001" {
+			t.Errorf("expected REQ-001 from identifier, got %s", got[0].ID)
+		}
+	})
+
+	t.Run("objects created in another tool get the next ID", func(t *testing.T) {
+		doc := Export(sampleSpecs()[:1], time.Now())
+		doc.Content.Objects = append(doc.Content.Objects, SpecObject{
+			Identifier: "ext-42",
+			TypeRef:    sotReq,
+			StringValues: []AttributeValueStr{
+				{Value: "Reset password", DefinitionRef: attrID(TypeRequirement, AttrName)},
+			},
+		})
+
+		got, err := Import(doc, []string{"REQ-005"}, nil)
+		if err != nil {
+			t.Fatalf("Import error: %v", err)
+		}
+		if len(got) != 2 || got[1].ID != "REQ-006" || got[1].Title != "Reset password" || got[1].Source.ReqIFIdentifier != "ext-42" {
+			t.Errorf("unexpected specs: %+v", got[1])
+		}
+		if got[0].Source.ReqIFIdentifier != "" {
+			t.Errorf("exported requirement should not record its identifier, got %q", got[0].Source.ReqIFIdentifier)
+		}
+
+		got, err = Import(doc, []string{"REQ-005", "REQ-006"}, map[string]string{"ext-42": "REQ-006"})
+		if err != nil {
+			t.Fatalf("Import error: %v", err)
+		}
+		if got[1].ID != "REQ-006" {
+			t.Errorf("expected the identifier to keep REQ-006, got %s", got[1].ID)
+		}
+	})
+
+	t.Run("XHTML text values", func(t *testing.T) {
+		xmlDoc := `<?xml version="1.0"?>
+<REQ-IF xmlns="http://www.omg.org/spec/ReqIF/20110401/reqif.xsd">
+  <THE-HEADER><REQ-IF-HEADER IDENTIFIER="h"/></THE-HEADER>
+  <CORE-CONTENT><REQ-IF-CONTENT>
+    <SPEC-TYPES>
+      <SPEC-OBJECT-TYPE IDENTIFIER="t1" LONG-NAME="Requirement" LAST-CHANGE="x">
+        <SPEC-ATTRIBUTES>
+          <ATTRIBUTE-DEFINITION-STRING IDENTIFIER="a-name" LONG-NAME="ReqIF.Name" LAST-CHANGE="x"/>
+          <ATTRIBUTE-DEFINITION-XHTML IDENTIFIER="a-text" LONG-NAME="ReqIF.Text" LAST-CHANGE="x"/>
+        </SPEC-ATTRIBUTES>
+      </SPEC-OBJECT-TYPE>
+    </SPEC-TYPES>
+    <SPEC-OBJECTS>
+      <SPEC-OBJECT IDENTIFIER="o1" LAST-CHANGE="x">
+        <TYPE><SPEC-OBJECT-TYPE-REF>t1</SPEC-OBJECT-TYPE-REF></TYPE>
+        <VALUES>
+          <ATTRIBUTE-VALUE-STRING THE-VALUE="Search">
+            <DEFINITION><ATTRIBUTE-DEFINITION-STRING-REF>a-name</ATTRIBUTE-DEFINITION-STRING-REF></DEFINITION>
+          </ATTRIBUTE-VALUE-STRING>
+          <ATTRIBUTE-VALUE-XHTML>
+            <DEFINITION><ATTRIBUTE-DEFINITION-XHTML-REF>a-text</ATTRIBUTE-DEFINITION-XHTML-REF></DEFINITION>
+            <THE-VALUE><xhtml:div xmlns:xhtml="http://www.w3.org/1999/xhtml"><xhtml:p>Find items &amp; filter</xhtml:p></xhtml:div></THE-VALUE>
+          </ATTRIBUTE-VALUE-XHTML>
+        </VALUES>
+      </SPEC-OBJECT>
+    </SPEC-OBJECTS>
+  </REQ-IF-CONTENT></CORE-CONTENT>
+</REQ-IF>`
+		doc, err := Parse([]byte(xmlDoc))
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		got, err := Import(doc, nil, nil)
+		if err != nil {
+			t.Fatalf("Import error: %v", err)
+		}
+		if got[0].ID != "REQ-001" || got[0].Description != "Find items & filter" {
+			t.Errorf("unexpected spec: %+v", got[0])
+		}
+	})
+
+	t.Run("duplicate requirement IDs are rejected", func(t *testing.T) {
+		doc := Export(sampleSpecs()[:1], time.Now())
+		dup := doc.Content.Objects[0]
+		dup.Identifier = "copy"
+		doc.Content.Objects = append(doc.Content.Objects, dup)
+
+		if _, err := Import(doc, nil, nil); err == nil {
+			t.Fatal("expected duplicate ID error")
+		}
+	})
+}
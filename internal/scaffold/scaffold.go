@@ -33,21 +33,20 @@ func RenderTest(s *spec.Spec, runner string) string {
 	sb.WriteString(fmt.Sprintf("describe(%q, () => {\n", desc))
 
 	examples := s.Examples
-	if len(examples) == 0 {
+	if len(examples) == 0 && len(s.Rules) == 0 {
 		examples = []spec.Example{{ID: "E1", Given: "TODO", When: "TODO", Then: "TODO: add examples"}}
 	}
 
 	for i, ex := range examples {
-		exID := strings.TrimSpace(ex.ID)
-		if exID == "" {
-			exID = fmt.Sprintf("E%d", i+1)
+		renderExample(&sb, s.ID, ex, fmt.Sprintf("E%d", i+1), "  ")
+	}
+
+	// Each rule gets its own describe block so the test file mirrors the example map
+	for _, r := range s.Rules {
+		sb.WriteString(fmt.Sprintf("  describe(%q, () => {\n", fmt.Sprintf("%s: %s", r.ID, r.Text)))
+		for _, ex := range r.Examples {
+			renderExample(&sb, s.ID, ex, "E?", "    ")
 		}
-		name := fmt.Sprintf("%s %s: %s", s.ID, exID, ex.Then)
-		sb.WriteString(fmt.Sprintf("  it(%q, () => {\n", name))
-		sb.WriteString(fmt.Sprintf("    // Given: %s\n", ex.Given))
-		sb.WriteString(fmt.Sprintf("    // When: %s\n", ex.When))
-		sb.WriteString(fmt.Sprintf("    // Then: %s\n", ex.Then))
-		sb.WriteString("    throw new Error(\"TODO: implement\")\n")
 		sb.WriteString("  })\n\n")
 	}
 
@@ -55,6 +54,20 @@ func RenderTest(s *spec.Spec, runner string) string {
 	return sb.String()
 }
 
+func renderExample(sb *strings.Builder, reqID string, ex spec.Example, fallbackID, indent string) {
+	exID := strings.TrimSpace(ex.ID)
+	if exID == "" {
+		exID = fallbackID
+	}
+	name := fmt.Sprintf("%s %s: %s", reqID, exID, ex.Then)
+	sb.WriteString(fmt.Sprintf("%sit(%q, () => {\n", indent, name))
+	sb.WriteString(fmt.Sprintf("%s  // Given: %s\n", indent, ex.Given))
+	sb.WriteString(fmt.Sprintf("%s  // When: %s\n", indent, ex.When))
+	sb.WriteString(fmt.Sprintf("%s  // Then: %s\n", indent, ex.Then))
+	sb.WriteString(fmt.Sprintf("%s  throw new Error(\"TODO: implement\")\n", indent))
+	sb.WriteString(indent + "})\n\n")
+}
+
 // ApplyPattern applies a filename pattern.
 func ApplyPattern(pattern, id, slug string) string {
 	out := strings.ReplaceAll(pattern, "{{id}}", id)
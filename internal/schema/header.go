@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// headerPrefix starts the modeline read by yaml-language-server.
+const headerPrefix = "# yaml-language-server: $schema="
+
+// InjectHeader puts a `# yaml-language-server: $schema=ref` modeline on the
+// first line of a YAML file, replacing an existing one. It reports whether
+// data changed.
+func InjectHeader(data []byte, ref string) ([]byte, bool) {
+	header := headerPrefix + ref
+	text := string(data)
+	if strings.HasPrefix(text, headerPrefix) {
+		first, rest, _ := strings.Cut(text, "\n")
+		if strings.TrimRight(first, "\r ") == header {
+			return data, false
+		}
+		return []byte(header + "\n" + rest), true
+	}
+	return []byte(header + "\n" + text), true
+}
+
+// Ref returns the schema path as referenced from a YAML file: relative to the
+// file's directory, with forward slashes.
+func Ref(yamlPath, schemaPath string) string {
+	rel, err := filepath.Rel(filepath.Dir(yamlPath), schemaPath)
+	if err != nil {
+		return filepath.ToSlash(schemaPath)
+	}
+	return filepath.ToSlash(rel)
+}
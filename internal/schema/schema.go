@@ -0,0 +1,262 @@
+// Package schema generates JSON Schemas for spec files and .tdd/config.yml,
+// so that editors (e.g. via yaml-language-server) can validate and complete
+// them. The structure is derived from the Go types; the constraints mirror
+// spec.Spec.Validate and config.SpecConfig.Validate.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/lint"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema used by the generated schemas.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// nonBlank matches strings with at least one non-space character, like the
+// strings.TrimSpace checks of the validators.
+const nonBlank = `\S`
+
+// FromType derives the structure of a schema from a Go type, using the YAML
+// field names of structs.
+func FromType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: FromType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: FromType(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			s.Properties[name] = FromType(f.Type)
+		}
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// Spec returns the schema of a spec file under the given ID scheme and
+// lifecycle. The id may be omitted (it defaults to the file name), so only
+// title is required.
+func Spec(ids spec.IDScheme, lc config.LifecycleConfig) *Schema {
+	s := FromType(reflect.TypeOf(spec.Spec{}))
+	s.Schema = Draft
+	s.Title = "spec-tdd requirement"
+	s.Required = []string{"title"}
+
+	version := describe(s, "version", "File format version (omitted means 1; run `spec-tdd migrate` to upgrade)")
+	version.Minimum, version.Maximum = intPtr(1), intPtr(spec.FormatVersion)
+
+	idPattern := "^(?:" + ids.Pattern() + ")$"
+	describe(s, "id", "Requirement ID, e.g. "+ids.Describe()+" (defaults to the file name)").Pattern = idPattern
+	describe(s, "title", "Short requirement title").Pattern = nonBlank
+	status := describe(s, "status", "Lifecycle status (see `req status`)")
+	status.Enum = lc.States()
+	describe(s, "parent", "Parent requirement ID (epic or feature); must not be the spec itself").Pattern = idPattern
+	describe(s, "description", "Free-form description")
+	deps := describe(s, "depends", "Requirement IDs this one depends on; must not include the spec itself")
+	deps.Items.Pattern = idPattern
+	deps.UniqueItems = true
+	describe(s, "tags", "Free-form tags")
+	describe(s, "source", "Where the requirement was imported from")
+
+	example := exampleSchema()
+	describe(s, "examples", "Given/When/Then examples").Items = example
+
+	rule := at(s, "rules[]")
+	rule.Required = []string{"text"}
+	describe(rule, "id", "Rule ID, e.g. R1").Pattern = `^R\d+$`
+	describe(rule, "text", "The business rule").Pattern = nonBlank
+	describe(rule, "examples", "Examples illustrating the rule").Items = example
+	describe(s, "rules", "Business rules with their examples (example IDs are shared with top-level examples)")
+
+	question := at(s, "questions[]")
+	question.Required = []string{"text"}
+	describe(question, "id", "Question ID, e.g. Q1")
+	describe(question, "text", "The open question").Pattern = nonBlank
+	describe(question, "status", "open (default) or resolved").Enum = []string{spec.QuestionOpen, spec.QuestionResolved}
+	describe(question, "owner", "Who is expected to answer")
+	describe(question, "answer", "The answer, once resolved")
+	describe(question, "resolved_at", "When the question was resolved")
+	// Older spec files list questions as plain strings
+	at(s, "questions").Items = &Schema{OneOf: []*Schema{
+		{Type: "string", Pattern: nonBlank},
+		question,
+	}}
+	describe(s, "questions", "Open questions (red cards)")
+
+	approval := at(s, "approvals[]")
+	approval.Required = []string{"reviewer", "hash"}
+	describe(approval, "reviewer", "Who approved the spec (one approval per reviewer)").Pattern = nonBlank
+	describe(approval, "approved_at", "When the spec was approved")
+	describe(approval, "hash", "Content hash of the approved spec; the approval is stale once it no longer matches").Pattern = `^[0-9a-f]{64}$`
+	describe(approval, "note", "Free-form comment")
+	describe(s, "approvals", "Review sign-offs recorded by `req approve`")
+	return s
+}
+
+func exampleSchema() *Schema {
+	ex := FromType(reflect.TypeOf(spec.Example{}))
+	ex.Required = []string{"given", "when", "then"}
+	describe(ex, "id", "Example ID, e.g. E1 (used in test names)")
+	describe(ex, "given", "Precondition").Pattern = nonBlank
+	describe(ex, "when", "Action").Pattern = nonBlank
+	describe(ex, "then", "Expected outcome").Pattern = nonBlank
+	return ex
+}
+
+// Config returns the schema of .tdd/config.yml. Every key has a default, so
+// none is required.
+func Config() *Schema {
+	s := FromType(reflect.TypeOf(config.SpecConfig{}))
+	s.Schema = Draft
+	s.Title = "spec-tdd configuration (.tdd/config.yml)"
+
+	version := describe(s, "version", "File format version (omitted means 1)")
+	version.Minimum, version.Maximum = intPtr(1), intPtr(config.ConfigVersion)
+	describe(s, "specDir", "Directory of the spec YAML files (default .tdd/specs)").Pattern = nonBlank
+	describe(s, "testDir", "Directory of the test files (default tests)").Pattern = nonBlank
+	describe(s, "runner", "Test runner").Enum = []string{"vitest", "jest"}
+	describe(s, "fileNamePattern", "Test file name; must include {{id}}").Pattern = `\{\{id\}\}`
+
+	describe(s, "ids", "Requirement ID scheme")
+	prefixes := describe(s, "ids.prefixes", "Allowed ID prefixes; the first is the default (default [REQ])")
+	prefixes.Items.Pattern = `^[A-Za-z][A-Za-z0-9_]*$`
+	prefixes.UniqueItems = true
+	describe(s, "ids.padding", "Minimum number of digits of new IDs (default 3)").Minimum = intPtr(0)
+	describe(s, "ids.numbering", "Numbering across prefixes").Enum = []string{spec.NumberingPerPrefix, spec.NumberingGlobal}
+	describe(s, "ids.provisional", "Provisional IDs for parallel branches").Enum = []string{spec.ProvisionalOff, spec.ProvisionalBranch, spec.ProvisionalULID}
+
+	describe(s, "csvColumns", "Column header for each spec field in CSV/TSV import and export")
+	at(s, "csvColumns{}").Pattern = nonBlank
+
+	describe(s, "lifecycle", "Requirement status state machine (default draft → ready → in-progress → implemented)")
+	describe(s, "lifecycle.initial", "Status of new requirements")
+	describe(s, "lifecycle.transitions", "Allowed next statuses for each status")
+	describe(s, "lifecycle.excluded", "Statuses left out of coverage reports")
+
+	describe(s, "readiness", "Definition of Ready")
+	describe(s, "readiness.rules", "Enabled checks").Items.Enum = config.ReadyRules
+	describe(s, "readiness.placeholders", "Words treated as unfinished content")
+	describe(s, "readiness.scaffold", "What scaffold does with specs that are not ready").Enum = []string{config.EnforceOff, config.EnforceWarn, config.EnforceError}
+
+	ruleIDs := lintRuleIDs()
+	describe(s, "lint", "Lint rule selection and severities")
+	describe(s, "lint.disable", "Rules to turn off").Items.Enum = ruleIDs
+	severity := describe(s, "lint.severity", "Severity override per rule")
+	severity.PropertyNames = &Schema{Enum: ruleIDs}
+	severity.AdditionalProperties.Enum = []string{string(lint.SeverityError), string(lint.SeverityWarning), string(lint.SeverityInfo)}
+	describe(s, "lint.maxTitleLength", "Longest title before the long-title rule fires").Minimum = intPtr(0)
+	describe(s, "lint.vagueWords", "Words flagged as vague (replaces the built-in list)")
+	return s
+}
+
+// lintRuleIDs lists the built-in lint rules, which lint.New checks
+// lint.disable and lint.severity against.
+func lintRuleIDs() []string {
+	var ids []string
+	for _, r := range lint.Builtin(config.LintConfig{}) {
+		ids = append(ids, r.ID())
+	}
+	return ids
+}
+
+// Marshal encodes a schema as indented JSON.
+func Marshal(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, apperrors.Wrap("schema.Marshal", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// at returns the sub-schema at path: dot-separated property names, where a
+// "[]" suffix selects array items and "{}" map values. It panics on a path
+// the types do not have, so a renamed field fails loudly in tests.
+func at(s *Schema, path string) *Schema {
+	cur := s
+	for _, part := range strings.Split(path, ".") {
+		name := strings.TrimRight(part, "[]{}")
+		next, ok := cur.Properties[name]
+		if !ok {
+			panic(fmt.Sprintf("schema: no property %q in path %q", name, path))
+		}
+		cur = next
+		for suffix := part[len(name):]; suffix != ""; suffix = suffix[2:] {
+			switch suffix[:2] {
+			case "[]":
+				cur = cur.Items
+			case "{}":
+				cur = cur.AdditionalProperties
+			}
+			if cur == nil {
+				panic(fmt.Sprintf("schema: %q is not a list or map in path %q", name, path))
+			}
+		}
+	}
+	return cur
+}
+
+func describe(s *Schema, path, description string) *Schema {
+	p := at(s, path)
+	p.Description = description
+	return p
+}
+
+func intPtr(n int) *int {
+	return &n
+}
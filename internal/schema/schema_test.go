@@ -0,0 +1,288 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/config"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+	"go.yaml.in/yaml/v3"
+)
+
+// check validates doc against s with the subset of JSON Schema the
+// generated schemas use. It returns the first violation, or "".
+func check(s *Schema, doc any, path string) string {
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, alt := range s.OneOf {
+			if check(alt, doc, path) == "" {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return path + ": must match exactly one alternative"
+		}
+		return ""
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, fmt.Sprint(doc)) {
+		return path + ": not in enum"
+	}
+	switch v := doc.(type) {
+	case string:
+		if s.Type != "" && s.Type != "string" {
+			return path + ": unexpected string"
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(v) {
+			return path + ": does not match " + s.Pattern
+		}
+	case int:
+		if s.Type != "" && s.Type != "integer" {
+			return path + ": unexpected integer"
+		}
+		if s.Minimum != nil && v < *s.Minimum {
+			return path + ": below minimum"
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return path + ": above maximum"
+		}
+	case []any:
+		if s.Type != "array" {
+			return path + ": unexpected array"
+		}
+		for i, item := range v {
+			if msg := check(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); msg != "" {
+				return msg
+			}
+			if s.UniqueItems && slices.Index(v, item) != i {
+				return path + ": duplicate item"
+			}
+		}
+	case map[string]any:
+		if s.Type != "object" {
+			return path + ": unexpected object"
+		}
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				return path + ": missing " + key
+			}
+		}
+		for key, value := range v {
+			if s.PropertyNames != nil {
+				if msg := check(s.PropertyNames, key, path+"."+key); msg != "" {
+					return msg
+				}
+			}
+			sub := s.Properties[key]
+			if sub == nil {
+				sub = s.AdditionalProperties
+			}
+			if sub == nil {
+				continue
+			}
+			if msg := check(sub, value, path+"."+key); msg != "" {
+				return msg
+			}
+		}
+	default:
+		if s.Type != "" && s.Type != "boolean" {
+			return fmt.Sprintf("%s: unexpected %T", path, doc)
+		}
+	}
+	return ""
+}
+
+func decode(t *testing.T, text string) any {
+	t.Helper()
+	var doc any
+	if err := yaml.Unmarshal([]byte(text), &doc); err != nil {
+		t.Fatalf("yaml error: %v", err)
+	}
+	return doc
+}
+
+// The schema must accept exactly what spec.Load accepts.
+func TestSpecSchemaMatchesValidate(t *testing.T) {
+	s := Spec(spec.CurrentIDScheme(), config.DefaultLifecycle())
+	tests := []struct {
+		name  string
+		yaml  string
+		valid bool
+	}{
+		{"minimal", "title: Login\n", true},
+		{"full", `version: 2
+id: REQ-001
+title: Login
+status: ready
+parent: REQ-002
+depends: [REQ-003, REQ-tmp-feature-x.1]
+tags: [auth]
+examples:
+  - {id: E1, given: a, when: b, then: c}
+rules:
+  - id: R1
+    text: Lockout
+    examples: [{id: E2, given: a, when: b, then: c}]
+questions:
+  - Legacy question?
+  - {id: Q1, text: "Threshold?", status: resolved, answer: "5"}
+approvals:
+  - reviewer: alice
+    approved_at: "2026-03-01T09:00:00Z"
+    hash: 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+    note: LGTM
+`, true},
+		{"newer version", "version: 3\ntitle: x\n", false},
+		{"blank title", "title: '  '\n", false},
+		{"missing title", "id: REQ-001\n", false},
+		{"bad id", "id: R-1\ntitle: x\n", false},
+		{"bad parent", "title: x\nparent: epic\n", false},
+		{"bad depends", "title: x\ndepends: [REQ-1x]\n", false},
+		{"duplicate depends", "title: x\ndepends: [REQ-002, REQ-002]\n", false},
+		{"example without then", "title: x\nexamples: [{given: a, when: b}]\n", false},
+		{"blank given", "title: x\nexamples: [{given: ' ', when: b, then: c}]\n", false},
+		{"bad rule id", "title: x\nrules: [{id: Rule1, text: t}]\n", false},
+		{"rule without text", "title: x\nrules: [{id: R1}]\n", false},
+		{"bad question status", "title: x\nquestions: [{text: q, status: closed}]\n", false},
+		{"question without text", "title: x\nquestions: [{owner: me}]\n", false},
+		{"approval without hash", "title: x\napprovals: [{reviewer: alice}]\n", false},
+		{"approval with bad hash", "title: x\napprovals: [{reviewer: alice, hash: abc}]\n", false},
+		{"approval without reviewer", "title: x\napprovals: [{hash: " + strings.Repeat("0", 64) + "}]\n", false},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "REQ-001.yml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, loadErr := spec.Load(path)
+			msg := check(s, decode(t, tt.yaml), "$")
+			if (loadErr == nil) != tt.valid {
+				t.Errorf("spec.Load valid = %v, want %v (%v)", loadErr == nil, tt.valid, loadErr)
+			}
+			if (msg == "") != tt.valid {
+				t.Errorf("schema valid = %v, want %v (%s)", msg == "", tt.valid, msg)
+			}
+		})
+	}
+}
+
+func TestSpecSchemaStatusEnum(t *testing.T) {
+	s := Spec(spec.CurrentIDScheme(), config.DefaultLifecycle())
+	if msg := check(s, decode(t, "title: x\nstatus: in-progress\n"), "$"); msg != "" {
+		t.Errorf("in-progress should be accepted: %s", msg)
+	}
+	if msg := check(s, decode(t, "title: x\nstatus: done\n"), "$"); msg == "" {
+		t.Error("status outside the lifecycle should be rejected")
+	}
+}
+
+func TestSpecSchemaUsesIDScheme(t *testing.T) {
+	ids := spec.NewIDScheme([]string{"AUTH", "BILL"}, 3, spec.NumberingPerPrefix)
+	s := Spec(ids, config.DefaultLifecycle())
+	for id, want := range map[string]bool{"AUTH-012": true, "BILL-1": true, "REQ-001": false, "AUTH-tmp-main.2": true} {
+		if got := check(s.Properties["id"], id, "$.id") == ""; got != want {
+			t.Errorf("id %q valid = %v, want %v", id, got, want)
+		}
+		if got := ids.Valid(id); got != want {
+			t.Errorf("IDScheme.Valid(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+// The schema must accept exactly what config.LoadSpecConfig accepts.
+func TestConfigSchemaMatchesValidate(t *testing.T) {
+	s := Config()
+	tests := []struct {
+		name  string
+		yaml  string
+		valid bool
+	}{
+		{"empty", "{}\n", true},
+		{"full", `version: 1
+specDir: specs
+testDir: test
+runner: jest
+fileNamePattern: "{{id}}.spec.ts"
+ids: {prefixes: [AUTH, BILL_2], padding: 4, numbering: global, provisional: ulid}
+csvColumns: {id: Key}
+readiness: {rules: [has-examples], scaffold: warn}
+lint: {disable: [vague-words], severity: {title-length: error}, maxTitleLength: 80}
+`, true},
+		{"newer version", "version: 2\n", false},
+		{"bad runner", "runner: mocha\n", false},
+		{"pattern without id", "fileNamePattern: test.ts\n", false},
+		{"bad prefix", "ids: {prefixes: [B-1]}\n", false},
+		{"duplicate prefix", "ids: {prefixes: [A, A]}\n", false},
+		{"negative padding", "ids: {padding: -1}\n", false},
+		{"bad numbering", "ids: {numbering: shared}\n", false},
+		{"bad provisional", "ids: {provisional: uuid}\n", false},
+		{"blank csv column", "csvColumns: {id: ' '}\n", false},
+		{"unknown ready rule", "readiness: {rules: [has-owner]}\n", false},
+		{"bad scaffold mode", "readiness: {scaffold: strict}\n", false},
+		{"bad severity", "lint: {severity: {title-length: fatal}}\n", false},
+		{"negative title length", "lint: {maxTitleLength: -1}\n", false},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, _, loadErr := config.LoadSpecConfig(path)
+			msg := check(s, decode(t, tt.yaml), "$")
+			if (loadErr == nil) != tt.valid {
+				t.Errorf("LoadSpecConfig valid = %v, want %v (%v)", loadErr == nil, tt.valid, loadErr)
+			}
+			if (msg == "") != tt.valid {
+				t.Errorf("schema valid = %v, want %v (%s)", msg == "", tt.valid, msg)
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(Spec(spec.CurrentIDScheme(), config.DefaultLifecycle()))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc["$schema"] != Draft || doc["title"] != "spec-tdd requirement" {
+		t.Errorf("unexpected header: %v %v", doc["$schema"], doc["title"])
+	}
+}
+
+func TestInjectHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		changed bool
+	}{
+		{"adds", "id: REQ-001\n", "# yaml-language-server: $schema=../schema/spec.schema.json\nid: REQ-001\n", true},
+		{"keeps", "# yaml-language-server: $schema=../schema/spec.schema.json\nid: REQ-001\n", "# yaml-language-server: $schema=../schema/spec.schema.json\nid: REQ-001\n", false},
+		{"replaces", "# yaml-language-server: $schema=old.json\nid: REQ-001\n", "# yaml-language-server: $schema=../schema/spec.schema.json\nid: REQ-001\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := InjectHeader([]byte(tt.in), "../schema/spec.schema.json")
+			if string(got) != tt.want || changed != tt.changed {
+				t.Errorf("InjectHeader = %q, %v; want %q, %v", got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+
+	if ref := Ref(filepath.Join(".tdd", "specs", "REQ-001.yml"), filepath.Join(".tdd", "schema", "spec.schema.json")); ref != "../schema/spec.schema.json" {
+		t.Errorf("Ref = %q", ref)
+	}
+}
@@ -0,0 +1,130 @@
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+var hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Approval records that a reviewer signed off a spec. Hash is the
+// ContentHash of the spec at that time, so that any later change of its
+// content makes the approval stale.
+type Approval struct {
+	Reviewer   string    `yaml:"reviewer"`
+	ApprovedAt time.Time `yaml:"approved_at,omitempty"`
+	Hash       string    `yaml:"hash"`
+	Note       string    `yaml:"note,omitempty"`
+}
+
+// Stale reports whether s changed since the approval.
+func (a Approval) Stale(s *Spec) bool {
+	return a.Hash != ContentHash(s)
+}
+
+// content is the part of a spec covered by ContentHash.
+type content struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Parent      string     `json:"parent,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Depends     []string   `json:"depends,omitempty"`
+	Examples    []Example  `json:"examples,omitempty"`
+	Rules       []Rule     `json:"rules,omitempty"`
+	Questions   []Question `json:"questions,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// ContentHash returns the hash of the normalized content of s. The status,
+// source, format version and approvals are left out: moving a requirement
+// through its lifecycle, re-importing or migrating it, or approving it does
+// not change what it requires.
+func ContentHash(s *Spec) string {
+	c := s.Clone()
+	c.Normalize()
+	data, _ := json.Marshal(content{
+		ID:          c.ID,
+		Title:       c.Title,
+		Parent:      c.Parent,
+		Description: c.Description,
+		Depends:     c.Depends,
+		Examples:    c.Examples,
+		Rules:       c.Rules,
+		Questions:   c.Questions,
+		Tags:        c.Tags,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Clone returns a copy of s that shares no slices with it, so that the copy
+// can be normalized or edited without touching s.
+func (s *Spec) Clone() *Spec {
+	c := *s
+	c.Depends = slices.Clone(s.Depends)
+	c.Examples = slices.Clone(s.Examples)
+	c.Rules = slices.Clone(s.Rules)
+	for i := range c.Rules {
+		c.Rules[i].Examples = slices.Clone(c.Rules[i].Examples)
+	}
+	c.Questions = slices.Clone(s.Questions)
+	c.Tags = slices.Clone(s.Tags)
+	c.Approvals = slices.Clone(s.Approvals)
+	c.Source.HeadingPath = slices.Clone(s.Source.HeadingPath)
+	return &c
+}
+
+// Approve records reviewer's approval of s in its current form, replacing
+// an earlier approval by the same reviewer.
+func (s *Spec) Approve(reviewer, note string, at time.Time) Approval {
+	a := Approval{Reviewer: reviewer, ApprovedAt: at.UTC().Truncate(time.Second), Hash: ContentHash(s), Note: note}
+	for i := range s.Approvals {
+		if s.Approvals[i].Reviewer == reviewer {
+			s.Approvals[i] = a
+			return a
+		}
+	}
+	s.Approvals = append(s.Approvals, a)
+	return a
+}
+
+// StaleApprovals returns the approvals of s given before its last change.
+func (s *Spec) StaleApprovals() []Approval {
+	if len(s.Approvals) == 0 {
+		return nil
+	}
+	hash := ContentHash(s)
+	var out []Approval
+	for _, a := range s.Approvals {
+		if a.Hash != hash {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func validateApprovals(approvals []Approval) error {
+	seen := make(map[string]bool, len(approvals))
+	for i, a := range approvals {
+		if strings.TrimSpace(a.Reviewer) == "" {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, fmt.Sprintf("approval %d must include reviewer", i+1))
+		}
+		if !hashPattern.MatchString(a.Hash) {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+				fmt.Sprintf("approval %d must include the hash of the approved spec (64 hex digits)", i+1))
+		}
+		if seen[a.Reviewer] {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, fmt.Sprintf("duplicate approval by %q", a.Reviewer))
+		}
+		seen[a.Reviewer] = true
+	}
+	return nil
+}
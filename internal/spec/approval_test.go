@@ -0,0 +1,118 @@
+package spec
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContentHash(t *testing.T) {
+	base := func() *Spec {
+		return &Spec{ID: "REQ-001", Title: "Login", Status: "draft",
+			Examples: []Example{{Given: "registered user", When: "login", Then: "dashboard shown"}}}
+	}
+	hash := ContentHash(base())
+
+	tests := []struct {
+		name    string
+		edit    func(s *Spec)
+		changed bool
+	}{
+		{"status", func(s *Spec) { s.Status = "approved" }, false},
+		{"source", func(s *Spec) { s.Source.FilePath = "docs/login.md" }, false},
+		{"version", func(s *Spec) { s.Version = FormatVersion }, false},
+		{"approvals", func(s *Spec) { s.Approve("alice", "", time.Now()) }, false},
+		{"missing IDs filled in", func(s *Spec) { s.Examples[0].ID = "E1" }, false},
+		{"title", func(s *Spec) { s.Title = "Sign in" }, true},
+		{"example", func(s *Spec) { s.Examples[0].Then = "home shown" }, true},
+		{"depends", func(s *Spec) { s.Depends = []string{"REQ-002"} }, true},
+		{"question", func(s *Spec) { s.Questions = QuestionsFromText([]string{"SSO?"}) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := base()
+			tt.edit(s)
+			if got := ContentHash(s) != hash; got != tt.changed {
+				t.Errorf("hash changed = %v, want %v", got, tt.changed)
+			}
+		})
+	}
+
+	t.Run("does not normalize the spec", func(t *testing.T) {
+		s := base()
+		ContentHash(s)
+		if s.Examples[0].ID != "" {
+			t.Errorf("ContentHash modified the spec: example ID %q", s.Examples[0].ID)
+		}
+	})
+}
+
+func TestApprove(t *testing.T) {
+	s := &Spec{ID: "REQ-001", Title: "Login"}
+	first := time.Date(2026, 3, 1, 9, 0, 0, 500, time.UTC)
+	a := s.Approve("alice", "looks good", first)
+	if !a.ApprovedAt.Equal(first.Truncate(time.Second)) || a.Hash != ContentHash(s) || a.Stale(s) {
+		t.Fatalf("unexpected approval %+v", a)
+	}
+	s.Approve("bob", "", first)
+
+	s.Title = "Sign in"
+	if got := len(s.StaleApprovals()); got != 2 {
+		t.Fatalf("stale approvals = %d, want 2", got)
+	}
+
+	t.Run("renewing replaces the reviewer's approval", func(t *testing.T) {
+		s.Approve("alice", "", first.Add(time.Hour))
+		if len(s.Approvals) != 2 {
+			t.Fatalf("approvals = %+v, want 2 entries", s.Approvals)
+		}
+		stale := s.StaleApprovals()
+		if len(stale) != 1 || stale[0].Reviewer != "bob" {
+			t.Errorf("stale approvals = %+v, want only bob", stale)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "REQ-001.yml")
+		if err := Save(path, s); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		if len(loaded.Approvals) != 2 || loaded.Approvals[0].Stale(loaded) || !loaded.Approvals[1].Stale(loaded) {
+			t.Errorf("unexpected approvals after reload: %+v", loaded.Approvals)
+		}
+	})
+}
+
+func TestValidateApprovals(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+	tests := []struct {
+		name      string
+		approvals []Approval
+		wantErr   string
+	}{
+		{"valid", []Approval{{Reviewer: "alice", Hash: hash}, {Reviewer: "bob", Hash: hash}}, ""},
+		{"missing reviewer", []Approval{{Reviewer: " ", Hash: hash}}, "must include reviewer"},
+		{"bad hash", []Approval{{Reviewer: "alice", Hash: "abc"}}, "hash"},
+		{"duplicate reviewer", []Approval{{Reviewer: "alice", Hash: hash}, {Reviewer: "alice", Hash: hash}}, "duplicate approval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Spec{ID: "REQ-001", Title: "Login", Approvals: tt.approvals}
+			err := s.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package spec
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/migrate"
+	"github.com/thirdlf03/spec-tdd/internal/yamlnode"
+	"go.yaml.in/yaml/v3"
+)
+
+// document is the parsed YAML of a spec file. Marshal merges the spec's
+// current values into it, so comments, key order, scalar styles, indentation
+// and keys unknown to Spec (added by other tools) survive programmatic edits.
+type document struct {
+	root   *yaml.Node // the document node; root.Content[0] is the mapping
+	indent int
+}
+
+// parseDocument parses data as a spec document. It returns nil when data is
+// not a YAML mapping, in which case Marshal falls back to plain encoding.
+func parseDocument(data []byte) *document {
+	root := yamlnode.Parse(data)
+	if root == nil {
+		return nil
+	}
+	return &document{root: root, indent: yamlnode.DetectIndent(data)}
+}
+
+// version returns the format version recorded in the document, or 0 when
+// it has none.
+func (d *document) version() int {
+	if v := yamlnode.Get(d.root.Content[0], migrate.VersionKey); v != nil {
+		n, _ := strconv.Atoi(v.Value)
+		return n
+	}
+	return 0
+}
+
+// encode merges s into the document and encodes the result. The parsed
+// document itself is not modified.
+func (d *document) encode(s *Spec) ([]byte, error) {
+	var fresh yaml.Node
+	if err := fresh.Encode(s); err != nil {
+		return nil, err
+	}
+	root := *d.root
+	root.Content = []*yaml.Node{mergeNode(d.root.Content[0], &fresh, reflect.TypeOf(Spec{}))}
+
+	return yamlnode.Encode(&root, d.indent)
+}
+
+// mergeNode returns next with the formatting of prev wherever the two agree
+// in shape. t is the Go type next was encoded from; it tells known keys
+// (replaced or removed) apart from unknown ones (kept as they are).
+func mergeNode(prev, next *yaml.Node, t reflect.Type) *yaml.Node {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if prev == nil || prev.Kind != next.Kind || prev.Kind == yaml.AliasNode {
+		out := *next
+		if prev != nil {
+			out.HeadComment, out.LineComment, out.FootComment = prev.HeadComment, prev.LineComment, prev.FootComment
+		}
+		return &out
+	}
+
+	out := *prev
+	switch prev.Kind {
+	case yaml.ScalarNode:
+		if prev.Value == next.Value && prev.ShortTag() == next.ShortTag() {
+			return &out
+		}
+		out.Value, out.Tag = next.Value, next.Tag
+		// Keep a quoted or block style for strings; anything else takes the
+		// style the encoder chose, which is always valid for the new value
+		if prev.Style == 0 || next.ShortTag() != "!!str" {
+			out.Style = next.Style
+		}
+	case yaml.MappingNode:
+		out.Content = mergeMapping(prev, next, t)
+	case yaml.SequenceNode:
+		out.Content = mergeSequence(prev, next, t)
+	default:
+		return next
+	}
+	return &out
+}
+
+func mergeMapping(prev, next *yaml.Node, t reflect.Type) []*yaml.Node {
+	fields := yamlFields(t)
+	nextValues := make(map[string]*yaml.Node, len(next.Content)/2)
+	for i := 0; i+1 < len(next.Content); i += 2 {
+		nextValues[next.Content[i].Value] = next.Content[i+1]
+	}
+
+	content := make([]*yaml.Node, 0, len(prev.Content)+len(next.Content))
+	present := make(map[string]bool, len(nextValues))
+	for i := 0; i+1 < len(prev.Content); i += 2 {
+		key, value := prev.Content[i], prev.Content[i+1]
+		if nv, ok := nextValues[key.Value]; ok && !present[key.Value] {
+			content = append(content, key, mergeNode(value, nv, fields[key.Value]))
+			present[key.Value] = true
+			continue
+		}
+		// A known key missing from next was cleared; an unknown one stays
+		if _, known := fields[key.Value]; known || fields == nil {
+			continue
+		}
+		content = append(content, key, value)
+	}
+
+	// New keys go right after the key that precedes them in next
+	for i := 0; i+1 < len(next.Content); i += 2 {
+		key := next.Content[i].Value
+		if present[key] {
+			continue
+		}
+		at := 0
+		for j := i - 2; j >= 0; j -= 2 {
+			if idx := keyIndex(content, next.Content[j].Value); idx >= 0 {
+				at = idx + 2
+				break
+			}
+		}
+		pair := []*yaml.Node{next.Content[i], next.Content[i+1]}
+		content = append(content[:at], append(pair, content[at:]...)...)
+		present[key] = true
+	}
+	return content
+}
+
+// mergeSequence pairs items of next with items of prev: mappings by their
+// "id" key, scalars by value, anything else by position.
+func mergeSequence(prev, next *yaml.Node, t reflect.Type) []*yaml.Node {
+	var elem reflect.Type
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		elem = t.Elem()
+	}
+	used := make([]bool, len(prev.Content))
+	find := func(i int, item *yaml.Node) *yaml.Node {
+		for j, p := range prev.Content {
+			if used[j] || p.Kind != item.Kind {
+				continue
+			}
+			switch {
+			case item.Kind == yaml.ScalarNode && p.Value == item.Value,
+				item.Kind == yaml.MappingNode && mappingID(item) != "" && mappingID(p) == mappingID(item):
+				used[j] = true
+				return p
+			}
+		}
+		if i < len(prev.Content) && !used[i] && mappingID(prev.Content[i]) == "" && mappingID(item) == "" && item.Kind != yaml.ScalarNode {
+			used[i] = true
+			return prev.Content[i]
+		}
+		return nil
+	}
+
+	content := make([]*yaml.Node, 0, len(next.Content))
+	for i, item := range next.Content {
+		if p := find(i, item); p != nil {
+			content = append(content, mergeNode(p, item, elem))
+		} else {
+			content = append(content, item)
+		}
+	}
+	return content
+}
+
+// mappingID returns the scalar value of the "id" key of a mapping node.
+func mappingID(n *yaml.Node) string {
+	if v := yamlnode.Get(n, "id"); v != nil {
+		return v.Value
+	}
+	return ""
+}
+
+func keyIndex(content []*yaml.Node, key string) int {
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// yamlFields maps the YAML keys of a struct type to their field types. It
+// returns nil for anything but structs, whose keys are all data.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
@@ -0,0 +1,107 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarshalPreservesFormatting(t *testing.T) {
+	const original = `# Login requirement, owned by the auth team
+title: Login # shown in reports
+id: REQ-001
+description: |
+  Users sign in with email
+  and password.
+depends:
+  - REQ-002 # session store
+  - REQ-003
+x-jira: AUTH-42 # added by the Jira sync
+examples:
+  # happy path
+  - id: E1
+    given: "a registered user"
+    when: logging in
+    then: the dashboard is shown
+    x-reviewed: true
+`
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	t.Run("unchanged spec round-trips", func(t *testing.T) {
+		data, err := Marshal(s)
+		if err != nil {
+			t.Fatalf("Marshal error: %v", err)
+		}
+		if string(data) != original {
+			t.Errorf("round trip changed the file:\n%s", data)
+		}
+	})
+
+	t.Run("edits keep comments, order and unknown keys", func(t *testing.T) {
+		s.Title = "Sign in"
+		s.Depends = []string{"REQ-002"}
+		s.Examples[0].Then = "the dashboard opens"
+		s.Examples = append(s.Examples, Example{ID: "E2", Given: "a locked user", When: "logging in", Then: "rejected"})
+		s.Tags = []string{"auth"}
+		if err := Save(path, s); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `# Login requirement, owned by the auth team
+title: Sign in # shown in reports
+id: REQ-001
+description: |
+  Users sign in with email
+  and password.
+depends:
+  - REQ-002 # session store
+x-jira: AUTH-42 # added by the Jira sync
+examples:
+  # happy path
+  - id: E1
+    given: "a registered user"
+    when: logging in
+    then: the dashboard opens
+    x-reviewed: true
+  - id: E2
+    given: a locked user
+    when: logging in
+    then: rejected
+tags:
+  - auth
+`
+		if string(data) != want {
+			t.Errorf("saved file =\n%s\nwant\n%s", data, want)
+		}
+	})
+}
+
+func TestSaveAdoptsExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	const existing = "# keep me\nid: REQ-001\ntitle: Old\nx-owner: alice\n"
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A spec built in memory, as an import with --force does
+	if err := Save(path, &Spec{ID: "REQ-001", Title: "New"}); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "# keep me\nid: REQ-001\ntitle: New\nx-owner: alice\n" {
+		t.Errorf("saved file = %q", data)
+	}
+}
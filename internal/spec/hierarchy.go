@@ -0,0 +1,117 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+// ValidateHierarchy checks parent references across all specs for missing
+// parents and cycles (e.g. an epic whose parent is one of its own features).
+func ValidateHierarchy(specs []*Spec) error {
+	byID := make(map[string]*Spec, len(specs))
+	for _, s := range specs {
+		byID[s.ID] = s
+	}
+
+	for _, s := range specs {
+		if s.Parent != "" && byID[s.Parent] == nil {
+			return apperrors.New("spec.ValidateHierarchy", apperrors.ErrInvalidInput,
+				fmt.Sprintf("%s has parent %s which does not exist", s.ID, s.Parent))
+		}
+	}
+
+	// Each spec has at most one parent, so following the chain is enough.
+	// done marks specs whose chain is known to reach a root.
+	done := make(map[string]bool, len(specs))
+	for _, s := range specs {
+		var path []string
+		onPath := make(map[string]bool)
+		for cur := s; cur != nil && !done[cur.ID]; cur = byID[cur.Parent] {
+			if onPath[cur.ID] {
+				path = append(path, cur.ID)
+				start := 0
+				for path[start] != cur.ID {
+					start++
+				}
+				return apperrors.New("spec.ValidateHierarchy", apperrors.ErrInvalidInput,
+					fmt.Sprintf("parent cycle detected: %s", strings.Join(path[start:], " -> ")))
+			}
+			onPath[cur.ID] = true
+			path = append(path, cur.ID)
+		}
+		for _, id := range path {
+			done[id] = true
+		}
+	}
+	return nil
+}
+
+// Hierarchy is the parent/child view of a set of specs. A spec whose parent
+// is not in the set (e.g. filtered out by status) is treated as a root.
+type Hierarchy struct {
+	byID     map[string]*Spec
+	children map[string][]*Spec
+	roots    []*Spec
+}
+
+// NewHierarchy builds the hierarchy of specs, keeping their order for
+// siblings. specs must be free of parent cycles (see ValidateHierarchy).
+func NewHierarchy(specs []*Spec) *Hierarchy {
+	h := &Hierarchy{
+		byID:     make(map[string]*Spec, len(specs)),
+		children: make(map[string][]*Spec),
+	}
+	for _, s := range specs {
+		h.byID[s.ID] = s
+	}
+	for _, s := range specs {
+		if s.Parent != "" && h.byID[s.Parent] != nil {
+			h.children[s.Parent] = append(h.children[s.Parent], s)
+			continue
+		}
+		h.roots = append(h.roots, s)
+	}
+	return h
+}
+
+// Roots returns specs without a parent in the set.
+func (h *Hierarchy) Roots() []*Spec {
+	return h.roots
+}
+
+// Children returns the direct children of id.
+func (h *Hierarchy) Children(id string) []*Spec {
+	return h.children[id]
+}
+
+// HasChildren reports whether id has at least one child in the set.
+func (h *Hierarchy) HasChildren(id string) bool {
+	return len(h.children[id]) > 0
+}
+
+// Descendants returns all specs below id, depth first.
+func (h *Hierarchy) Descendants(id string) []*Spec {
+	var out []*Spec
+	for _, c := range h.children[id] {
+		out = append(out, c)
+		out = append(out, h.Descendants(c.ID)...)
+	}
+	return out
+}
+
+// Walk visits every spec depth first from the roots, passing its depth
+// (0 for roots).
+func (h *Hierarchy) Walk(fn func(s *Spec, depth int)) {
+	var visit func(s *Spec, depth int)
+	visit = func(s *Spec, depth int) {
+		fn(s, depth)
+		for _, c := range h.children[s.ID] {
+			visit(c, depth+1)
+		}
+	}
+	for _, r := range h.roots {
+		visit(r, 0)
+	}
+}
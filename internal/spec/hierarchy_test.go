@@ -0,0 +1,129 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_Parent(t *testing.T) {
+	tests := []struct {
+		name    string
+		parent  string
+		wantErr string
+	}{
+		{name: "empty", parent: ""},
+		{name: "valid", parent: "REQ-002"},
+		{name: "bad format", parent: "EPIC-1", wantErr: "must match REQ-###"},
+		{name: "self", parent: "REQ-001", wantErr: "self-reference"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Spec{ID: "REQ-001", Title: "Login", Parent: tt.parent}
+			err := s.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHierarchy(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []*Spec
+		wantErr string
+	}{
+		{
+			name: "valid tree",
+			specs: []*Spec{
+				{ID: "REQ-001"},
+				{ID: "REQ-002", Parent: "REQ-001"},
+				{ID: "REQ-003", Parent: "REQ-002"},
+			},
+		},
+		{
+			name:    "missing parent",
+			specs:   []*Spec{{ID: "REQ-001", Parent: "REQ-009"}},
+			wantErr: "REQ-001 has parent REQ-009 which does not exist",
+		},
+		{
+			name: "cycle",
+			specs: []*Spec{
+				{ID: "REQ-001", Parent: "REQ-003"},
+				{ID: "REQ-002", Parent: "REQ-001"},
+				{ID: "REQ-003", Parent: "REQ-002"},
+				{ID: "REQ-004", Parent: "REQ-002"},
+			},
+			wantErr: "parent cycle detected: REQ-001 -> REQ-003 -> REQ-002 -> REQ-001",
+		},
+		{
+			name: "cycle below a valid chain",
+			specs: []*Spec{
+				{ID: "REQ-001", Parent: "REQ-002"},
+				{ID: "REQ-002", Parent: "REQ-003"},
+				{ID: "REQ-003", Parent: "REQ-002"},
+			},
+			wantErr: "parent cycle detected: REQ-002 -> REQ-003 -> REQ-002",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHierarchy(tt.specs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	specs := []*Spec{
+		{ID: "REQ-001"},
+		{ID: "REQ-002", Parent: "REQ-001"},
+		{ID: "REQ-003", Parent: "REQ-002"},
+		{ID: "REQ-004", Parent: "REQ-001"},
+		{ID: "REQ-005", Parent: "REQ-099"}, // parent filtered out
+	}
+	h := NewHierarchy(specs)
+
+	ids := func(specs []*Spec) string {
+		out := make([]string, 0, len(specs))
+		for _, s := range specs {
+			out = append(out, s.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(h.Roots()); got != "REQ-001,REQ-005" {
+		t.Errorf("Roots = %s", got)
+	}
+	if got := ids(h.Children("REQ-001")); got != "REQ-002,REQ-004" {
+		t.Errorf("Children = %s", got)
+	}
+	if got := ids(h.Descendants("REQ-001")); got != "REQ-002,REQ-003,REQ-004" {
+		t.Errorf("Descendants = %s", got)
+	}
+	if h.HasChildren("REQ-003") {
+		t.Error("REQ-003 should be a leaf")
+	}
+
+	var walked []string
+	h.Walk(func(s *Spec, depth int) {
+		walked = append(walked, strings.Repeat(">", depth)+s.ID)
+	})
+	if got := strings.Join(walked, " "); got != "REQ-001 >REQ-002 >>REQ-003 >REQ-004 REQ-005" {
+		t.Errorf("Walk = %s", got)
+	}
+}
@@ -0,0 +1,216 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Numbering modes of an IDScheme.
+const (
+	// NumberingPerPrefix counts each prefix separately (AUTH-001, BILL-001).
+	NumberingPerPrefix = "per-prefix"
+	// NumberingGlobal shares one counter across prefixes (AUTH-001, BILL-002).
+	NumberingGlobal = "global"
+)
+
+// IDScheme describes the requirement IDs of a project: one or more prefixes
+// followed by a hyphen and a zero-padded number, e.g. REQ-001 or AUTH-012.
+type IDScheme struct {
+	Prefixes  []string
+	Padding   int
+	Numbering string
+
+	pattern     *regexp.Regexp
+	provisional *regexp.Regexp
+}
+
+// DefaultIDScheme is the REQ-### scheme used when nothing is configured.
+func DefaultIDScheme() IDScheme {
+	return NewIDScheme([]string{"REQ"}, 3, NumberingPerPrefix)
+}
+
+// NewIDScheme builds a scheme. Empty prefixes fall back to REQ, a
+// non-positive padding to 3 and an empty numbering to per-prefix.
+func NewIDScheme(prefixes []string, padding int, numbering string) IDScheme {
+	if len(prefixes) == 0 {
+		prefixes = []string{"REQ"}
+	}
+	if padding <= 0 {
+		padding = 3
+	}
+	if numbering == "" {
+		numbering = NumberingPerPrefix
+	}
+	quoted := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		quoted[i] = regexp.QuoteMeta(p)
+	}
+	alt := strings.Join(quoted, "|")
+	return IDScheme{
+		Prefixes:    append([]string(nil), prefixes...),
+		Padding:     padding,
+		Numbering:   numbering,
+		pattern:     regexp.MustCompile(`^(` + alt + `)-(\d+)$`),
+		provisional: regexp.MustCompile(`^(` + alt + `)-` + provisionalMarker + `-(` + provisionalToken + `)$`),
+	}
+}
+
+var (
+	idSchemeMu sync.RWMutex
+	idScheme   = DefaultIDScheme()
+)
+
+// SetIDScheme changes the scheme used to validate, generate and sort IDs.
+// It is set from .tdd/config.yml when a command loads its configuration.
+func SetIDScheme(s IDScheme) {
+	idSchemeMu.Lock()
+	defer idSchemeMu.Unlock()
+	idScheme = s
+}
+
+// CurrentIDScheme returns the scheme in use.
+func CurrentIDScheme() IDScheme {
+	idSchemeMu.RLock()
+	defer idSchemeMu.RUnlock()
+	return idScheme
+}
+
+// DefaultPrefix is the prefix used when none is given (the first configured).
+func (s IDScheme) DefaultPrefix() string {
+	return s.Prefixes[0]
+}
+
+// HasPrefix reports whether prefix is one of the configured prefixes.
+func (s IDScheme) HasPrefix(prefix string) bool {
+	for _, p := range s.Prefixes {
+		if p == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse splits a permanent ID into its prefix and number. Provisional IDs
+// have no number and are not parsed.
+func (s IDScheme) Parse(id string) (prefix string, n int, ok bool) {
+	m := s.pattern.FindStringSubmatch(id)
+	if m == nil {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return m[1], n, true
+}
+
+// Valid reports whether id matches the scheme, either as a permanent
+// numbered ID or as a provisional one (see IsProvisional).
+func (s IDScheme) Valid(id string) bool {
+	return s.pattern.MatchString(id) || s.provisional.MatchString(id)
+}
+
+// Format renders prefix and number as an ID, e.g. AUTH-012.
+func (s IDScheme) Format(prefix string, n int) string {
+	return fmt.Sprintf("%s-%0*d", prefix, s.Padding, n)
+}
+
+// Describe returns a human-readable form of the scheme, e.g. "REQ-###" or
+// "AUTH-### or BILL-###", for error messages.
+func (s IDScheme) Describe() string {
+	hashes := strings.Repeat("#", s.Padding)
+	forms := make([]string, len(s.Prefixes))
+	for i, p := range s.Prefixes {
+		forms[i] = p + "-" + hashes
+	}
+	return strings.Join(forms, " or ")
+}
+
+// Example returns the first ID of the default prefix, e.g. REQ-001, for
+// examples in help texts and prompts.
+func (s IDScheme) Example() string {
+	return s.Format(s.DefaultPrefix(), 1)
+}
+
+// Pattern returns an unanchored regular expression matching IDs of the
+// scheme, for finding references in free text and test names.
+func (s IDScheme) Pattern() string {
+	quoted := make([]string, len(s.Prefixes))
+	for i, p := range s.Prefixes {
+		quoted[i] = regexp.QuoteMeta(p)
+	}
+	return `(?:` + strings.Join(quoted, "|") + `)-(?:\d+|` + provisionalMarker + `-(?:` + provisionalToken + `))`
+}
+
+// Next returns the ID following the highest one in ids for prefix. With
+// global numbering the highest number across all prefixes is used.
+// IDs that do not match the scheme are ignored.
+func (s IDScheme) Next(prefix string, ids []string) string {
+	max := 0
+	for _, id := range ids {
+		p, n, ok := s.Parse(id)
+		if !ok || (s.Numbering != NumberingGlobal && p != prefix) {
+			continue
+		}
+		if n > max {
+			max = n
+		}
+	}
+	return s.Format(prefix, max+1)
+}
+
+// Less orders permanent IDs by prefix in configured order, then
+// numerically. Provisional IDs follow in creation order, and IDs outside the
+// scheme come last, alphabetically.
+func (s IDScheme) Less(a, b string) bool {
+	if ra, rb := s.rank(a), s.rank(b); ra != rb {
+		return ra < rb
+	}
+	ap, an, aok := s.Parse(a)
+	bp, bn, bok := s.Parse(b)
+	if aok && bok {
+		if ap != bp {
+			return s.prefixIndex(ap) < s.prefixIndex(bp)
+		}
+		if an != bn {
+			return an < bn
+		}
+		return a < b
+	}
+	if s.IsProvisional(a) {
+		return provisionalLess(s.provisional.FindStringSubmatch(a)[2], s.provisional.FindStringSubmatch(b)[2])
+	}
+	return a < b
+}
+
+// rank groups IDs for sorting: permanent, provisional, then anything else.
+func (s IDScheme) rank(id string) int {
+	switch {
+	case s.pattern.MatchString(id):
+		return 0
+	case s.provisional.MatchString(id):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Sort sorts IDs in scheme order.
+func (s IDScheme) Sort(ids []string) {
+	sort.SliceStable(ids, func(i, j int) bool {
+		return s.Less(ids[i], ids[j])
+	})
+}
+
+func (s IDScheme) prefixIndex(prefix string) int {
+	for i, p := range s.Prefixes {
+		if p == prefix {
+			return i
+		}
+	}
+	return len(s.Prefixes)
+}
@@ -0,0 +1,101 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+func useIDScheme(t *testing.T, s IDScheme) {
+	t.Helper()
+	SetIDScheme(s)
+	t.Cleanup(func() { SetIDScheme(DefaultIDScheme()) })
+}
+
+func TestIDScheme(t *testing.T) {
+	s := NewIDScheme([]string{"AUTH", "BILL"}, 4, "")
+
+	t.Run("parse and valid", func(t *testing.T) {
+		prefix, n, ok := s.Parse("BILL-0012")
+		if !ok || prefix != "BILL" || n != 12 {
+			t.Errorf("Parse = %q %d %v", prefix, n, ok)
+		}
+		for _, id := range []string{"REQ-001", "AUTH", "AUTH-", "AUTH-1a", "xAUTH-001"} {
+			if s.Valid(id) {
+				t.Errorf("%q should be invalid", id)
+			}
+		}
+		if !s.Valid("AUTH-1") {
+			t.Error("shorter numbers than the padding are still valid")
+		}
+	})
+
+	t.Run("format and describe", func(t *testing.T) {
+		if got := s.Format("AUTH", 7); got != "AUTH-0007" {
+			t.Errorf("Format = %q", got)
+		}
+		if got := s.Describe(); got != "AUTH-#### or BILL-####" {
+			t.Errorf("Describe = %q", got)
+		}
+		if got := DefaultIDScheme().Describe(); got != "REQ-###" {
+			t.Errorf("default Describe = %q", got)
+		}
+	})
+
+	t.Run("next per prefix and global", func(t *testing.T) {
+		ids := []string{"AUTH-0003", "BILL-0010", "other"}
+		if got := s.Next("AUTH", ids); got != "AUTH-0004" {
+			t.Errorf("per-prefix Next = %q", got)
+		}
+		if got := s.Next("BILL", nil); got != "BILL-0001" {
+			t.Errorf("empty Next = %q", got)
+		}
+		global := NewIDScheme([]string{"AUTH", "BILL"}, 3, NumberingGlobal)
+		if got := global.Next("AUTH", []string{"AUTH-003", "BILL-010"}); got != "AUTH-011" {
+			t.Errorf("global Next = %q", got)
+		}
+	})
+
+	t.Run("sort by prefix order then number", func(t *testing.T) {
+		ids := []string{"BILL-0002", "zzz", "AUTH-0010", "BILL-0001", "AUTH-0009"}
+		s.Sort(ids)
+		if got := strings.Join(ids, ","); got != "AUTH-0009,AUTH-0010,BILL-0001,BILL-0002,zzz" {
+			t.Errorf("Sort = %s", got)
+		}
+	})
+}
+
+func TestValidate_CustomIDScheme(t *testing.T) {
+	useIDScheme(t, NewIDScheme([]string{"AUTH", "BILL"}, 3, ""))
+
+	ok := &Spec{ID: "AUTH-012", Title: "Login", Parent: "AUTH-001", Depends: []string{"BILL-003"}}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	bad := &Spec{ID: "REQ-001", Title: "Login"}
+	if err := bad.Validate(); err == nil || !strings.Contains(err.Error(), "id must match AUTH-### or BILL-###") {
+		t.Errorf("expected scheme error, got %v", err)
+	}
+}
+
+func TestNextReqIDFor(t *testing.T) {
+	useIDScheme(t, NewIDScheme([]string{"AUTH", "BILL"}, 3, ""))
+	dir := t.TempDir()
+	for _, s := range []*Spec{{ID: "AUTH-002", Title: "a"}, {ID: "BILL-005", Title: "b"}} {
+		if err := Save(dir+"/"+s.ID+".yml", s); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+
+	if got, err := NextReqID(dir); err != nil || got != "AUTH-003" {
+		t.Errorf("NextReqID = %q, %v", got, err)
+	}
+	if got, err := NextReqIDFor(dir, "BILL"); err != nil || got != "BILL-006" {
+		t.Errorf("NextReqIDFor(BILL) = %q, %v", got, err)
+	}
+
+	specs, err := LoadAll(dir)
+	if err != nil || len(specs) != 2 || specs[0].ID != "AUTH-002" {
+		t.Errorf("LoadAll = %v, %v", specs, err)
+	}
+}
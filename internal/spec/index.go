@@ -0,0 +1,198 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+)
+
+// indexFormat changes whenever the cached data changes shape.
+const indexFormat = 1
+
+// racyWindow is how recent a file's mtime may be for its entry to be
+// trusted. A file written again within the same mtime tick keeps its mtime,
+// so entries for files modified shortly before indexing are re-parsed.
+const racyWindow = 2 * time.Second
+
+var (
+	indexMu   sync.Mutex
+	indexPath string
+)
+
+// SetIndexPath enables the on-disk spec index at path (e.g. .tdd/index.json)
+// for LoadAll and ListIDs. An empty path disables it.
+func SetIndexPath(path string) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	indexPath = path
+}
+
+// index caches parsed specs by file path. An entry is valid while the file
+// keeps its size and mtime and the ID scheme is unchanged.
+type index struct {
+	Format  int                   `json:"format"`
+	Key     string                `json:"key"`
+	Entries map[string]indexEntry `json:"entries"`
+}
+
+type indexEntry struct {
+	ModTime int64 `json:"mtime"`
+	Size    int64 `json:"size"`
+	Spec    *Spec `json:"spec"`
+}
+
+// indexKey identifies everything besides the file content that Load
+// depends on: the spec format and the ID scheme Validate checks against.
+func indexKey() string {
+	ids := CurrentIDScheme()
+	return fmt.Sprintf("v%d/%s/%d/%s", FormatVersion, strings.Join(ids.Prefixes, ","), ids.Padding, ids.Numbering)
+}
+
+func readIndex(path string) *index {
+	idx := &index{Format: indexFormat, Key: indexKey(), Entries: map[string]indexEntry{}}
+	if path == "" {
+		return idx
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return idx
+	}
+	var cached index
+	// A corrupt or outdated index is rebuilt from scratch
+	if json.Unmarshal(data, &cached) != nil || cached.Format != idx.Format || cached.Key != idx.Key || cached.Entries == nil {
+		return idx
+	}
+	return &cached
+}
+
+// write saves the index atomically. The index is only a cache, so callers
+// ignore failures (e.g. a read-only checkout).
+func (idx *index) write(path string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, data, 0644)
+}
+
+// loadFiles loads specs in the order of files, taking unchanged ones from
+// the index and parsing the rest in parallel. With prune, entries for files
+// not in files are dropped (the caller passes the whole tree).
+func loadFiles(files []string, prune bool) ([]*Spec, error) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	idx := readIndex(indexPath)
+	now := time.Now()
+	out := make([]*Spec, len(files))
+	infos := make([]os.FileInfo, len(files))
+	var stale []int
+	for i, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			stale = append(stale, i)
+			continue
+		}
+		infos[i] = info
+		e, ok := idx.Entries[path]
+		if ok && e.Spec != nil && e.Size == info.Size() && e.ModTime == info.ModTime().UnixNano() {
+			s := *e.Spec
+			out[i] = &s
+			continue
+		}
+		stale = append(stale, i)
+	}
+
+	errs := make([]error, len(files))
+	parseParallel(stale, func(i int) {
+		out[i], errs[i] = Load(files[i])
+	})
+	// Report the first failing file in file order, as a sequential load would
+	for _, i := range stale {
+		if errs[i] != nil {
+			return nil, apperrors.Wrapf("spec.LoadAll", errs[i], "%s", files[i])
+		}
+	}
+
+	if indexPath == "" {
+		return out, nil
+	}
+	changed := len(stale) > 0
+	for _, i := range stale {
+		info := infos[i]
+		if info == nil || now.Sub(info.ModTime()) < racyWindow {
+			delete(idx.Entries, files[i])
+			continue
+		}
+		cached := *out[i]
+		cached.doc = nil
+		idx.Entries[files[i]] = indexEntry{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Spec: &cached}
+	}
+	if prune {
+		keep := make(map[string]bool, len(files))
+		for _, path := range files {
+			keep[path] = true
+		}
+		for path := range idx.Entries {
+			if !keep[path] {
+				delete(idx.Entries, path)
+				changed = true
+			}
+		}
+	}
+	if changed {
+		_ = idx.write(indexPath)
+	}
+	return out, nil
+}
+
+// parseParallel calls fn for every item on up to GOMAXPROCS goroutines.
+func parseParallel(items []int, fn func(int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(items) {
+		workers = len(items)
+	}
+	if workers <= 1 {
+		for _, i := range items {
+			fn(i)
+		}
+		return
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for _, i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// ClearIndex removes the index file, if any.
+func ClearIndex() error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	if indexPath == "" {
+		return nil
+	}
+	if err := os.Remove(indexPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
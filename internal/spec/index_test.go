@@ -0,0 +1,174 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func useIndex(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "index.json")
+	SetIndexPath(path)
+	t.Cleanup(func() { SetIndexPath("") })
+	return path
+}
+
+// writeOld writes a spec file with an mtime old enough to be indexed.
+func writeOld(t *testing.T, path, data string, mtime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readIndexEntries(t *testing.T, path string) map[string]indexEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		t.Fatalf("invalid index: %v", err)
+	}
+	return idx.Entries
+}
+
+func titles(t *testing.T, dir string) string {
+	t.Helper()
+	specs, err := LoadAll(dir)
+	if err != nil {
+		t.Fatalf("LoadAll error: %v", err)
+	}
+	var out []string
+	for _, s := range specs {
+		out = append(out, s.ID+"="+s.Title)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestIndexCachesUnchangedFiles(t *testing.T) {
+	indexPath := useIndex(t)
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	a, b := filepath.Join(dir, "REQ-001.yml"), filepath.Join(dir, "auth", "REQ-002.yml")
+	writeOld(t, a, "title: Login\n", old)
+	writeOld(t, b, "title: Logout\n", old)
+
+	if got := titles(t, dir); got != "REQ-001=Login,REQ-002=Logout" {
+		t.Fatalf("first load = %s", got)
+	}
+	if n := len(readIndexEntries(t, indexPath)); n != 2 {
+		t.Fatalf("index has %d entries, want 2", n)
+	}
+
+	// Same size and mtime: the cached spec is used without parsing
+	writeOld(t, a, "title: LOGIN\n", old)
+	if got := titles(t, dir); got != "REQ-001=Login,REQ-002=Logout" {
+		t.Errorf("cached load = %s", got)
+	}
+
+	// A new mtime invalidates only that file
+	writeOld(t, a, "title: LOGIN\n", old.Add(time.Minute))
+	if got := titles(t, dir); got != "REQ-001=LOGIN,REQ-002=Logout" {
+		t.Errorf("load after edit = %s", got)
+	}
+
+	// Namespaces are derived from the path, not the cache
+	specs, _ := LoadAll(dir)
+	if specs[1].Namespace != "auth" {
+		t.Errorf("namespace = %q, want auth", specs[1].Namespace)
+	}
+
+	// Deleted files are pruned
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(t, dir); got != "REQ-001=LOGIN" {
+		t.Errorf("load after delete = %s", got)
+	}
+	if _, ok := readIndexEntries(t, indexPath)[b]; ok {
+		t.Error("deleted file still in the index")
+	}
+}
+
+func TestIndexInvalidation(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	path := filepath.Join(dir, "REQ-001.yml")
+
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, indexPath string)
+	}{
+		{"corrupt index", func(t *testing.T, indexPath string) {
+			if err := os.WriteFile(indexPath, []byte("{not json"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"ID scheme changed", func(t *testing.T, indexPath string) {
+			useIDScheme(t, NewIDScheme([]string{"REQ", "AUTH"}, 3, NumberingPerPrefix))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexPath := useIndex(t)
+			writeOld(t, path, "title: Login\n", old)
+			if got := titles(t, dir); got != "REQ-001=Login" {
+				t.Fatalf("first load = %s", got)
+			}
+			writeOld(t, path, "title: LOGIN\n", old)
+			tt.prepare(t, indexPath)
+			if got := titles(t, dir); got != "REQ-001=LOGIN" {
+				t.Errorf("load = %s, want the file re-parsed", got)
+			}
+		})
+	}
+}
+
+func TestIndexSkipsRecentlyModifiedFiles(t *testing.T) {
+	indexPath := useIndex(t)
+	dir := t.TempDir()
+	writeOld(t, filepath.Join(dir, "REQ-001.yml"), "title: Login\n", time.Now())
+	writeOld(t, filepath.Join(dir, "REQ-002.yml"), "title: Logout\n", time.Now().Add(-time.Hour))
+
+	if got := titles(t, dir); got != "REQ-001=Login,REQ-002=Logout" {
+		t.Fatalf("load = %s", got)
+	}
+	entries := readIndexEntries(t, indexPath)
+	if _, ok := entries[filepath.Join(dir, "REQ-001.yml")]; ok {
+		t.Error("a file modified within the mtime granularity should not be cached")
+	}
+	if _, ok := entries[filepath.Join(dir, "REQ-002.yml")]; !ok {
+		t.Error("an old file should be cached")
+	}
+}
+
+func TestLoadAllReportsFirstInvalidFile(t *testing.T) {
+	useIndex(t)
+	dir := t.TempDir()
+	for i := 1; i <= 40; i++ {
+		data := fmt.Sprintf("title: Spec %d\n", i)
+		if i == 7 || i == 30 {
+			data = "title: ''\n"
+		}
+		writeOld(t, filepath.Join(dir, fmt.Sprintf("REQ-%03d.yml", i)), data, time.Now().Add(-time.Hour))
+	}
+	for run := 0; run < 3; run++ {
+		_, err := LoadAll(dir)
+		if err == nil || !strings.Contains(err.Error(), "REQ-007") {
+			t.Fatalf("run %d: error = %v, want the error of REQ-007", run, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package spec
+
+import (
+	"github.com/thirdlf03/spec-tdd/internal/migrate"
+	"github.com/thirdlf03/spec-tdd/internal/yamlnode"
+	"go.yaml.in/yaml/v3"
+)
+
+// FormatVersion is the spec file format written by this version of
+// spec-tdd. Load refuses files with a newer version.
+const FormatVersion = 2
+
+// Migrations upgrade spec files to FormatVersion, oldest first.
+var Migrations = []migrate.Step{
+	{
+		To:          2,
+		Description: "structured questions and explicit example, rule and question IDs",
+		Apply:       migrateExplicitIDs,
+	},
+}
+
+// migrateExplicitIDs writes out what Normalize fills in on load: plain
+// string questions become {id, text, status}, and examples, rules and
+// questions without an ID get the one Normalize would give them.
+func migrateExplicitIDs(m *yaml.Node) (bool, error) {
+	var s Spec
+	if err := m.Decode(&s); err != nil {
+		return false, err
+	}
+	normalized := s
+	normalized.Examples = append([]Example(nil), s.Examples...)
+	normalized.Rules = make([]Rule, len(s.Rules))
+	for i, r := range s.Rules {
+		r.Examples = append([]Example(nil), r.Examples...)
+		normalized.Rules[i] = r
+	}
+	normalized.Questions = append([]Question(nil), s.Questions...)
+	normalized.Normalize()
+
+	changed := false
+	setIDs := func(seq *yaml.Node, ids func(i int) string) {
+		if seq == nil || seq.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range seq.Content {
+			if item.Kind == yaml.MappingNode && yamlnode.Get(item, "id") == nil {
+				yamlnode.SetFirst(item, "id", yamlnode.Scalar("!!str", ids(i)))
+				changed = true
+			}
+		}
+	}
+
+	setIDs(yamlnode.Get(m, "examples"), func(i int) string { return normalized.Examples[i].ID })
+	rules := yamlnode.Get(m, "rules")
+	setIDs(rules, func(i int) string { return normalized.Rules[i].ID })
+	if rules != nil && rules.Kind == yaml.SequenceNode {
+		for i, r := range rules.Content {
+			setIDs(yamlnode.Get(r, "examples"), func(j int) string { return normalized.Rules[i].Examples[j].ID })
+		}
+	}
+
+	questions := yamlnode.Get(m, "questions")
+	if questions != nil && questions.Kind == yaml.SequenceNode {
+		for i, q := range questions.Content {
+			if q.Kind != yaml.ScalarNode {
+				continue
+			}
+			text := *q
+			text.HeadComment, text.LineComment, text.FootComment = "", "", ""
+			questions.Content[i] = &yaml.Node{
+				Kind:        yaml.MappingNode,
+				Tag:         "!!map",
+				HeadComment: q.HeadComment,
+				LineComment: q.LineComment,
+				FootComment: q.FootComment,
+				Content: []*yaml.Node{
+					yamlnode.Scalar("!!str", "id"), yamlnode.Scalar("!!str", normalized.Questions[i].ID),
+					yamlnode.Scalar("!!str", "text"), &text,
+					yamlnode.Scalar("!!str", "status"), yamlnode.Scalar("!!str", QuestionOpen),
+				},
+			}
+			changed = true
+		}
+		setIDs(questions, func(i int) string { return normalized.Questions[i].ID })
+	}
+	return changed, nil
+}
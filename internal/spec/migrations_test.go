@@ -0,0 +1,140 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/migrate"
+)
+
+func TestMigrateToVersion2(t *testing.T) {
+	input := `# Login requirement
+id: REQ-001
+title: Login
+examples:
+  - id: E2
+    given: a
+    when: b
+    then: c
+  - given: d # no ID yet
+    when: e
+    then: f
+rules:
+  - text: Lockout
+    examples:
+      - given: g
+        when: h
+        then: i
+questions:
+  - id: Q1
+    text: Known?
+  # Asked in the kickoff
+  - Legacy question?
+`
+	want := `# Login requirement
+version: 2
+id: REQ-001
+title: Login
+examples:
+  - id: E2
+    given: a
+    when: b
+    then: c
+  - id: E3
+    given: d # no ID yet
+    when: e
+    then: f
+rules:
+  - id: R1
+    text: Lockout
+    examples:
+      - id: E4
+        given: g
+        when: h
+        then: i
+questions:
+  - id: Q1
+    text: Known?
+  # Asked in the kickoff
+  - id: Q2
+    text: Legacy question?
+    status: open
+`
+	res, err := migrate.Run([]byte(input), Migrations, FormatVersion)
+	if err != nil {
+		t.Fatalf("migrate error: %v", err)
+	}
+	if string(res.After) != want {
+		t.Errorf("migrated spec:\n%s\nwant:\n%s", res.After, want)
+	}
+
+	again, err := migrate.Run(res.After, Migrations, FormatVersion)
+	if err != nil {
+		t.Fatalf("second migrate error: %v", err)
+	}
+	if again.Changed() {
+		t.Errorf("second migrate changed the spec:\n%s", again.After)
+	}
+
+	// The migrated file loads to the same spec as the original
+	dir := t.TempDir()
+	load := func(name, data string) *Spec {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		s.Normalize()
+		s.Version, s.doc = 0, nil
+		return s
+	}
+	before, after := load("before.yml", input), load("after.yml", want)
+	b, _ := Marshal(before)
+	a, _ := Marshal(after)
+	if string(a) != string(b) {
+		t.Errorf("migration changed the spec:\n%s\nvs\n%s", a, b)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	if err := os.WriteFile(path, []byte("version: 3\ntitle: Login\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "upgrade spec-tdd") {
+		t.Fatalf("expected newer version error, got %v", err)
+	}
+	_, err = LoadAll(filepath.Dir(path))
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("expected LoadAll error naming the file, got %v", err)
+	}
+}
+
+func TestMarshalVersion(t *testing.T) {
+	data, err := Marshal(&Spec{ID: "REQ-001", Title: "Login"})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "version: 2\n") {
+		t.Errorf("new spec should be stamped with the current version:\n%s", data)
+	}
+
+	// Overwriting a file keeps its version, so an unmigrated file stays at 1
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	if err := os.WriteFile(path, []byte("id: REQ-001\ntitle: Old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(path, &Spec{ID: "REQ-001", Title: "Login"}); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != "id: REQ-001\ntitle: Login\n" {
+		t.Errorf("saved spec = %q", got)
+	}
+}
@@ -0,0 +1,103 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+// NamespaceOf returns the namespace of a spec file: its directory relative to
+// specDir with forward slashes, or "" for files at the top level.
+func NamespaceOf(specDir, path string) string {
+	rel, err := filepath.Rel(specDir, filepath.Dir(path))
+	if err != nil || rel == "." {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// ValidateNamespace checks that ns names a directory inside the spec
+// directory.
+func ValidateNamespace(ns string) error {
+	clean := CleanNamespace(ns)
+	if clean == "" || clean == ".." || strings.HasPrefix(clean, "../") || filepath.IsAbs(ns) || strings.HasPrefix(ns, "/") {
+		return apperrors.New("spec.ValidateNamespace", apperrors.ErrInvalidInput,
+			fmt.Sprintf("invalid namespace %q (must be a directory inside the spec directory, e.g. auth or billing/invoices)", ns))
+	}
+	for _, part := range strings.Split(clean, "/") {
+		if strings.HasPrefix(part, ".") {
+			return apperrors.New("spec.ValidateNamespace", apperrors.ErrInvalidInput,
+				fmt.Sprintf("invalid namespace %q (hidden directories are not scanned)", ns))
+		}
+	}
+	return nil
+}
+
+// CleanNamespace normalizes a namespace as given on the command line
+// ("auth/", "./auth", "auth\\login") to the form LoadAll sets.
+func CleanNamespace(ns string) string {
+	clean := filepath.ToSlash(filepath.Clean(strings.ReplaceAll(strings.TrimSpace(ns), "\\", "/")))
+	if clean == "." {
+		return ""
+	}
+	return clean
+}
+
+// InNamespace reports whether namespace ns is filter or one of its
+// sub-namespaces. An empty filter matches every namespace.
+func InNamespace(ns, filter string) bool {
+	filter = CleanNamespace(filter)
+	return filter == "" || ns == filter || strings.HasPrefix(ns, filter+"/")
+}
+
+// FilterNamespace returns the specs in namespace filter or below it.
+func FilterNamespace(specs []*Spec, filter string) []*Spec {
+	if CleanNamespace(filter) == "" {
+		return specs
+	}
+	out := make([]*Spec, 0, len(specs))
+	for _, s := range specs {
+		if InNamespace(s.Namespace, filter) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// FindFile returns the path of the spec file for id anywhere in the spec
+// directory tree. It returns an ErrNotFound error when there is none.
+func FindFile(specDir, id string) (string, error) {
+	for _, ext := range []string{".yml", ".yaml"} {
+		path := filepath.Join(specDir, id+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	files, err := ListFiles(specDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	for _, path := range files {
+		if strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) == id {
+			return path, nil
+		}
+	}
+	return "", apperrors.New("spec.FindFile", apperrors.ErrNotFound, "spec not found: "+id)
+}
+
+// PathFor returns the path of the spec file for id: the existing file when
+// there is one, otherwise a new file in namespace ns.
+func PathFor(specDir, ns, id string) (string, error) {
+	path, err := FindFile(specDir, id)
+	if err == nil {
+		return path, nil
+	}
+	if !apperrors.IsNotFound(err) {
+		return "", err
+	}
+	return filepath.Join(specDir, filepath.FromSlash(CleanNamespace(ns)), id+".yml"), nil
+}
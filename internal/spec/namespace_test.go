@@ -0,0 +1,139 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+func writeSpecFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadAllRecursive(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFiles(t, dir, map[string]string{
+		"REQ-003.yml":                  "title: Home\n",
+		"auth/REQ-001.yml":             "title: Login\n",
+		"billing/invoices/REQ-002.yml": "title: Invoice\n",
+		".drafts/REQ-009.yml":          "title: Hidden\n",
+		"auth/notes.txt":               "not a spec\n",
+	})
+
+	specs, err := LoadAll(dir)
+	if err != nil {
+		t.Fatalf("LoadAll error: %v", err)
+	}
+	var got []string
+	for _, s := range specs {
+		got = append(got, s.ID+"@"+s.Namespace)
+	}
+	if want := "REQ-001@auth,REQ-002@billing/invoices,REQ-003@"; strings.Join(got, ",") != want {
+		t.Errorf("specs = %v, want %s", got, want)
+	}
+	if want := filepath.Join(dir, "billing", "invoices", "REQ-002.yml"); specs[1].Path != want {
+		t.Errorf("Path = %q, want %q", specs[1].Path, want)
+	}
+
+	next, err := NextReqID(dir)
+	if err != nil || next != "REQ-004" {
+		t.Errorf("NextReqID = %q, %v; want REQ-004 (numbered across namespaces)", next, err)
+	}
+
+	path, err := FindFile(dir, "REQ-002")
+	if err != nil || path != filepath.Join(dir, "billing", "invoices", "REQ-002.yml") {
+		t.Errorf("FindFile = %q, %v", path, err)
+	}
+	if _, err := FindFile(dir, "REQ-009"); !apperrors.IsNotFound(err) {
+		t.Errorf("specs in hidden directories should not be found, got %v", err)
+	}
+	if path, _ := PathFor(dir, "auth", "REQ-010"); path != filepath.Join(dir, "auth", "REQ-010.yml") {
+		t.Errorf("PathFor new spec = %q", path)
+	}
+	if path, _ := PathFor(dir, "", "REQ-001"); path != filepath.Join(dir, "auth", "REQ-001.yml") {
+		t.Errorf("PathFor existing spec = %q", path)
+	}
+
+	if got := FilterNamespace(specs, "billing"); len(got) != 1 || got[0].ID != "REQ-002" {
+		t.Errorf("FilterNamespace(billing) = %v", got)
+	}
+}
+
+func TestLoadAllDuplicateIDs(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFiles(t, dir, map[string]string{
+		"auth/REQ-001.yml":    "title: Login\n",
+		"billing/REQ-001.yml": "title: Invoice\n",
+	})
+	_, err := LoadAll(dir)
+	if err == nil || !strings.Contains(err.Error(), "duplicate ID REQ-001") {
+		t.Fatalf("expected duplicate ID error, got %v", err)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	specs, err := ParseAll(".tdd/specs", map[string][]byte{
+		".tdd/specs/REQ-002.yml":         []byte("title: Logout\n"),
+		".tdd/specs/auth/REQ-001.yml":    []byte("title: Login\n"),
+		".tdd/specs/.drafts/REQ-009.yml": []byte("title: Hidden\n"),
+		".tdd/specs/auth/notes.txt":      []byte("not a spec\n"),
+		".tdd/config.yml":                []byte("spec_dir: .tdd/specs\n"),
+		".tdd/specs-old/REQ-008.yml":     []byte("title: Outside\n"),
+	})
+	if err != nil {
+		t.Fatalf("ParseAll error: %v", err)
+	}
+	var got []string
+	for _, s := range specs {
+		got = append(got, s.ID+"@"+s.Namespace)
+	}
+	if want := "REQ-001@auth,REQ-002@"; strings.Join(got, ",") != want {
+		t.Errorf("specs = %v, want %s", got, want)
+	}
+
+	_, err = ParseAll("specs", map[string][]byte{"specs/REQ-001.yml": []byte("title: [\n")})
+	if err == nil || !strings.Contains(err.Error(), "specs/REQ-001.yml") {
+		t.Errorf("expected parse error naming the file, got %v", err)
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	tests := []struct {
+		ns, filter string
+		in         bool
+	}{
+		{"auth", "", true},
+		{"", "", true},
+		{"auth", "auth", true},
+		{"auth/login", "auth", true},
+		{"authz", "auth", false},
+		{"", "auth", false},
+		{"auth/login", "auth/login/", true},
+	}
+	for _, tt := range tests {
+		if got := InNamespace(tt.ns, tt.filter); got != tt.in {
+			t.Errorf("InNamespace(%q, %q) = %v, want %v", tt.ns, tt.filter, got, tt.in)
+		}
+	}
+
+	for ns, valid := range map[string]bool{
+		"auth": true, "billing/invoices": true, "./auth/": true,
+		"../x": false, "/abs": false, "auth/../..": false, ".hidden": false, ".": false,
+	} {
+		if err := ValidateNamespace(ns); (err == nil) != valid {
+			t.Errorf("ValidateNamespace(%q) = %v, want valid %v", ns, err, valid)
+		}
+	}
+}
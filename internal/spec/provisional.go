@@ -0,0 +1,137 @@
+package spec
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provisional ID modes (ids.provisional in .tdd/config.yml).
+const (
+	// ProvisionalOff creates permanent sequential IDs right away.
+	ProvisionalOff = "off"
+	// ProvisionalBranch creates IDs local to the git branch, e.g.
+	// REQ-tmp-login-form.1.
+	ProvisionalBranch = "branch"
+	// ProvisionalULID creates IDs from a ULID, e.g. REQ-tmp-01J9Z3K4ABCDEFGHJKMNPQRSTV.
+	ProvisionalULID = "ulid"
+)
+
+// provisionalMarker separates the prefix from the provisional token.
+const provisionalMarker = "tmp"
+
+// provisionalToken matches a ULID or a branch slug with a counter. Both are
+// self-delimiting, so a provisional ID can be found inside a file name such
+// as req-REQ-tmp-login.1-title.test.ts.
+const provisionalToken = `[0-9A-HJKMNP-TV-Z]{26}|[a-z0-9]+(?:-[a-z0-9]+)*\.\d+`
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var branchSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// IsProvisional reports whether id is a provisional ID awaiting
+// `req finalize`.
+func (s IDScheme) IsProvisional(id string) bool {
+	return s.provisional.MatchString(id)
+}
+
+// ProvisionalPrefix returns the prefix of a provisional ID.
+func (s IDScheme) ProvisionalPrefix(id string) (string, bool) {
+	m := s.provisional.FindStringSubmatch(id)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// NextBranchID returns the next provisional ID for branch, e.g.
+// REQ-tmp-feature-login.3 after REQ-tmp-feature-login.2.
+func (s IDScheme) NextBranchID(prefix, branch string, ids []string) string {
+	slug := branchSlugPattern.ReplaceAllString(strings.ToLower(branch), "-")
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		slug = "branch"
+	}
+	max := 0
+	for _, id := range ids {
+		m := s.provisional.FindStringSubmatch(id)
+		if m == nil || m[1] != prefix {
+			continue
+		}
+		name, n, ok := splitBranchToken(m[2])
+		if ok && name == slug && n > max {
+			max = n
+		}
+	}
+	return fmt.Sprintf("%s-%s-%s.%d", prefix, provisionalMarker, slug, max+1)
+}
+
+// NewULIDID returns a provisional ID built from a new ULID.
+func (s IDScheme) NewULIDID(prefix string, now time.Time) (string, error) {
+	ulid, err := NewULID(now, rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s-%s", prefix, provisionalMarker, ulid), nil
+}
+
+// NewULID returns a ULID: a 48-bit millisecond timestamp followed by 80
+// random bits, in Crockford base32. ULIDs sort by creation time.
+func NewULID(now time.Time, entropy io.Reader) (string, error) {
+	var b [16]byte
+	ms := uint64(now.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := io.ReadFull(entropy, b[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 bits as 26 base32 characters; the first character carries 3 bits
+	out := make([]byte, 26)
+	var acc uint64
+	bits := 0
+	pos := 25
+	for i := 15; i >= 0; i-- {
+		acc |= uint64(b[i]) << bits
+		bits += 8
+		for bits >= 5 && pos >= 0 {
+			out[pos] = crockford[acc&31]
+			acc >>= 5
+			bits -= 5
+			pos--
+		}
+	}
+	if pos >= 0 {
+		out[pos] = crockford[acc&31]
+	}
+	return string(out), nil
+}
+
+// provisionalLess orders ULID tokens by time and branch tokens by branch,
+// then counter.
+func provisionalLess(a, b string) bool {
+	an, ai, aok := splitBranchToken(a)
+	bn, bi, bok := splitBranchToken(b)
+	if aok && bok && an == bn {
+		return ai < bi
+	}
+	return a < b
+}
+
+func splitBranchToken(token string) (string, int, bool) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(token[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return token[:i], n, true
+}
@@ -0,0 +1,60 @@
+package spec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewULID(t *testing.T) {
+	// Timestamp part of the example ULID in the specification
+	id, err := NewULID(time.UnixMilli(1469922850259), bytes.NewReader(make([]byte, 10)))
+	if err != nil {
+		t.Fatalf("NewULID error: %v", err)
+	}
+	if id != "01ARZ3NDEK0000000000000000" {
+		t.Errorf("NewULID = %q", id)
+	}
+
+	later, _ := NewULID(time.UnixMilli(1469922850260), bytes.NewReader(make([]byte, 10)))
+	if later <= id {
+		t.Errorf("ULIDs should sort by time: %q <= %q", later, id)
+	}
+}
+
+func TestProvisionalIDs(t *testing.T) {
+	s := NewIDScheme([]string{"AUTH", "BILL"}, 3, "")
+
+	ulidID, err := s.NewULIDID("AUTH", time.Now())
+	if err != nil {
+		t.Fatalf("NewULIDID error: %v", err)
+	}
+	for _, id := range []string{ulidID, "BILL-tmp-feature-login.2"} {
+		if !s.Valid(id) || !s.IsProvisional(id) {
+			t.Errorf("%q should be a valid provisional ID", id)
+		}
+	}
+	for _, id := range []string{"AUTH-012", "AUTH-tmp-login", "AUTH-tmp-Login.1", "REQ-tmp-login.1"} {
+		if s.IsProvisional(id) {
+			t.Errorf("%q should not be provisional", id)
+		}
+	}
+	if prefix, ok := s.ProvisionalPrefix("BILL-tmp-x.1"); !ok || prefix != "BILL" {
+		t.Errorf("ProvisionalPrefix = %q, %v", prefix, ok)
+	}
+
+	existing := []string{"AUTH-001", "AUTH-tmp-feature-login.1", "AUTH-tmp-feature-login.9", "BILL-tmp-feature-login.20"}
+	if got := s.NextBranchID("AUTH", "feature/Login", existing); got != "AUTH-tmp-feature-login.10" {
+		t.Errorf("NextBranchID = %q", got)
+	}
+	if got := s.Next("AUTH", existing); got != "AUTH-002" {
+		t.Errorf("provisional IDs must not affect Next, got %q", got)
+	}
+
+	ids := []string{"AUTH-tmp-a.10", "zzz", "AUTH-tmp-a.2", "BILL-001", "AUTH-003"}
+	s.Sort(ids)
+	if got := strings.Join(ids, ","); got != "AUTH-003,BILL-001,AUTH-tmp-a.2,AUTH-tmp-a.10,zzz" {
+		t.Errorf("Sort = %s", got)
+	}
+}
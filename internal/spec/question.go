@@ -0,0 +1,148 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"go.yaml.in/yaml/v3"
+)
+
+// Question statuses. An empty status is treated as open.
+const (
+	QuestionOpen     = "open"
+	QuestionResolved = "resolved"
+)
+
+var questionIDPattern = regexp.MustCompile(`^Q(\d+)$`)
+
+// Question is an open point raised during example mapping (a "red card").
+type Question struct {
+	ID         string    `yaml:"id,omitempty"`
+	Text       string    `yaml:"text"`
+	Owner      string    `yaml:"owner,omitempty"`
+	Status     string    `yaml:"status,omitempty"`
+	Answer     string    `yaml:"answer,omitempty"`
+	ResolvedAt time.Time `yaml:"resolved_at,omitempty"`
+}
+
+// UnmarshalYAML accepts both the structured form and a plain string,
+// which older spec files use for questions.
+func (q *Question) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*q = Question{Text: value.Value}
+		return nil
+	}
+	type plain Question
+	var p plain
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+	*q = Question(p)
+	return nil
+}
+
+// MarshalYAML writes a question that has nothing but text as a plain
+// string, the form older spec files use, so that saving a spec for an
+// unrelated change does not rewrite it.
+func (q Question) MarshalYAML() (any, error) {
+	if q.textOnly() {
+		return q.Text, nil
+	}
+	type plain Question
+	return plain(q), nil
+}
+
+// textOnly reports whether q is an open question without ID or any other
+// field, as loaded from a plain string.
+func (q Question) textOnly() bool {
+	return q == Question{Text: q.Text}
+}
+
+// completeQuestions fills in the ID and status of structured questions that
+// lack them (as Normalize does), leaving text-only ones as they are, so
+// that no question is written half-converted.
+func completeQuestions(s *Spec) {
+	for i := range s.Questions {
+		q := &s.Questions[i]
+		if q.textOnly() {
+			continue
+		}
+		if strings.TrimSpace(q.ID) == "" {
+			q.ID = NextQuestionID(s)
+		}
+		if q.Status == "" {
+			q.Status = QuestionOpen
+		}
+	}
+}
+
+// IsOpen reports whether the question still needs an answer.
+func (q Question) IsOpen() bool {
+	return q.Status != QuestionResolved
+}
+
+// QuestionsFromText wraps plain question texts as open questions.
+func QuestionsFromText(texts []string) []Question {
+	if len(texts) == 0 {
+		return nil
+	}
+	out := make([]Question, 0, len(texts))
+	for _, t := range texts {
+		out = append(out, Question{Text: t})
+	}
+	return out
+}
+
+// OpenQuestions returns the questions of s that are not resolved.
+func (s *Spec) OpenQuestions() []Question {
+	var out []Question
+	for _, q := range s.Questions {
+		if q.IsOpen() {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+// FindQuestion returns the question with the given ID, or nil.
+func (s *Spec) FindQuestion(id string) *Question {
+	for i := range s.Questions {
+		if s.Questions[i].ID == id {
+			return &s.Questions[i]
+		}
+	}
+	return nil
+}
+
+// NextQuestionID returns the next question ID for the spec.
+func NextQuestionID(s *Spec) string {
+	max := 0
+	for _, q := range s.Questions {
+		if matches := questionIDPattern.FindStringSubmatch(strings.TrimSpace(q.ID)); len(matches) == 2 {
+			val, _ := strconv.Atoi(matches[1])
+			if val > max {
+				max = val
+			}
+		}
+	}
+	return fmt.Sprintf("Q%d", max+1)
+}
+
+func validateQuestions(questions []Question) error {
+	for i, q := range questions {
+		if strings.TrimSpace(q.Text) == "" {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, fmt.Sprintf("question %d must include text", i+1))
+		}
+		switch q.Status {
+		case "", QuestionOpen, QuestionResolved:
+		default:
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+				fmt.Sprintf("question %d has unknown status %q (allowed: %s, %s)", i+1, q.Status, QuestionOpen, QuestionResolved))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadLegacyQuestions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	data := `id: REQ-001
+title: Login
+questions:
+  - セッションの有効期限は？
+  - id: Q2
+    text: Lockout after how many attempts?
+    owner: po
+    status: resolved
+    answer: "5"
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(s.Questions) != 2 {
+		t.Fatalf("expected 2 questions, got %d", len(s.Questions))
+	}
+	if s.Questions[0].Text != "セッションの有効期限は？" || !s.Questions[0].IsOpen() {
+		t.Errorf("legacy question = %+v", s.Questions[0])
+	}
+	if s.Questions[1].Owner != "po" || s.Questions[1].IsOpen() {
+		t.Errorf("structured question = %+v", s.Questions[1])
+	}
+
+	s.Normalize()
+	if s.Questions[0].ID != "Q3" || s.Questions[0].Status != QuestionOpen {
+		t.Errorf("Normalize should assign Q3/open, got %+v", s.Questions[0])
+	}
+	if open := s.OpenQuestions(); len(open) != 1 || open[0].ID != "Q3" {
+		t.Errorf("OpenQuestions = %+v", open)
+	}
+}
+
+func TestSaveKeepsLegacyQuestions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	data := `id: REQ-001
+title: Login
+questions:
+  - Who owns this? # ask in standup
+  - Session timeout?
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	// An unrelated edit keeps text-only questions as plain strings
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	s.Examples = append(s.Examples, Example{ID: "E1", Given: "g", When: "w", Then: "t"})
+	if err := Save(path, s); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if !strings.Contains(string(got), "  - Who owns this? # ask in standup\n  - Session timeout?\n") {
+		t.Errorf("legacy questions should be kept:\n%s", got)
+	}
+
+	// A question that gains a field is written with an ID and status
+	s, _ = Load(path)
+	s.Questions[1].Owner = "po"
+	if err := Save(path, s); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if q := loaded.Questions[1]; q.ID != "Q1" || q.Status != QuestionOpen || q.Owner != "po" {
+		t.Errorf("changed question = %+v, want Q1/open", q)
+	}
+	if q := loaded.Questions[0]; q.ID != "" || q.Text != "Who owns this?" {
+		t.Errorf("untouched question = %+v", q)
+	}
+}
+
+func TestQuestionSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	resolved := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	orig := &Spec{
+		ID:    "REQ-001",
+		Title: "Login",
+		Questions: []Question{
+			{ID: "Q1", Text: "open one", Status: QuestionOpen},
+			{ID: "Q2", Text: "done", Status: QuestionResolved, Answer: "yes", ResolvedAt: resolved},
+		},
+	}
+	if err := Save(path, orig); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Count(string(data), "resolved_at") != 1 {
+		t.Errorf("resolved_at should only be written for resolved questions:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if !loaded.Questions[1].ResolvedAt.Equal(resolved) || loaded.Questions[1].Answer != "yes" {
+		t.Errorf("loaded question = %+v", loaded.Questions[1])
+	}
+}
+
+func TestValidateQuestions(t *testing.T) {
+	tests := []struct {
+		name string
+		q    Question
+	}{
+		{"empty text", Question{ID: "Q1"}},
+		{"unknown status", Question{ID: "Q1", Text: "x", Status: "maybe"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Spec{ID: "REQ-001", Title: "A", Questions: []Question{tt.q}}
+			if err := s.Validate(); err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+}
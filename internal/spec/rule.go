@@ -0,0 +1,142 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+)
+
+var ruleIDPattern = regexp.MustCompile(`^R(\d+)$`)
+
+// Rule is a business rule of a requirement together with the examples that
+// illustrate it (the blue card of example mapping).
+type Rule struct {
+	ID       string    `yaml:"id,omitempty"`
+	Text     string    `yaml:"text"`
+	Examples []Example `yaml:"examples,omitempty"`
+}
+
+// AllExamples returns top-level examples followed by the examples of each rule.
+// Example IDs are unique across both, so callers can treat them as one list.
+func (s *Spec) AllExamples() []Example {
+	n := len(s.Examples)
+	for _, r := range s.Rules {
+		n += len(r.Examples)
+	}
+	out := make([]Example, 0, n)
+	out = append(out, s.Examples...)
+	for _, r := range s.Rules {
+		out = append(out, r.Examples...)
+	}
+	return out
+}
+
+// FindRule returns the rule with the given ID, or nil.
+func (s *Spec) FindRule(id string) *Rule {
+	for i := range s.Rules {
+		if s.Rules[i].ID == id {
+			return &s.Rules[i]
+		}
+	}
+	return nil
+}
+
+// FindExample returns the example with the given ID, top-level or in a rule,
+// or nil.
+func (s *Spec) FindExample(id string) *Example {
+	for i := range s.Examples {
+		if s.Examples[i].ID == id {
+			return &s.Examples[i]
+		}
+	}
+	for i := range s.Rules {
+		for j := range s.Rules[i].Examples {
+			if s.Rules[i].Examples[j].ID == id {
+				return &s.Rules[i].Examples[j]
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveExample removes the example with the given ID. It returns the removed
+// example and the ID of the rule it belonged to ("" for top-level).
+func (s *Spec) RemoveExample(id string) (Example, string, bool) {
+	for i, ex := range s.Examples {
+		if ex.ID == id {
+			s.Examples = append(s.Examples[:i], s.Examples[i+1:]...)
+			return ex, "", true
+		}
+	}
+	for i := range s.Rules {
+		r := &s.Rules[i]
+		for j, ex := range r.Examples {
+			if ex.ID == id {
+				r.Examples = append(r.Examples[:j], r.Examples[j+1:]...)
+				return ex, r.ID, true
+			}
+		}
+	}
+	return Example{}, "", false
+}
+
+// NextRuleID returns the next rule ID for the spec.
+func NextRuleID(s *Spec) string {
+	max := 0
+	for _, r := range s.Rules {
+		if matches := ruleIDPattern.FindStringSubmatch(strings.TrimSpace(r.ID)); len(matches) == 2 {
+			val, _ := strconv.Atoi(matches[1])
+			if val > max {
+				max = val
+			}
+		}
+	}
+	return fmt.Sprintf("R%d", max+1)
+}
+
+func validateRules(s *Spec) error {
+	rules := s.Rules
+	if len(rules) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		if strings.TrimSpace(r.Text) == "" {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, fmt.Sprintf("rule %d must include text", i+1))
+		}
+		if r.ID != "" {
+			if !ruleIDPattern.MatchString(r.ID) {
+				return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+					fmt.Sprintf("rule id %q must match R#", r.ID))
+			}
+			if seen[r.ID] {
+				return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+					fmt.Sprintf("duplicate rule id %q", r.ID))
+			}
+			seen[r.ID] = true
+		}
+		for j, ex := range r.Examples {
+			if strings.TrimSpace(ex.Given) == "" || strings.TrimSpace(ex.When) == "" || strings.TrimSpace(ex.Then) == "" {
+				return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+					fmt.Sprintf("rule %d example %d must include given/when/then", i+1, j+1))
+			}
+		}
+	}
+
+	// Rule examples share the spec's example ID space so test names stay unique
+	exampleIDs := make(map[string]bool)
+	for _, ex := range s.AllExamples() {
+		if ex.ID == "" {
+			continue
+		}
+		if exampleIDs[ex.ID] {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+				fmt.Sprintf("duplicate example id %q", ex.ID))
+		}
+		exampleIDs[ex.ID] = true
+	}
+	return nil
+}
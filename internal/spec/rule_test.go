@@ -0,0 +1,118 @@
+package spec
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRulesNormalize(t *testing.T) {
+	s := &Spec{
+		ID:       "REQ-001",
+		Title:    "Withdraw cash",
+		Examples: []Example{{ID: "E1", Given: "a", When: "b", Then: "c"}},
+		Rules: []Rule{
+			{Text: "Balance must cover the amount", Examples: []Example{
+				{Given: "balance 100", When: "withdraw 50", Then: "ok"},
+				{Given: "balance 10", When: "withdraw 50", Then: "rejected"},
+			}},
+			{ID: "R5", Text: "Daily limit applies"},
+			{Text: "Card must be valid"},
+		},
+	}
+
+	s.Normalize()
+
+	if s.Rules[0].ID != "R6" || s.Rules[2].ID != "R7" {
+		t.Errorf("rule IDs = %s, %s, want R6, R7", s.Rules[0].ID, s.Rules[2].ID)
+	}
+	if s.Rules[0].Examples[0].ID != "E2" || s.Rules[0].Examples[1].ID != "E3" {
+		t.Errorf("rule example IDs should continue after top-level: %+v", s.Rules[0].Examples)
+	}
+	if got := len(s.AllExamples()); got != 3 {
+		t.Errorf("AllExamples = %d, want 3", got)
+	}
+	if next := NextExampleID(s); next != "E4" {
+		t.Errorf("NextExampleID = %q, want E4", next)
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "REQ-001.yml")
+	if err := Save(path, s); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(loaded.Rules) != 3 || len(loaded.Rules[0].Examples) != 2 {
+		t.Errorf("rules not round-tripped: %+v", loaded.Rules)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	ex := func(id string) Example { return Example{ID: id, Given: "a", When: "b", Then: "c"} }
+
+	tests := []struct {
+		name  string
+		spec  Spec
+		valid bool
+	}{
+		{"top-level examples only", Spec{Examples: []Example{ex("E1")}}, true},
+		{"missing rule text", Spec{Rules: []Rule{{ID: "R1"}}}, false},
+		{"bad rule id", Spec{Rules: []Rule{{ID: "rule-1", Text: "x"}}}, false},
+		{"duplicate rule id", Spec{Rules: []Rule{{ID: "R1", Text: "x"}, {ID: "R1", Text: "y"}}}, false},
+		{"incomplete rule example", Spec{Rules: []Rule{{ID: "R1", Text: "x", Examples: []Example{{Given: "a"}}}}}, false},
+		{"example id shared with rule", Spec{
+			Examples: []Example{ex("E1")},
+			Rules:    []Rule{{ID: "R1", Text: "x", Examples: []Example{ex("E1")}}},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.spec
+			s.ID, s.Title = "REQ-001", "A"
+			err := s.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestFindAndRemoveExample(t *testing.T) {
+	s := &Spec{
+		ID:       "REQ-001",
+		Title:    "Withdraw cash",
+		Examples: []Example{{ID: "E1", Given: "a", When: "b", Then: "c"}},
+		Rules: []Rule{{ID: "R1", Text: "Balance", Examples: []Example{
+			{ID: "E2", Given: "balance 10", When: "withdraw 50", Then: "rejected"},
+		}}},
+	}
+
+	if ex := s.FindExample("E2"); ex == nil || ex.Then != "rejected" {
+		t.Fatalf("FindExample(E2) = %+v", ex)
+	}
+	s.FindExample("E1").Then = "changed"
+	if s.Examples[0].Then != "changed" {
+		t.Error("FindExample should return a pointer into the spec")
+	}
+	if s.FindExample("E9") != nil {
+		t.Error("FindExample(E9) should be nil")
+	}
+
+	ex, rule, ok := s.RemoveExample("E2")
+	if !ok || ex.ID != "E2" || rule != "R1" || len(s.Rules[0].Examples) != 0 {
+		t.Errorf("RemoveExample(E2) = %+v, %q, %v", ex, rule, ok)
+	}
+	if _, rule, ok := s.RemoveExample("E1"); !ok || rule != "" || len(s.Examples) != 0 {
+		t.Errorf("RemoveExample(E1) = %q, %v", rule, ok)
+	}
+	if _, _, ok := s.RemoveExample("E1"); ok {
+		t.Error("removing twice should fail")
+	}
+}
@@ -3,39 +3,63 @@ package spec
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/atomicfile"
+	"github.com/thirdlf03/spec-tdd/internal/migrate"
 	"go.yaml.in/yaml/v3"
 )
 
-var (
-	reqIDPattern     = regexp.MustCompile(`^REQ-(\d+)$`)
-	exampleIDPattern = regexp.MustCompile(`^E(\d+)$`)
-)
+var exampleIDPattern = regexp.MustCompile(`^E(\d+)$`)
 
 // SourceInfo represents the origin of a spec imported from external tools.
 type SourceInfo struct {
 	SegmentID   string   `yaml:"segment_id,omitempty"`
 	HeadingPath []string `yaml:"heading_path,omitempty"`
 	FilePath    string   `yaml:"file_path,omitempty"`
+	OperationID string   `yaml:"operation_id,omitempty"`
+	// ReqIFIdentifier is the SPEC-OBJECT IDENTIFIER of a requirement
+	// created in a ReqIF tool, which carries no REQ ID of its own.
+	ReqIFIdentifier string `yaml:"reqif_identifier,omitempty"`
 }
 
 // Spec represents a requirement spec file.
 type Spec struct {
+	// Version is the format version of the file (see FormatVersion). Files
+	// without one are at version 1; `spec-tdd migrate` upgrades them.
+	Version     int        `yaml:"version,omitempty"`
 	ID          string     `yaml:"id"`
 	Title       string     `yaml:"title"`
+	Status      string     `yaml:"status,omitempty"`
+	Parent      string     `yaml:"parent,omitempty"`
 	Description string     `yaml:"description,omitempty"`
 	Source      SourceInfo `yaml:"source,omitempty"`
 	Depends     []string   `yaml:"depends,omitempty"`
 	Examples    []Example  `yaml:"examples,omitempty"`
-	Questions   []string   `yaml:"questions,omitempty"`
+	Rules       []Rule     `yaml:"rules,omitempty"`
+	Questions   []Question `yaml:"questions,omitempty"`
 	Tags        []string   `yaml:"tags,omitempty"`
+	Approvals   []Approval `yaml:"approvals,omitempty"`
+
+	// Namespace is the directory of the spec file relative to the spec
+	// directory ("auth", "billing/invoices"), or "" at the top level. It is
+	// set by LoadAll and not stored in the file.
+	Namespace string `yaml:"-"`
+	// Path is the file the spec was loaded from (any extension or file
+	// name). It is set by LoadAll and not stored in the file.
+	Path string `yaml:"-"`
+
+	// doc is the YAML the spec was loaded from, kept so that Save preserves
+	// comments, formatting and unknown keys.
+	doc *document
 }
 
 // Example represents a Given/When/Then example.
@@ -51,24 +75,47 @@ func (s *Spec) Validate() error {
 	if strings.TrimSpace(s.ID) == "" {
 		return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, "id is required")
 	}
-	if !reqIDPattern.MatchString(s.ID) {
-		return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, "id must match REQ-###")
+	ids := CurrentIDScheme()
+	if !ids.Valid(s.ID) {
+		return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, "id must match "+ids.Describe())
 	}
 	if strings.TrimSpace(s.Title) == "" {
 		return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, "title is required")
 	}
+	if s.Status != strings.TrimSpace(s.Status) || strings.ContainsAny(s.Status, " \t\n") {
+		return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, fmt.Sprintf("status %q must be a single word", s.Status))
+	}
+	if s.Parent != "" {
+		if !ids.Valid(s.Parent) {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+				fmt.Sprintf("parent %q must match %s format", s.Parent, ids.Describe()))
+		}
+		if s.Parent == s.ID {
+			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
+				fmt.Sprintf("parent %q is a self-reference", s.Parent))
+		}
+	}
 	for i, ex := range s.Examples {
 		if strings.TrimSpace(ex.Given) == "" || strings.TrimSpace(ex.When) == "" || strings.TrimSpace(ex.Then) == "" {
 			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput, fmt.Sprintf("example %d must include given/when/then", i+1))
 		}
 	}
+	if err := validateRules(s); err != nil {
+		return err
+	}
+	if err := validateQuestions(s.Questions); err != nil {
+		return err
+	}
+	if err := validateApprovals(s.Approvals); err != nil {
+		return err
+	}
 
 	// Validate Depends
 	seen := make(map[string]bool, len(s.Depends))
 	for _, dep := range s.Depends {
-		if !reqIDPattern.MatchString(dep) {
+		if !ids.Valid(dep) {
 			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
-				fmt.Sprintf("depends entry %q must match REQ-### format", dep))
+				fmt.Sprintf("depends entry %q must match %s format", dep, ids.Describe()))
 		}
 		if dep == s.ID {
 			return apperrors.New("spec.Validate", apperrors.ErrInvalidInput,
@@ -153,7 +200,9 @@ func ValidateDependsGraph(specs []*Spec) error {
 	return nil
 }
 
-// Normalize fills missing example IDs in-memory.
+// Normalize fills missing rule, example and question IDs and question
+// statuses in-memory. Example IDs are numbered across top-level and rule
+// examples.
 func (s *Spec) Normalize() {
 	next := NextExampleID(s)
 	for i := range s.Examples {
@@ -162,6 +211,25 @@ func (s *Spec) Normalize() {
 			next = NextExampleID(s)
 		}
 	}
+	for i := range s.Rules {
+		r := &s.Rules[i]
+		if strings.TrimSpace(r.ID) == "" {
+			r.ID = NextRuleID(s)
+		}
+		for j := range r.Examples {
+			if strings.TrimSpace(r.Examples[j].ID) == "" {
+				r.Examples[j].ID = NextExampleID(s)
+			}
+		}
+	}
+	for i := range s.Questions {
+		if strings.TrimSpace(s.Questions[i].ID) == "" {
+			s.Questions[i].ID = NextQuestionID(s)
+		}
+		if s.Questions[i].Status == "" {
+			s.Questions[i].Status = QuestionOpen
+		}
+	}
 }
 
 // Load reads a spec from disk.
@@ -170,10 +238,19 @@ func Load(path string) (*Spec, error) {
 	if err != nil {
 		return nil, apperrors.Wrap("spec.Load", err)
 	}
+	return Parse(path, data)
+}
 
+// Parse decodes the content of the spec file at path. The file name is the
+// ID when the spec has none.
+func Parse(path string, data []byte) (*Spec, error) {
 	var s Spec
 	if err := yaml.Unmarshal(data, &s); err != nil {
-		return nil, apperrors.Wrap("spec.Load", err)
+		return nil, apperrors.Wrap("spec.Parse", err)
+	}
+	s.doc = parseDocument(data)
+	if err := migrate.CheckVersion(s.Version, FormatVersion); err != nil {
+		return nil, err
 	}
 
 	if strings.TrimSpace(s.ID) == "" {
@@ -187,54 +264,108 @@ func Load(path string) (*Spec, error) {
 	return &s, nil
 }
 
-// Save writes a spec to disk.
+// Save writes a spec to disk. Comments, key order, styles and unknown keys
+// of the file s was loaded from are kept; a spec built in memory (e.g. by an
+// import) adopts the formatting of the file it overwrites.
+// The file is replaced atomically, so readers never see a partial spec.
 func Save(path string, s *Spec) error {
-	if s == nil {
-		return apperrors.New("spec.Save", apperrors.ErrInvalidInput, "spec is nil")
-	}
-	if err := s.Validate(); err != nil {
+	data, err := marshalFor(path, s)
+	if err != nil {
 		return err
 	}
 
-	data, err := yaml.Marshal(s)
-	if err != nil {
+	if err := atomicfile.WriteFile(path, data, 0644); err != nil {
 		return apperrors.Wrap("spec.Save", err)
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return apperrors.Wrap("spec.Save", err)
-	}
+	return nil
+}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return apperrors.Wrap("spec.Save", err)
+// Stage adds saving s to path to tx, for changes to several specs that
+// must be applied together. It formats the file like Save.
+func Stage(tx *atomicfile.Tx, path string, s *Spec) error {
+	data, err := marshalFor(path, s)
+	if err != nil {
+		return err
 	}
-
+	tx.Write(path, data, 0644)
 	return nil
 }
 
-// ListFiles returns spec files in the spec directory.
-func ListFiles(specDir string) ([]string, error) {
-	entries, err := os.ReadDir(specDir)
+// marshalFor encodes s as it is written to path.
+func marshalFor(path string, s *Spec) ([]byte, error) {
+	if s == nil {
+		return nil, apperrors.New("spec.Save", apperrors.ErrInvalidInput, "spec is nil")
+	}
+	if s.doc == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			s.doc = parseDocument(data)
+		}
+	}
+	return Marshal(s)
+}
+
+// Marshal validates a spec and encodes it as YAML, as Save writes it.
+func Marshal(s *Spec) ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	var (
+		data []byte
+		err  error
+	)
+	// A spec built in memory takes the version of the file it overwrites, or
+	// the current format for a new file
+	out := *s
+	out.Questions = slices.Clone(s.Questions)
+	completeQuestions(&out)
+	if out.Version == 0 {
+		out.Version = FormatVersion
+		if s.doc != nil {
+			out.Version = s.doc.version()
+		}
+	}
+	if s.doc != nil {
+		data, err = s.doc.encode(&out)
+	} else {
+		data, err = yaml.Marshal(&out)
+	}
 	if err != nil {
-		return nil, apperrors.Wrap("spec.ListFiles", err)
+		return nil, apperrors.Wrap("spec.Marshal", err)
 	}
+	return data, nil
+}
 
+// ListFiles returns spec files in the spec directory and its
+// subdirectories (namespaces). Hidden directories are skipped.
+func ListFiles(specDir string) ([]string, error) {
 	files := make([]string, 0)
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+	err := filepath.WalkDir(specDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-		if filepath.Ext(entry.Name()) != ".yml" && filepath.Ext(entry.Name()) != ".yaml" {
-			continue
+		if d.IsDir() {
+			if path != specDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
 		}
-		files = append(files, filepath.Join(specDir, entry.Name()))
+		if filepath.Ext(path) != ".yml" && filepath.Ext(path) != ".yaml" {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Wrap("spec.ListFiles", err)
 	}
 
 	sort.Strings(files)
 	return files, nil
 }
 
-// LoadAll loads all specs from a directory.
+// LoadAll loads all specs from a directory tree, setting their namespaces.
+// IDs must be unique across the whole tree.
 func LoadAll(specDir string) ([]*Spec, error) {
 	files, err := ListFiles(specDir)
 	if err != nil {
@@ -244,12 +375,62 @@ func LoadAll(specDir string) ([]*Spec, error) {
 		return nil, err
 	}
 
-	out := make([]*Spec, 0, len(files))
-	for _, path := range files {
-		s, err := Load(path)
+	loaded, err := loadFiles(files, true)
+	if err != nil {
+		return nil, err
+	}
+	return collect(specDir, files, loaded)
+}
+
+// ParseAll is LoadAll for spec files that are not read from disk (e.g. from
+// a git revision), given as content by path. Paths outside specDir, in
+// hidden directories or without a YAML extension are ignored.
+func ParseAll(specDir string, contents map[string][]byte) ([]*Spec, error) {
+	var files []string
+	for path := range contents {
+		if isSpecFile(specDir, path) {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	loaded := make([]*Spec, len(files))
+	for i, path := range files {
+		s, err := Parse(path, contents[path])
 		if err != nil {
-			return nil, err
+			return nil, apperrors.Wrapf("spec.ParseAll", err, "%s", path)
+		}
+		loaded[i] = s
+	}
+	return collect(specDir, files, loaded)
+}
+
+// isSpecFile reports whether ListFiles would return path.
+func isSpecFile(specDir, path string) bool {
+	if filepath.Ext(path) != ".yml" && filepath.Ext(path) != ".yaml" {
+		return false
+	}
+	rel, err := filepath.Rel(specDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	return !slices.ContainsFunc(dirs, func(d string) bool { return d != "." && strings.HasPrefix(d, ".") })
+}
+
+// collect sets the namespaces of the specs loaded from files, checks that
+// their IDs are unique and sorts them by ID.
+func collect(specDir string, files []string, loaded []*Spec) ([]*Spec, error) {
+	out := make([]*Spec, 0, len(files))
+	seen := make(map[string]string, len(files))
+	for i, path := range files {
+		s := loaded[i]
+		if prev, dup := seen[s.ID]; dup {
+			return nil, apperrors.New("spec.LoadAll", apperrors.ErrInvalidInput,
+				fmt.Sprintf("duplicate ID %s in %s and %s", s.ID, prev, path))
 		}
+		seen[s.ID] = path
+		s.Namespace = NamespaceOf(specDir, path)
+		s.Path = path
 		out = append(out, s)
 	}
 
@@ -260,45 +441,57 @@ func LoadAll(specDir string) ([]*Spec, error) {
 	return out, nil
 }
 
-// NextReqID returns the next available REQ-### ID in the directory.
+// NextReqID returns the next available ID with the default prefix.
 func NextReqID(specDir string) (string, error) {
+	return NextReqIDFor(specDir, CurrentIDScheme().DefaultPrefix())
+}
+
+// NextReqIDFor returns the next available ID with prefix in the directory,
+// numbered per prefix or globally as configured.
+func NextReqIDFor(specDir, prefix string) (string, error) {
+	ids, err := ListIDs(specDir)
+	if err != nil {
+		return "", err
+	}
+	return CurrentIDScheme().Next(prefix, ids), nil
+}
+
+// ListIDs returns the IDs of all spec files in the directory, taken from the
+// file name when it is a valid ID and from the spec otherwise.
+func ListIDs(specDir string) ([]string, error) {
 	files, err := ListFiles(specDir)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return "REQ-001", nil
+			return nil, nil
 		}
-		return "", err
+		return nil, err
 	}
 
-	max := 0
+	scheme := CurrentIDScheme()
+	ids := make([]string, 0, len(files))
+	var unnamed []string
 	for _, path := range files {
 		base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
-		if matches := reqIDPattern.FindStringSubmatch(base); len(matches) == 2 {
-			val, _ := strconv.Atoi(matches[1])
-			if val > max {
-				max = val
-			}
+		if scheme.Valid(base) {
+			ids = append(ids, base)
 			continue
 		}
-		s, err := Load(path)
-		if err != nil {
-			return "", err
-		}
-		if matches := reqIDPattern.FindStringSubmatch(s.ID); len(matches) == 2 {
-			val, _ := strconv.Atoi(matches[1])
-			if val > max {
-				max = val
-			}
-		}
+		unnamed = append(unnamed, path)
 	}
-
-	return fmt.Sprintf("REQ-%03d", max+1), nil
+	specs, err := loadFiles(unnamed, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range specs {
+		ids = append(ids, s.ID)
+	}
+	return ids, nil
 }
 
-// NextExampleID returns the next example ID for the spec.
+// NextExampleID returns the next example ID for the spec, including rule examples.
 func NextExampleID(s *Spec) string {
 	max := 0
-	for _, ex := range s.Examples {
+	for _, ex := range s.AllExamples() {
 		if matches := exampleIDPattern.FindStringSubmatch(strings.TrimSpace(ex.ID)); len(matches) == 2 {
 			val, _ := strconv.Atoi(matches[1])
 			if val > max {
@@ -310,12 +503,5 @@ func NextExampleID(s *Spec) string {
 }
 
 func reqIDLess(a, b string) bool {
-	am := reqIDPattern.FindStringSubmatch(a)
-	bm := reqIDPattern.FindStringSubmatch(b)
-	if len(am) == 2 && len(bm) == 2 {
-		ai, _ := strconv.Atoi(am[1])
-		bi, _ := strconv.Atoi(bm[1])
-		return ai < bi
-	}
-	return a < b
+	return CurrentIDScheme().Less(a, b)
 }
@@ -0,0 +1,398 @@
+// Package specdiff compares two sets of specs (e.g. at two git revisions),
+// matching requirements, rules, examples and questions by ID, and renders
+// the differences as Markdown or JSON.
+package specdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+// Report is the difference between two sets of specs.
+type Report struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Added   []Requirement `json:"added"`
+	Removed []Requirement `json:"removed"`
+	Changed []Change      `json:"changed"`
+}
+
+// Requirement is an added or removed requirement.
+type Requirement struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Depends  []string  `json:"depends,omitempty"`
+	Examples []Example `json:"examples,omitempty"`
+}
+
+// Change lists the differences within one requirement.
+type Change struct {
+	ID      string        `json:"id"`
+	Title   string        `json:"title"`
+	Fields  []FieldChange `json:"fields,omitempty"`
+	Depends *ListChange   `json:"depends,omitempty"`
+
+	ExamplesAdded    []Example        `json:"examplesAdded,omitempty"`
+	ExamplesRemoved  []Example        `json:"examplesRemoved,omitempty"`
+	ExamplesChanged  []ExampleChange  `json:"examplesChanged,omitempty"`
+	QuestionsAdded   []Question       `json:"questionsAdded,omitempty"`
+	QuestionsRemoved []Question       `json:"questionsRemoved,omitempty"`
+	QuestionsChanged []QuestionChange `json:"questionsChanged,omitempty"`
+}
+
+// FieldChange is a changed scalar field, such as the title, status or the
+// text of a rule ("rule R1").
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ListChange is the difference between two lists of IDs.
+type ListChange struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Example is an example with the rule it belongs to.
+type Example struct {
+	ID    string `json:"id"`
+	Rule  string `json:"rule,omitempty"`
+	Given string `json:"given"`
+	When  string `json:"when"`
+	Then  string `json:"then"`
+}
+
+// ExampleChange is an example that exists in both sets.
+type ExampleChange struct {
+	ID     string  `json:"id"`
+	Before Example `json:"before"`
+	After  Example `json:"after"`
+}
+
+// Question is a question with its resolution.
+type Question struct {
+	ID     string `json:"id"`
+	Text   string `json:"text"`
+	Status string `json:"status"`
+	Owner  string `json:"owner,omitempty"`
+	Answer string `json:"answer,omitempty"`
+}
+
+// QuestionChange is a question that exists in both sets.
+type QuestionChange struct {
+	ID     string   `json:"id"`
+	Before Question `json:"before"`
+	After  Question `json:"after"`
+}
+
+// Empty reports whether the sets are equal.
+func (r Report) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// Compare returns the difference from the specs before to the specs after.
+// Missing rule, example and question IDs are filled in (as migrate does) so
+// that older files can be compared by ID; the specs are not modified.
+func Compare(before, after []*spec.Spec) Report {
+	r := Report{Added: []Requirement{}, Removed: []Requirement{}, Changed: []Change{}}
+	old := make(map[string]*spec.Spec, len(before))
+	for _, s := range before {
+		old[s.ID] = normalized(s)
+	}
+	seen := make(map[string]bool, len(after))
+	for _, s := range after {
+		s = normalized(s)
+		seen[s.ID] = true
+		prev, ok := old[s.ID]
+		if !ok {
+			r.Added = append(r.Added, requirement(s))
+			continue
+		}
+		if c, changed := compareSpec(prev, s); changed {
+			r.Changed = append(r.Changed, c)
+		}
+	}
+	for _, s := range before {
+		if !seen[s.ID] {
+			r.Removed = append(r.Removed, requirement(old[s.ID]))
+		}
+	}
+	return r
+}
+
+func normalized(s *spec.Spec) *spec.Spec {
+	c := s.Clone()
+	c.Normalize()
+	return c
+}
+
+func requirement(s *spec.Spec) Requirement {
+	return Requirement{ID: s.ID, Title: s.Title, Depends: s.Depends, Examples: examples(s)}
+}
+
+func compareSpec(before, after *spec.Spec) (Change, bool) {
+	c := Change{ID: after.ID, Title: after.Title}
+	field := func(name, b, a string) {
+		if b != a {
+			c.Fields = append(c.Fields, FieldChange{Field: name, Before: b, After: a})
+		}
+	}
+	field("title", before.Title, after.Title)
+	field("status", before.Status, after.Status)
+	field("namespace", before.Namespace, after.Namespace)
+	field("parent", before.Parent, after.Parent)
+	field("description", strings.TrimSpace(before.Description), strings.TrimSpace(after.Description))
+	field("tags", strings.Join(before.Tags, ", "), strings.Join(after.Tags, ", "))
+	for _, id := range union(ruleIDs(before), ruleIDs(after)) {
+		field("rule "+id, ruleText(before, id), ruleText(after, id))
+	}
+
+	if added, removed := listDiff(before.Depends, after.Depends); len(added)+len(removed) > 0 {
+		c.Depends = &ListChange{Added: added, Removed: removed}
+	}
+
+	oldExamples := examples(before)
+	newExamples := examples(after)
+	for _, e := range newExamples {
+		i := slices.IndexFunc(oldExamples, func(o Example) bool { return o.ID == e.ID })
+		switch {
+		case i < 0:
+			c.ExamplesAdded = append(c.ExamplesAdded, e)
+		case oldExamples[i] != e:
+			c.ExamplesChanged = append(c.ExamplesChanged, ExampleChange{ID: e.ID, Before: oldExamples[i], After: e})
+		}
+	}
+	for _, e := range oldExamples {
+		if !slices.ContainsFunc(newExamples, func(n Example) bool { return n.ID == e.ID }) {
+			c.ExamplesRemoved = append(c.ExamplesRemoved, e)
+		}
+	}
+
+	oldQuestions := questions(before)
+	newQuestions := questions(after)
+	for _, q := range newQuestions {
+		i := slices.IndexFunc(oldQuestions, func(o Question) bool { return o.ID == q.ID })
+		switch {
+		case i < 0:
+			c.QuestionsAdded = append(c.QuestionsAdded, q)
+		case oldQuestions[i] != q:
+			c.QuestionsChanged = append(c.QuestionsChanged, QuestionChange{ID: q.ID, Before: oldQuestions[i], After: q})
+		}
+	}
+	for _, q := range oldQuestions {
+		if !slices.ContainsFunc(newQuestions, func(n Question) bool { return n.ID == q.ID }) {
+			c.QuestionsRemoved = append(c.QuestionsRemoved, q)
+		}
+	}
+
+	changed := len(c.Fields) > 0 || c.Depends != nil ||
+		len(c.ExamplesAdded)+len(c.ExamplesRemoved)+len(c.ExamplesChanged) > 0 ||
+		len(c.QuestionsAdded)+len(c.QuestionsRemoved)+len(c.QuestionsChanged) > 0
+	return c, changed
+}
+
+func examples(s *spec.Spec) []Example {
+	var out []Example
+	add := func(rule string, list []spec.Example) {
+		for _, e := range list {
+			out = append(out, Example{ID: e.ID, Rule: rule, Given: e.Given, When: e.When, Then: e.Then})
+		}
+	}
+	add("", s.Examples)
+	for _, r := range s.Rules {
+		add(r.ID, r.Examples)
+	}
+	return out
+}
+
+func questions(s *spec.Spec) []Question {
+	out := make([]Question, 0, len(s.Questions))
+	for _, q := range s.Questions {
+		out = append(out, Question{ID: q.ID, Text: q.Text, Status: q.Status, Owner: q.Owner, Answer: q.Answer})
+	}
+	return out
+}
+
+func ruleIDs(s *spec.Spec) []string {
+	ids := make([]string, 0, len(s.Rules))
+	for _, r := range s.Rules {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func ruleText(s *spec.Spec, id string) string {
+	if r := s.FindRule(id); r != nil {
+		return r.Text
+	}
+	return ""
+}
+
+// listDiff returns the items only in after and the items only in before.
+func listDiff(before, after []string) (added, removed []string) {
+	for _, a := range after {
+		if !slices.Contains(before, a) {
+			added = append(added, a)
+		}
+	}
+	for _, b := range before {
+		if !slices.Contains(after, b) {
+			removed = append(removed, b)
+		}
+	}
+	return added, removed
+}
+
+// union returns the items of a followed by those of b not in a.
+func union(a, b []string) []string {
+	out := slices.Clone(a)
+	for _, s := range b {
+		if !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ToJSON encodes the report to JSON.
+func (r Report) ToJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, apperrors.Wrap("specdiff.ToJSON", err)
+	}
+	return data, nil
+}
+
+// ToMarkdown renders the report in Markdown for a pull request comment.
+func (r Report) ToMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Spec changes (%s → %s)\n\n", r.From, r.To))
+	if r.Empty() {
+		sb.WriteString("No spec changes.\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%d added, %d removed, %d changed\n", len(r.Added), len(r.Removed), len(r.Changed)))
+
+	if len(r.Added) > 0 {
+		sb.WriteString("\n### Added\n\n")
+		for _, req := range r.Added {
+			sb.WriteString(fmt.Sprintf("- **%s** %s%s\n", req.ID, req.Title, countNote(len(req.Examples), "example")))
+			if len(req.Depends) > 0 {
+				sb.WriteString(fmt.Sprintf("  - depends on %s\n", strings.Join(req.Depends, ", ")))
+			}
+		}
+	}
+	if len(r.Removed) > 0 {
+		sb.WriteString("\n### Removed\n\n")
+		for _, req := range r.Removed {
+			sb.WriteString(fmt.Sprintf("- **%s** %s%s\n", req.ID, req.Title, countNote(len(req.Examples), "example")))
+		}
+	}
+	if len(r.Changed) > 0 {
+		sb.WriteString("\n### Changed\n")
+		for _, c := range r.Changed {
+			sb.WriteString(fmt.Sprintf("\n#### %s %s\n\n", c.ID, c.Title))
+			for _, f := range c.Fields {
+				sb.WriteString(fmt.Sprintf("- %s: %s → %s\n", f.Field, code(f.Before), code(f.After)))
+			}
+			if c.Depends != nil {
+				var parts []string
+				for _, id := range c.Depends.Added {
+					parts = append(parts, "+"+id)
+				}
+				for _, id := range c.Depends.Removed {
+					parts = append(parts, "-"+id)
+				}
+				sb.WriteString(fmt.Sprintf("- depends: %s\n", strings.Join(parts, ", ")))
+			}
+			for _, e := range c.ExamplesAdded {
+				sb.WriteString(fmt.Sprintf("- example %s added: %s\n", e.ID, gwt(e)))
+			}
+			for _, e := range c.ExamplesChanged {
+				sb.WriteString(fmt.Sprintf("- example %s changed\n", e.ID))
+				for _, f := range exampleFields(e.Before, e.After) {
+					sb.WriteString(fmt.Sprintf("  - %s: %s → %s\n", f.Field, code(f.Before), code(f.After)))
+				}
+			}
+			for _, e := range c.ExamplesRemoved {
+				sb.WriteString(fmt.Sprintf("- example %s removed: %s\n", e.ID, gwt(e)))
+			}
+			for _, q := range c.QuestionsAdded {
+				sb.WriteString(fmt.Sprintf("- question %s added (%s): %s\n", q.ID, q.Status, q.Text))
+			}
+			for _, q := range c.QuestionsChanged {
+				sb.WriteString(fmt.Sprintf("- question %s changed: %s\n", q.ID, q.After.Text))
+				for _, f := range questionFields(q.Before, q.After) {
+					sb.WriteString(fmt.Sprintf("  - %s: %s → %s\n", f.Field, code(f.Before), code(f.After)))
+				}
+			}
+			for _, q := range c.QuestionsRemoved {
+				sb.WriteString(fmt.Sprintf("- question %s removed: %s\n", q.ID, q.Text))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func exampleFields(before, after Example) []FieldChange {
+	var out []FieldChange
+	for _, f := range []FieldChange{
+		{"rule", before.Rule, after.Rule},
+		{"given", before.Given, after.Given},
+		{"when", before.When, after.When},
+		{"then", before.Then, after.Then},
+	} {
+		if f.Before != f.After {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func questionFields(before, after Question) []FieldChange {
+	var out []FieldChange
+	for _, f := range []FieldChange{
+		{"text", before.Text, after.Text},
+		{"status", before.Status, after.Status},
+		{"owner", before.Owner, after.Owner},
+		{"answer", before.Answer, after.Answer},
+	} {
+		if f.Before != f.After {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func gwt(e Example) string {
+	return fmt.Sprintf("Given %s, When %s, Then %s", oneLine(e.Given), oneLine(e.When), oneLine(e.Then))
+}
+
+func countNote(n int, noun string) string {
+	if n == 0 {
+		return ""
+	}
+	if n != 1 {
+		noun += "s"
+	}
+	return fmt.Sprintf(" (%d %s)", n, noun)
+}
+
+// code formats a value as inline code, or "(none)" when it is empty.
+func code(s string) string {
+	s = oneLine(s)
+	if s == "" {
+		return "(none)"
+	}
+	return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
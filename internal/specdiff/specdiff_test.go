@@ -0,0 +1,95 @@
+package specdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thirdlf03/spec-tdd/internal/spec"
+)
+
+func TestCompare(t *testing.T) {
+	before := []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Status: "draft", Depends: []string{"REQ-002"},
+			Examples:  []spec.Example{{Given: "a user", When: "logging in", Then: "a token"}, {Given: "x", When: "y", Then: "z"}},
+			Questions: []spec.Question{{Text: "Session length?"}}},
+		{ID: "REQ-002", Title: "Logout"},
+		{ID: "REQ-003", Title: "Unchanged", Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t"}}},
+	}
+	after := []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Status: "ready", Depends: []string{"REQ-004"},
+			Examples:  []spec.Example{{ID: "E1", Given: "a user", When: "logging in", Then: "a JWT"}, {ID: "E3", Given: "g", When: "w", Then: "t"}},
+			Questions: []spec.Question{{ID: "Q1", Text: "Session length?", Status: spec.QuestionResolved, Answer: "30m"}}},
+		{ID: "REQ-003", Title: "Unchanged", Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t"}}},
+		{ID: "REQ-004", Title: "Reset", Examples: []spec.Example{{ID: "E1"}}},
+	}
+
+	r := Compare(before, after)
+
+	if len(r.Added) != 1 || r.Added[0].ID != "REQ-004" {
+		t.Errorf("Added = %+v", r.Added)
+	}
+	if len(r.Removed) != 1 || r.Removed[0].ID != "REQ-002" {
+		t.Errorf("Removed = %+v", r.Removed)
+	}
+	if len(r.Changed) != 1 {
+		t.Fatalf("Changed = %+v, want only REQ-001", r.Changed)
+	}
+	c := r.Changed[0]
+
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"status field", len(c.Fields) == 1 && c.Fields[0] == FieldChange{"status", "draft", "ready"}},
+		{"depends", c.Depends != nil && strings.Join(c.Depends.Added, ",") == "REQ-004" && strings.Join(c.Depends.Removed, ",") == "REQ-002"},
+		{"example E1 changed (ID filled in)", len(c.ExamplesChanged) == 1 && c.ExamplesChanged[0].ID == "E1" && c.ExamplesChanged[0].After.Then == "a JWT"},
+		{"example E3 added", len(c.ExamplesAdded) == 1 && c.ExamplesAdded[0].ID == "E3"},
+		{"example E2 removed", len(c.ExamplesRemoved) == 1 && c.ExamplesRemoved[0].ID == "E2"},
+		{"question Q1 changed", len(c.QuestionsChanged) == 1 && c.QuestionsChanged[0].Before.Status == spec.QuestionOpen && c.QuestionsChanged[0].After.Answer == "30m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.ok {
+				t.Errorf("unexpected change: %+v", c)
+			}
+		})
+	}
+
+	if before[0].Examples[0].ID != "" {
+		t.Error("Compare must not modify its input")
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		r := Compare(nil, nil)
+		r.From, r.To = "main", "HEAD"
+		if got := r.ToMarkdown(); got != "## Spec changes (main → HEAD)\n\nNo spec changes.\n" {
+			t.Errorf("ToMarkdown = %q", got)
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		r := Compare(
+			[]*spec.Spec{{ID: "REQ-001", Title: "Login", Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t"}}}},
+			[]*spec.Spec{
+				{ID: "REQ-001", Title: "Sign in", Depends: []string{"REQ-002"}, Examples: []spec.Example{{ID: "E1", Given: "g", When: "w", Then: "t2"}},
+					Questions: []spec.Question{{ID: "Q1", Text: "Lockout?"}}},
+				{ID: "REQ-002", Title: "Accounts"},
+			},
+		)
+		r.From, r.To = "main", "working tree"
+		got := r.ToMarkdown()
+		for _, want := range []string{
+			"1 added, 0 removed, 1 changed",
+			"### Added\n\n- **REQ-002** Accounts\n",
+			"#### REQ-001 Sign in\n\n- title: `Login` → `Sign in`\n- depends: +REQ-002\n",
+			"- example E1 changed\n  - then: `t` → `t2`\n",
+			"- question Q1 added (open): Lockout?\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("markdown missing %q:\n%s", want, got)
+			}
+		}
+	})
+}
@@ -12,10 +12,20 @@ import (
 	"time"
 
 	"github.com/thirdlf03/spec-tdd/internal/apperrors"
+	"github.com/thirdlf03/spec-tdd/internal/baseline"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
-var testReqPattern = regexp.MustCompile(`(?m)^\s*(it|test)\s*\(\s*["'](REQ-\d+)`)
+// testReqPattern matches it()/test() names starting with a requirement ID
+// of the current ID scheme.
+func testReqPattern() *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\s*(it|test)\s*\(\s*["'](` + spec.CurrentIDScheme().Pattern() + `)\b`)
+}
+
+// testExamplePattern matches it()/test() names of the form "<ID> E#".
+func testExamplePattern() *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\s*(it|test)\s*\(\s*["'](` + spec.CurrentIDScheme().Pattern() + `)\s+(E\d+)\b`)
+}
 
 // Item represents a traceability entry.
 type Item struct {
@@ -24,17 +34,73 @@ type Item struct {
 	Expected int    `json:"expected"`
 	Actual   int    `json:"actual"`
 	Status   string `json:"status"`
+	// SpecStatus is the lifecycle status of the requirement (draft, ready, ...).
+	SpecStatus string     `json:"specStatus,omitempty"`
+	Parent     string     `json:"parent,omitempty"`
+	Rules      []RuleItem `json:"rules,omitempty"`
+	// Rollup aggregates this requirement and all of its descendants.
+	// It is only set for requirements that have children.
+	Rollup *Rollup `json:"rollup,omitempty"`
+	// BaselineChange is set when the requirement changed since the baseline
+	// given to AddBaseline, i.e. its tests need review.
+	BaselineChange *BaselineChange `json:"baselineChange,omitempty"`
+}
+
+// BaselineChange describes how a requirement differs from the baseline.
+type BaselineChange struct {
+	// Change is "added" or "changed".
+	Change string `json:"change"`
+	// Examples lists the added, changed and removed examples.
+	Examples []string `json:"examples,omitempty"`
+}
+
+// BaselineRemoved is a baseline requirement that no longer exists, with the
+// number of tests that still reference it.
+type BaselineRemoved struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Tests int    `json:"tests"`
+}
+
+// Rollup is the coverage of a requirement together with its descendants.
+// Actual counts tests up to the expected number per requirement, so that an
+// over-tested child cannot hide a missing one.
+type Rollup struct {
+	Requirements int    `json:"requirements"`
+	Covered      int    `json:"covered"`
+	Expected     int    `json:"expected"`
+	Actual       int    `json:"actual"`
+	Status       string `json:"status"`
+}
+
+// RuleItem is the coverage of one rule: how many of its examples have a
+// test named "REQ-### E#".
+type RuleItem struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	Expected int    `json:"expected"`
+	Actual   int    `json:"actual"`
+	Status   string `json:"status"`
 }
 
 // Report represents traceability results.
 type Report struct {
 	GeneratedAt time.Time `json:"generatedAt"`
 	Items       []Item    `json:"items"`
+	// Excluded lists requirements left out of coverage (e.g. deprecated).
+	Excluded []string `json:"excluded,omitempty"`
+	// Baseline is the baseline the report was compared with, if any.
+	Baseline        string            `json:"baseline,omitempty"`
+	BaselineRemoved []BaselineRemoved `json:"baselineRemoved,omitempty"`
+
+	hierarchy *spec.Hierarchy
 }
 
-// CountTestsByReq scans tests and counts REQ references in it()/test() names.
+// CountTestsByReq scans tests and counts requirement ID references in
+// it()/test() names.
 func CountTestsByReq(testDir string) (map[string]int, error) {
 	counts := make(map[string]int)
+	pattern := testReqPattern()
 
 	err := filepath.WalkDir(testDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -43,7 +109,7 @@ func CountTestsByReq(testDir string) (map[string]int, error) {
 		if d.IsDir() {
 			return nil
 		}
-		if !isTestFile(path) {
+		if !IsTestFile(path) {
 			return nil
 		}
 
@@ -52,7 +118,7 @@ func CountTestsByReq(testDir string) (map[string]int, error) {
 			return err
 		}
 
-		matches := testReqPattern.FindAllSubmatch(data, -1)
+		matches := pattern.FindAllSubmatch(data, -1)
 		for _, m := range matches {
 			if len(m) < 3 {
 				continue
@@ -73,32 +139,62 @@ func CountTestsByReq(testDir string) (map[string]int, error) {
 	return counts, nil
 }
 
+// CountTestsByExample scans tests and counts "<ID> E#" references in
+// it()/test() names, keyed by REQ ID and then example ID.
+func CountTestsByExample(testDir string) (map[string]map[string]int, error) {
+	counts := make(map[string]map[string]int)
+	pattern := testExamplePattern()
+	err := filepath.WalkDir(testDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !IsTestFile(path) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range pattern.FindAllSubmatch(data, -1) {
+			reqID, exID := string(m[2]), string(m[3])
+			if counts[reqID] == nil {
+				counts[reqID] = make(map[string]int)
+			}
+			counts[reqID][exID]++
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return counts, nil
+		}
+		return nil, apperrors.Wrap("trace.CountTestsByExample", err)
+	}
+	return counts, nil
+}
+
 // BuildReport builds a trace report from specs and test counts.
 func BuildReport(specs []*spec.Spec, counts map[string]int) Report {
 	items := make([]Item, 0, len(specs))
 	for _, s := range specs {
-		expected := len(s.Examples)
+		expected := len(s.AllExamples())
 		actual := counts[s.ID]
-		status := "OK"
-		if expected == 0 {
-			status = "MISSING"
-		} else if actual == 0 {
-			status = "MISSING"
-		} else if actual < expected {
-			status = "PARTIAL"
-		}
+		status := coverageStatus(expected, actual)
 
 		items = append(items, Item{
-			ID:       s.ID,
-			Title:    s.Title,
-			Expected: expected,
-			Actual:   actual,
-			Status:   status,
+			ID:         s.ID,
+			Title:      s.Title,
+			Expected:   expected,
+			Actual:     actual,
+			Status:     status,
+			SpecStatus: s.Status,
 		})
 	}
 
+	ids := spec.CurrentIDScheme()
 	sort.Slice(items, func(i, j int) bool {
-		return items[i].ID < items[j].ID
+		return ids.Less(items[i].ID, items[j].ID)
 	})
 
 	return Report{
@@ -107,6 +203,147 @@ func BuildReport(specs []*spec.Spec, counts map[string]int) Report {
 	}
 }
 
+// AddRuleCoverage fills per-rule coverage for specs that have rules.
+// A rule example counts as covered when a test references its example ID.
+func (r *Report) AddRuleCoverage(specs []*spec.Spec, exampleCounts map[string]map[string]int) {
+	byID := make(map[string]*spec.Spec, len(specs))
+	for _, s := range specs {
+		byID[s.ID] = s
+	}
+
+	for i := range r.Items {
+		s, ok := byID[r.Items[i].ID]
+		if !ok || len(s.Rules) == 0 {
+			continue
+		}
+		rules := make([]RuleItem, 0, len(s.Rules))
+		for _, rule := range s.Rules {
+			actual := 0
+			for _, ex := range rule.Examples {
+				if exampleCounts[s.ID][ex.ID] > 0 {
+					actual++
+				}
+			}
+			rules = append(rules, RuleItem{
+				ID:       rule.ID,
+				Text:     rule.Text,
+				Expected: len(rule.Examples),
+				Actual:   actual,
+				Status:   coverageStatus(len(rule.Examples), actual),
+			})
+		}
+		r.Items[i].Rules = rules
+	}
+}
+
+// AddHierarchy records each requirement's parent and rolls coverage up to
+// every requirement that has children in the report.
+func (r *Report) AddHierarchy(specs []*spec.Spec) {
+	h := spec.NewHierarchy(specs)
+	r.hierarchy = h
+
+	index := make(map[string]int, len(r.Items))
+	for i, item := range r.Items {
+		index[item.ID] = i
+	}
+
+	for _, s := range specs {
+		i, ok := index[s.ID]
+		if !ok {
+			continue
+		}
+		r.Items[i].Parent = s.Parent
+		if !h.HasChildren(s.ID) {
+			continue
+		}
+
+		rollup := &Rollup{}
+		for _, member := range append([]*spec.Spec{s}, h.Descendants(s.ID)...) {
+			j, ok := index[member.ID]
+			if !ok {
+				continue
+			}
+			item := r.Items[j]
+			rollup.Requirements++
+			if item.Status == "OK" {
+				rollup.Covered++
+			}
+			rollup.Expected += item.Expected
+			rollup.Actual += min(item.Actual, item.Expected)
+		}
+		rollup.Status = coverageStatus(rollup.Expected, rollup.Actual)
+		r.Items[i].Rollup = rollup
+	}
+}
+
+// AddBaseline flags the requirements that changed since the baseline diff
+// was taken from, and lists removed baseline requirements that tests still
+// reference (counts as returned by CountTestsByReq).
+func (r *Report) AddBaseline(diff baseline.Report, counts map[string]int) {
+	r.Baseline = diff.Baseline
+	index := make(map[string]int, len(r.Items))
+	for i, item := range r.Items {
+		index[item.ID] = i
+	}
+	for _, it := range diff.Added {
+		if i, ok := index[it.ID]; ok {
+			r.Items[i].BaselineChange = &BaselineChange{Change: "added"}
+		}
+	}
+	for _, it := range diff.Changed {
+		if i, ok := index[it.ID]; ok {
+			r.Items[i].BaselineChange = &BaselineChange{Change: "changed", Examples: it.Examples()}
+		}
+	}
+	for _, it := range diff.Removed {
+		if counts[it.ID] > 0 {
+			r.BaselineRemoved = append(r.BaselineRemoved, BaselineRemoved{ID: it.ID, Title: it.Title, Tests: counts[it.ID]})
+		}
+	}
+}
+
+// BaselineMarkdown renders the requirements changed since the baseline and
+// the number of tests to review for each. It returns "" when AddBaseline
+// was not called.
+func (r Report) BaselineMarkdown() string {
+	if r.Baseline == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Changed Since Baseline %s\n\n", r.Baseline))
+	var changed []Item
+	for _, item := range r.Items {
+		if item.BaselineChange != nil {
+			changed = append(changed, item)
+		}
+	}
+	if len(changed) == 0 && len(r.BaselineRemoved) == 0 {
+		sb.WriteString("No requirements changed since the baseline.\n")
+		return sb.String()
+	}
+	sb.WriteString("| REQ ID | Title | Change | Examples | Tests to review |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, item := range changed {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %d |\n",
+			item.ID, escapePipes(item.Title), item.BaselineChange.Change, strings.Join(item.BaselineChange.Examples, ", "), item.Actual))
+	}
+	for _, rm := range r.BaselineRemoved {
+		sb.WriteString(fmt.Sprintf("| %s | %s | removed |  | %d |\n", rm.ID, escapePipes(rm.Title), rm.Tests))
+	}
+	return sb.String()
+}
+
+func coverageStatus(expected, actual int) string {
+	switch {
+	case expected == 0, actual == 0:
+		return "MISSING"
+	case actual < expected:
+		return "PARTIAL"
+	default:
+		return "OK"
+	}
+}
+
 // ToJSON encodes the report to JSON.
 func (r Report) ToJSON() ([]byte, error) {
 	data, err := json.MarshalIndent(r, "", "  ")
@@ -121,19 +358,122 @@ func (r Report) ToMarkdown() string {
 	var sb strings.Builder
 	sb.WriteString("# Traceability Report\n\n")
 	sb.WriteString(fmt.Sprintf("Generated: %s\n\n", r.GeneratedAt.Format(time.RFC3339)))
-	sb.WriteString("| REQ ID | Title | Expected | Actual | Status |\n")
-	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	sb.WriteString("| REQ ID | Title | Spec Status | Expected | Actual | Status |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, item := range r.Items {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d | %s |\n", item.ID, escapePipes(item.Title), item.SpecStatus, item.Expected, item.Actual, item.Status))
+	}
+
+	if groups := r.byStatus(); len(groups) > 0 {
+		sb.WriteString("\n## By Status\n\n")
+		sb.WriteString("| Spec Status | Requirements | Covered |\n")
+		sb.WriteString("| --- | --- | --- |\n")
+		for _, g := range groups {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %d |\n", g.status, g.total, g.ok))
+		}
+	}
+
+	if tree := r.HierarchyMarkdown(); tree != "" {
+		sb.WriteString("\n")
+		sb.WriteString(tree)
+	}
+
+	if len(r.Excluded) > 0 {
+		sb.WriteString(fmt.Sprintf("\nExcluded from coverage: %s\n", strings.Join(r.Excluded, ", ")))
+	}
+
+	if changes := r.BaselineMarkdown(); changes != "" {
+		sb.WriteString("\n")
+		sb.WriteString(changes)
+	}
+
+	hasRules := false
 	for _, item := range r.Items {
-		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %s |\n", item.ID, escapePipes(item.Title), item.Expected, item.Actual, item.Status))
+		hasRules = hasRules || len(item.Rules) > 0
+	}
+	if hasRules {
+		sb.WriteString("\n## Rule Coverage\n\n")
+		sb.WriteString("| REQ ID | Rule | Text | Expected | Actual | Status |\n")
+		sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, item := range r.Items {
+			for _, rule := range item.Rules {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d | %s |\n",
+					item.ID, rule.ID, escapePipes(rule.Text), rule.Expected, rule.Actual, rule.Status))
+			}
+		}
 	}
 	return sb.String()
 }
 
+// HierarchyMarkdown renders the requirement tree with rolled-up coverage.
+// It returns "" when no requirement has a parent or AddHierarchy was not called.
+func (r Report) HierarchyMarkdown() string {
+	if r.hierarchy == nil {
+		return ""
+	}
+	items := make(map[string]Item, len(r.Items))
+	nested := false
+	for _, item := range r.Items {
+		items[item.ID] = item
+		nested = nested || item.Rollup != nil
+	}
+	if !nested {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Hierarchy\n\n")
+	r.hierarchy.Walk(func(s *spec.Spec, depth int) {
+		item, ok := items[s.ID]
+		if !ok {
+			return
+		}
+		indent := strings.Repeat("  ", depth)
+		if item.Rollup != nil {
+			ru := item.Rollup
+			sb.WriteString(fmt.Sprintf("%s- %s %s — %d/%d requirements covered, %d/%d examples (%s)\n",
+				indent, item.ID, item.Title, ru.Covered, ru.Requirements, ru.Actual, ru.Expected, ru.Status))
+			return
+		}
+		sb.WriteString(fmt.Sprintf("%s- %s %s — %d/%d examples (%s)\n",
+			indent, item.ID, item.Title, min(item.Actual, item.Expected), item.Expected, item.Status))
+	})
+	return sb.String()
+}
+
+type statusGroup struct {
+	status    string
+	total, ok int
+}
+
+// byStatus groups items by spec status in order of first appearance.
+func (r Report) byStatus() []statusGroup {
+	var groups []statusGroup
+	index := make(map[string]int)
+	for _, item := range r.Items {
+		if item.SpecStatus == "" {
+			continue
+		}
+		i, ok := index[item.SpecStatus]
+		if !ok {
+			i = len(groups)
+			index[item.SpecStatus] = i
+			groups = append(groups, statusGroup{status: item.SpecStatus})
+		}
+		groups[i].total++
+		if item.Status == "OK" {
+			groups[i].ok++
+		}
+	}
+	return groups
+}
+
 func escapePipes(s string) string {
 	return strings.ReplaceAll(s, "|", "\\|")
 }
 
-func isTestFile(path string) bool {
+// IsTestFile reports whether path is a JS/TS test file (*.test.ts, *.spec.js, ...).
+func IsTestFile(path string) bool {
 	name := filepath.Base(path)
 	return strings.HasSuffix(name, ".test.ts") ||
 		strings.HasSuffix(name, ".test.tsx") ||
@@ -3,8 +3,10 @@ package trace
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/thirdlf03/spec-tdd/internal/baseline"
 	"github.com/thirdlf03/spec-tdd/internal/spec"
 )
 
@@ -63,3 +65,191 @@ func TestBuildReportStatus(t *testing.T) {
 		t.Fatalf("REQ-003 status = %q, want PARTIAL", statuses["REQ-003"])
 	}
 }
+
+func TestRuleCoverage(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `describe("REQ-001: Withdraw", () => {
+  it("REQ-001 E1: ok", () => {})
+  describe("R1: Balance must cover the amount", () => {
+    it("REQ-001 E2: ok", () => {})
+  })
+})`
+	if err := os.WriteFile(filepath.Join(tmpDir, "req.test.ts"), []byte(content), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	specs := []*spec.Spec{{
+		ID:       "REQ-001",
+		Title:    "Withdraw",
+		Examples: []spec.Example{{ID: "E1", Given: "a", When: "b", Then: "c"}},
+		Rules: []spec.Rule{
+			{ID: "R1", Text: "Balance must cover the amount", Examples: []spec.Example{
+				{ID: "E2", Given: "a", When: "b", Then: "c"},
+				{ID: "E3", Given: "a", When: "b", Then: "c"},
+			}},
+			{ID: "R2", Text: "Card | PIN", Examples: []spec.Example{{ID: "E4", Given: "a", When: "b", Then: "c"}}},
+		},
+	}}
+
+	counts, err := CountTestsByReq(tmpDir)
+	if err != nil {
+		t.Fatalf("CountTestsByReq error: %v", err)
+	}
+	exampleCounts, err := CountTestsByExample(tmpDir)
+	if err != nil {
+		t.Fatalf("CountTestsByExample error: %v", err)
+	}
+
+	report := BuildReport(specs, counts)
+	report.AddRuleCoverage(specs, exampleCounts)
+
+	item := report.Items[0]
+	if item.Expected != 4 || item.Status != "PARTIAL" {
+		t.Errorf("item = %+v, want 4 expected and PARTIAL", item)
+	}
+	if len(item.Rules) != 2 {
+		t.Fatalf("expected 2 rule items, got %+v", item.Rules)
+	}
+	if r := item.Rules[0]; r.Expected != 2 || r.Actual != 1 || r.Status != "PARTIAL" {
+		t.Errorf("R1 = %+v", r)
+	}
+	if r := item.Rules[1]; r.Actual != 0 || r.Status != "MISSING" {
+		t.Errorf("R2 = %+v", r)
+	}
+
+	md := report.ToMarkdown()
+	if !strings.Contains(md, "## Rule Coverage") || !strings.Contains(md, "| REQ-001 | R2 | Card \\| PIN | 1 | 0 | MISSING |") {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+}
+
+func TestAddHierarchy(t *testing.T) {
+	ex := spec.Example{Given: "a", When: "b", Then: "c"}
+	specs := []*spec.Spec{
+		{ID: "REQ-001", Title: "Checkout"},
+		{ID: "REQ-002", Title: "Cart", Parent: "REQ-001", Examples: []spec.Example{ex, ex}},
+		{ID: "REQ-003", Title: "Add item", Parent: "REQ-002", Examples: []spec.Example{ex}},
+		{ID: "REQ-004", Title: "Payment", Parent: "REQ-001", Examples: []spec.Example{ex, ex}},
+		{ID: "REQ-005", Title: "Search"},
+	}
+	// REQ-003 is over-tested; it must not make up for REQ-004
+	counts := map[string]int{"REQ-002": 2, "REQ-003": 3, "REQ-004": 1}
+
+	report := BuildReport(specs, counts)
+	report.AddHierarchy(specs)
+
+	byID := make(map[string]Item)
+	for _, item := range report.Items {
+		byID[item.ID] = item
+	}
+	if got := byID["REQ-001"].Rollup; got == nil || *got != (Rollup{Requirements: 4, Covered: 2, Expected: 5, Actual: 4, Status: "PARTIAL"}) {
+		t.Errorf("REQ-001 rollup = %+v", got)
+	}
+	if got := byID["REQ-002"].Rollup; got == nil || *got != (Rollup{Requirements: 2, Covered: 2, Expected: 3, Actual: 3, Status: "OK"}) {
+		t.Errorf("REQ-002 rollup = %+v", got)
+	}
+	if byID["REQ-003"].Rollup != nil || byID["REQ-005"].Rollup != nil {
+		t.Error("leaves should not have a rollup")
+	}
+	if byID["REQ-003"].Parent != "REQ-002" {
+		t.Errorf("REQ-003 parent = %q", byID["REQ-003"].Parent)
+	}
+
+	md := report.ToMarkdown()
+	for _, want := range []string{
+		"## Hierarchy",
+		"- REQ-001 Checkout — 2/4 requirements covered, 4/5 examples (PARTIAL)",
+		"  - REQ-002 Cart — 2/2 requirements covered, 3/3 examples (OK)",
+		"    - REQ-003 Add item — 1/1 examples (OK)",
+		"  - REQ-004 Payment — 1/2 examples (PARTIAL)",
+		"- REQ-005 Search — 0/0 examples (MISSING)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected %q in markdown:\n%s", want, md)
+		}
+	}
+
+	flat := BuildReport(specs[4:], counts)
+	flat.AddHierarchy(specs[4:])
+	if strings.Contains(flat.ToMarkdown(), "## Hierarchy") {
+		t.Error("flat specs should not render a hierarchy")
+	}
+}
+
+func TestAddBaseline(t *testing.T) {
+	specs := []*spec.Spec{
+		{ID: "REQ-001", Title: "Login", Examples: []spec.Example{{ID: "E1"}, {ID: "E2"}}},
+		{ID: "REQ-002", Title: "Logout", Examples: []spec.Example{{ID: "E1"}}},
+		{ID: "REQ-003", Title: "Signup"},
+	}
+	counts := map[string]int{"REQ-001": 2, "REQ-002": 1, "REQ-009": 3}
+	diff := baseline.Report{
+		Baseline: "v1.0",
+		Changed:  []baseline.Item{{ID: "REQ-001", Title: "Login", ExamplesChanged: []string{"E2"}}},
+		Added:    []baseline.Item{{ID: "REQ-003", Title: "Signup"}},
+		Removed:  []baseline.Item{{ID: "REQ-008", Title: "Untested"}, {ID: "REQ-009", Title: "Legacy"}},
+	}
+
+	report := BuildReport(specs, counts)
+	if report.BaselineMarkdown() != "" {
+		t.Error("BaselineMarkdown should be empty without a baseline")
+	}
+	report.AddBaseline(diff, counts)
+
+	if got := report.Items[0].BaselineChange; got == nil || got.Change != "changed" || strings.Join(got.Examples, ",") != "E2" {
+		t.Errorf("REQ-001 baseline change = %+v", got)
+	}
+	if report.Items[1].BaselineChange != nil {
+		t.Errorf("unchanged REQ-002 flagged: %+v", report.Items[1].BaselineChange)
+	}
+	if len(report.BaselineRemoved) != 1 || report.BaselineRemoved[0] != (BaselineRemoved{ID: "REQ-009", Title: "Legacy", Tests: 3}) {
+		t.Errorf("BaselineRemoved = %+v, want only the tested REQ-009", report.BaselineRemoved)
+	}
+
+	md := report.ToMarkdown()
+	for _, want := range []string{
+		"## Changed Since Baseline v1.0",
+		"| REQ-001 | Login | changed | E2 | 2 |",
+		"| REQ-003 | Signup | added |  | 0 |",
+		"| REQ-009 | Legacy | removed |  | 3 |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected %q in markdown:\n%s", want, md)
+		}
+	}
+}
+
+func TestCountTests_CustomScheme(t *testing.T) {
+	spec.SetIDScheme(spec.NewIDScheme([]string{"AUTH", "BILL"}, 3, ""))
+	t.Cleanup(func() { spec.SetIDScheme(spec.DefaultIDScheme()) })
+
+	tmpDir := t.TempDir()
+	content := `describe("AUTH-012: Login", () => {
+  it("AUTH-012 E1: locks", () => {})
+  it("BILL-003 E2: invoices", () => {})
+  it("REQ-001 E1: legacy", () => {})
+})`
+	if err := os.WriteFile(filepath.Join(tmpDir, "sample.test.ts"), []byte(content), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	counts, err := CountTestsByReq(tmpDir)
+	if err != nil {
+		t.Fatalf("CountTestsByReq error: %v", err)
+	}
+	if counts["AUTH-012"] != 1 || counts["BILL-003"] != 1 || counts["REQ-001"] != 0 {
+		t.Errorf("counts = %v", counts)
+	}
+	exampleCounts, err := CountTestsByExample(tmpDir)
+	if err != nil {
+		t.Fatalf("CountTestsByExample error: %v", err)
+	}
+	if exampleCounts["BILL-003"]["E2"] != 1 {
+		t.Errorf("example counts = %v", exampleCounts)
+	}
+
+	report := BuildReport([]*spec.Spec{{ID: "BILL-003", Title: "b"}, {ID: "AUTH-012", Title: "a"}}, counts)
+	if report.Items[0].ID != "AUTH-012" {
+		t.Errorf("items should follow prefix order, got %s first", report.Items[0].ID)
+	}
+}
@@ -0,0 +1,40 @@
+package yamlnode
+
+import "testing"
+
+func TestDetectIndent(t *testing.T) {
+	tests := map[string]int{
+		"id: REQ-001\n":                         4,
+		"depends:\n  - REQ-002\n":               2,
+		"depends:\n    - REQ-002\n# x\n":        4,
+		"examples:\n  - id: E1\n    given: a\n": 2,
+	}
+	for in, want := range tests {
+		if got := DetectIndent([]byte(in)); got != want {
+			t.Errorf("DetectIndent(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestSetFirst(t *testing.T) {
+	root := Parse([]byte("# header\nid: REQ-001\ntitle: Login\n"))
+	if root == nil {
+		t.Fatal("Parse returned nil")
+	}
+	m := root.Content[0]
+	SetFirst(m, "version", Scalar("!!int", "2"))
+	SetFirst(m, "title", Scalar("!!str", "Sign in"))
+	data, err := Encode(root, 2)
+	if err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	if string(data) != "# header\nversion: 2\nid: REQ-001\ntitle: Sign in\n" {
+		t.Errorf("encoded = %q", data)
+	}
+	if Get(m, "id").Value != "REQ-001" || Get(m, "missing") != nil {
+		t.Error("Get returned the wrong node")
+	}
+	if Parse([]byte("- a\n")) != nil {
+		t.Error("Parse should reject a top-level sequence")
+	}
+}